@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/kubewarden/k8s-objects-generator/split"
+	"github.com/pkg/errors"
+)
+
+// Compares the Go API of two generated trees. Breaking changes cause a
+// failure when the two trees target the same Kubernetes minor release
+func runAPIDiff(args []string) error {
+	var oldRoot, newRoot, outputFormat string
+	var strict bool
+
+	flags := flag.NewFlagSet("api-diff", flag.ExitOnError)
+	flags.StringVar(&oldRoot, "old", "", "Root directory of the previously generated code")
+	flags.StringVar(&newRoot, "new", "", "Root directory of the newly generated code")
+	flags.StringVar(&outputFormat, "output", "text", "Output format: `text` or `json`")
+	flags.BoolVar(&strict, "strict", false, "Fail on breaking changes even when the Kubernetes minor version changed")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if oldRoot == "" || newRoot == "" {
+		return fmt.Errorf("both `-old` and `-new` flags must be provided")
+	}
+	if outputFormat != "text" && outputFormat != "json" {
+		return fmt.Errorf("unknown output format %s", outputFormat)
+	}
+
+	oldAPI, err := split.LoadGoAPI(oldRoot)
+	if err != nil {
+		return errors.Wrapf(err, "cannot load Go API of %s", oldRoot)
+	}
+	newAPI, err := split.LoadGoAPI(newRoot)
+	if err != nil {
+		return errors.Wrapf(err, "cannot load Go API of %s", newRoot)
+	}
+
+	changes := split.DiffGoAPI(oldAPI, newAPI)
+
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(changes)
+	} else {
+		err = split.WriteGoAPIChanges(os.Stdout, changes)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !split.HasBreakingGoAPIChanges(changes) {
+		return nil
+	}
+
+	if strict || isPatchLevelRegeneration(oldRoot, newRoot) {
+		return fmt.Errorf("breaking changes detected")
+	}
+	log.Print("breaking changes detected, allowed because the Kubernetes minor version changed")
+
+	return nil
+}
+
+// Returns true when both trees have been generated for the same Kubernetes
+// minor release. Unknown versions are considered to be the same release.
+func isPatchLevelRegeneration(oldRoot, newRoot string) bool {
+	oldVersion, oldErr := readKubernetesVersion(oldRoot)
+	newVersion, newErr := readKubernetesVersion(newRoot)
+	if oldErr != nil || newErr != nil {
+		return true
+	}
+
+	return oldVersion.Major == newVersion.Major && oldVersion.Minor == newVersion.Minor
+}
+
+func readKubernetesVersion(root string) (semver.Version, error) {
+	data, err := os.ReadFile(filepath.Join(root, "KUBERNETES_VERSION"))
+	if err != nil {
+		return semver.Version{}, err
+	}
+
+	return semver.ParseTolerant(strings.TrimSpace(string(data)))
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kubewarden/k8s-objects-generator/split"
+	"github.com/pkg/errors"
+)
+
+// Writes the Markdown changelog of a release of the generated objects
+func runChangelog(args []string) error {
+	var root, previousRoot, outputFile string
+
+	flags := flag.NewFlagSet("changelog", flag.ExitOnError)
+	flags.StringVar(&root, "root", "", "Root directory of the generated code")
+	flags.StringVar(&previousRoot, "previous-root", "", "Root directory of the code generated for the previous release")
+	flags.StringVar(&outputFile, "o", "", "File where the changelog is written, defaults to the standard output")
+	planOptions := addPlanFlags(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if root == "" {
+		return fmt.Errorf("the `-root` flag must be provided")
+	}
+
+	plan, swaggerData, err := loadGeneratedTreePlan(root, planOptions)
+	if err != nil {
+		return err
+	}
+
+	var diff *split.PlanDiff
+	if previousRoot != "" {
+		previousPlan, _, err := loadGeneratedTreePlan(previousRoot, planOptions)
+		if err != nil {
+			return err
+		}
+		planDiff := split.DiffPlans(previousPlan, plan)
+		diff = &planDiff
+	}
+
+	changelog, err := split.RenderChangelog(
+		split.NewChangelogData(plan.KubernetesVersion, version, swaggerData, diff))
+	if err != nil {
+		return errors.Wrapf(err, "cannot render changelog")
+	}
+
+	if outputFile == "" {
+		_, err = os.Stdout.WriteString(changelog)
+		return err
+	}
+
+	return os.WriteFile(outputFile, []byte(changelog), 0644)
+}
+
+// Builds the refactoring plan of the swagger file saved inside of a
+// generated tree, the Kubernetes version is read from the tree too. The plan
+// flags must be the ones used to generate the tree
+func loadGeneratedTreePlan(root string, planOptions *planFlags) (*split.RefactoringPlan, []byte, error) {
+	swaggerFile := filepath.Join(root, "swagger.json")
+	swaggerData, err := os.ReadFile(swaggerFile)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "cannot read swagger file %s", swaggerFile)
+	}
+
+	plan, err := loadRefactoringPlan(swaggerFile, "", planOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	kubernetesVersionFile := filepath.Join(root, "KUBERNETES_VERSION")
+	kubernetesVersion, err := os.ReadFile(kubernetesVersionFile)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "cannot read %s", kubernetesVersionFile)
+	}
+	plan.KubernetesVersion = strings.TrimSpace(string(kubernetesVersion))
+
+	return plan, swaggerData, nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/kubewarden/k8s-objects-generator/split"
+	"github.com/pkg/errors"
+)
+
+// Reports the API differences between two swagger files
+func runDiff(args []string) error {
+	var oldSwaggerFile, oldKubeVersion, newSwaggerFile, newKubeVersion, outputFormat string
+
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	flags.StringVar(&oldSwaggerFile, "old-f", "", "The swagger file of the old version")
+	flags.StringVar(&oldKubeVersion, "old-kube-version", "", "Fetch the swagger file of the old Kubernetes version")
+	flags.StringVar(&newSwaggerFile, "new-f", "", "The swagger file of the new version")
+	flags.StringVar(&newKubeVersion, "new-kube-version", "", "Fetch the swagger file of the new Kubernetes version")
+	flags.StringVar(&outputFormat, "output", "text", "Output format: `text` or `json`")
+	planOptions := addPlanFlags(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if outputFormat != "text" && outputFormat != "json" {
+		return fmt.Errorf("unknown output format %s", outputFormat)
+	}
+
+	oldPlan, err := loadRefactoringPlan(oldSwaggerFile, oldKubeVersion, planOptions)
+	if err != nil {
+		return errors.Wrapf(err, "cannot process old swagger file")
+	}
+
+	newPlan, err := loadRefactoringPlan(newSwaggerFile, newKubeVersion, planOptions)
+	if err != nil {
+		return errors.Wrapf(err, "cannot process new swagger file")
+	}
+
+	diff := split.DiffPlans(oldPlan, newPlan)
+
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(diff)
+	}
+
+	return diff.WriteText(os.Stdout)
+}
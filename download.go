@@ -5,6 +5,7 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"os"
 
 	"github.com/blang/semver/v4"
 	"github.com/pkg/errors"
@@ -15,6 +16,32 @@ type SwaggerData struct {
 	KubernetesVersion string
 }
 
+// Loads the swagger file either from the local filesystem or by downloading
+// the one of the given Kubernetes version
+func LoadSwagger(swaggerFile, kubeVersion string) (*SwaggerData, error) {
+	if swaggerFile != "" && kubeVersion != "" {
+		return nil, fmt.Errorf("`-f` and `-kube-version` flags cannot be used at the same time")
+	}
+
+	if kubeVersion != "" {
+		return DownloadSwagger(kubeVersion)
+	}
+
+	if swaggerFile == "" {
+		return nil, fmt.Errorf("either `-f` or `-kube-version` flag must be provided")
+	}
+
+	data, err := os.ReadFile(swaggerFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read swagger file %s", swaggerFile)
+	}
+
+	return &SwaggerData{
+		Data:              data,
+		KubernetesVersion: "unknown",
+	}, nil
+}
+
 // Downloads the swagger file for the Kubernetes version specified by the user
 func DownloadSwagger(kubeVersion string) (*SwaggerData, error) {
 	version, err := semver.ParseTolerant(kubeVersion)
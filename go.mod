@@ -6,19 +6,19 @@ require (
 	github.com/blang/semver/v4 v4.0.0
 	github.com/deckarep/golang-set v1.8.0
 	github.com/go-openapi/spec v0.20.6
+	github.com/go-openapi/swag v0.21.1
 	github.com/heimdalr/dag v1.1.1
+	github.com/mailru/easyjson v0.7.6
 	github.com/pkg/errors v0.9.1
+	gopkg.in/yaml.v2 v2.4.0
 )
 
 require (
 	github.com/emirpasic/gods v1.18.1 // indirect
 	github.com/go-openapi/jsonpointer v0.19.5 // indirect
 	github.com/go-openapi/jsonreference v0.20.0 // indirect
-	github.com/go-openapi/swag v0.21.1 // indirect
 	github.com/google/uuid v1.1.2 // indirect
 	github.com/josharian/intern v1.0.0 // indirect
-	github.com/mailru/easyjson v0.7.6 // indirect
 	github.com/stretchr/testify v1.7.0 // indirect
-	gopkg.in/yaml.v2 v2.4.0 // indirect
 	gopkg.in/yaml.v3 v3.0.0 // indirect
 )
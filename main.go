@@ -8,42 +8,79 @@ import (
 	"path/filepath"
 
 	"github.com/kubewarden/k8s-objects-generator/split"
+	"github.com/pkg/errors"
 )
 
 //go:embed LICENSE
 var LICENSE string
 
+// Version of the generator, set at build time by goreleaser
+var version = "dev"
+
+// Commands that can be invoked as first argument of the program, e.g.
+// `k8s-objects-generator sample -type io.k8s.api.core.v1.Pod`.
+// The swagger files are split and turned into Go code when no command is given.
+var commands = map[string]func(args []string) error{
+	"api-diff":  runAPIDiff,
+	"changelog": runChangelog,
+	"diff":      runDiff,
+	"sample":    runSample,
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		if command, found := commands[os.Args[1]]; found {
+			if err := command(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+	}
+
+	generate()
+}
+
+// Builds the refactoring plan of the swagger file referenced either by
+// `swaggerFile` or by `kubeVersion`
+func loadRefactoringPlan(swaggerFile, kubeVersion string, planOptions *planFlags) (*split.RefactoringPlan, error) {
+	swaggerData, err := LoadSwagger(swaggerFile, kubeVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	splitter, err := split.NewSplitterFromData(swaggerData.Data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot decode swagger file")
+	}
+
+	refactoringPlan, err := planOptions.refactoringPlan(&splitter)
+	if err != nil {
+		return nil, err
+	}
+
+	if swaggerData.KubernetesVersion != "unknown" {
+		refactoringPlan.KubernetesVersion = swaggerData.KubernetesVersion
+	}
+
+	return refactoringPlan, nil
+}
+
+func generate() {
 	var swaggerFile, kubeVersion, outputDir, gitRepo string
-	var swaggerData *SwaggerData
-	var err error
+	var generateTests bool
 
 	flag.StringVar(&swaggerFile, "f", "", "The swagger file to process")
 	flag.StringVar(&kubeVersion, "kube-version", "", "Fetch the swagger file of the specified Kubernetes version")
 	flag.StringVar(&outputDir, "o", "./k8s-objects", "The root directory where the files will be generated")
 	flag.StringVar(&gitRepo, "repo", "github.com/kubewarden/k8s-objects", "The repository where the generated files are going to be published")
+	flag.BoolVar(&generateTests, "generate-tests", false, "Generate JSON round-trip tests for all the types")
+	planOptions := addPlanFlags(flag.CommandLine)
 
 	flag.Parse()
 
-	if swaggerFile != "" && kubeVersion != "" {
-		log.Fatal("`-f` and `-kube-version` flags cannot be used at the same time")
-	}
-
-	if kubeVersion != "" {
-		swaggerData, err = DownloadSwagger(kubeVersion)
-		if err != nil {
-			log.Fatal(err)
-		}
-	}
-	if swaggerFile != "" {
-		data, err := os.ReadFile(swaggerFile)
-		if err != nil {
-			log.Fatalf("cannot read swagger file %s: %v", swaggerFile, err)
-		}
-		swaggerData = &SwaggerData{
-			Data:              data,
-			KubernetesVersion: "unknown",
-		}
+	swaggerData, err := LoadSwagger(swaggerFile, kubeVersion)
+	if err != nil {
+		log.Fatal(err)
 	}
 
 	outputDir, err = filepath.Abs(outputDir)
@@ -88,7 +125,7 @@ func main() {
 		log.Fatal(err)
 	}
 
-	refactoringPlan, err := splitter.ComputeRefactoringPlan()
+	refactoringPlan, err := planOptions.refactoringPlan(&splitter)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -100,4 +137,76 @@ func main() {
 	if err := split.GenerateEasyjsonFiles(project, refactoringPlan); err != nil {
 		log.Fatal(err)
 	}
+
+	log.Print("Generating IntOrString type")
+	if err := split.GenerateIntOrString(project, refactoringPlan); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Print("Generating Quantity type")
+	if err := split.GenerateQuantity(project, refactoringPlan); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Print("Generating type override checks")
+	if err := split.GenerateTypeOverrideChecks(project, refactoringPlan); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Print("Generating GroupVersionKind helpers")
+	if err := split.GenerateGroupVersionKindFiles(project, refactoringPlan); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Print("Generating Object interface")
+	if err := split.GenerateObjectInterface(project, refactoringPlan); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Print("Generating PodSpec accessors")
+	if err := split.GeneratePodSpecAccessors(project, refactoringPlan); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Print("Generating patch metadata")
+	if err := split.GeneratePatchMetadata(project, refactoringPlan); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Print("Generating getters")
+	if err := split.GenerateGetters(project, refactoringPlan); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Print("Generating DeepCopy methods")
+	if err := split.GenerateDeepCopy(project, refactoringPlan); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Print("Generating Equal methods")
+	if err := split.GenerateEqual(project, refactoringPlan); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Print("Generating JSON patch diffs")
+	if err := split.GenerateJSONPatchDiffs(project, refactoringPlan); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Print("Generating kind registry")
+	if err := split.GenerateRegistry(project, refactoringPlan); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Print("Generating embedded object helpers")
+	if err := split.GenerateEmbeddedObjectHelpers(project, refactoringPlan); err != nil {
+		log.Fatal(err)
+	}
+
+	if generateTests {
+		log.Print("Generating round-trip tests")
+		if err := split.GenerateRoundTripTests(project, refactoringPlan); err != nil {
+			log.Fatal(err)
+		}
+	}
 }
@@ -9,6 +9,15 @@ set -e
 
 OUT_DIR=~/k8s-data-types
 GIT_DIR=~/checkout/kubernetes/kubewarden/k8s-objects
+GENERATOR="$(readlink -f ./k8s-objects-generator)"
+
+# Flags passed to the generator
+GENERATOR_FLAGS=()
+# Flags deciding the packages and the types of the generated code, they are
+# passed also to the changelog command, which rejects the other flags
+PLAN_FLAG_NAMES=(-type-overrides -import-alias-strategy -import-aliases
+  -package-mapping -type-names -duplicate-type-strategy -cycle-strategy)
+PLAN_FLAGS=()
 
 while [[ $# -gt 0 ]]; do
   case $1 in
@@ -17,6 +26,11 @@ while [[ $# -gt 0 ]]; do
       shift # past argument
       shift # past value
       ;;
+    --)
+      shift # past separator
+      GENERATOR_FLAGS=("$@")
+      break
+      ;;
     -*|--*)
       echo "Unknown option $1"
       exit 1
@@ -28,10 +42,30 @@ while [[ $# -gt 0 ]]; do
   esac
 done
 
-if [ -z "$GIT_COMMIT_MSG_FILE" ]; then
-  echo "git commit message must be provided via the -m flag"
-  exit 1
-fi
+# Picks the plan flags out of the generator ones. All the plan flags take a
+# value, either as `-flag=value` or as the next argument
+ARGS=("${GENERATOR_FLAGS[@]}")
+for ((i = 0; i < ${#ARGS[@]}; i++)); do
+  # both -flag and --flag are accepted
+  NAME="${ARGS[i]%%=*}"
+  NAME="${NAME#-}"
+  NAME="-${NAME#-}"
+  for PLAN_FLAG_NAME in "${PLAN_FLAG_NAMES[@]}"; do
+    if [ "$NAME" = "$PLAN_FLAG_NAME" ]; then
+      PLAN_FLAGS+=("${ARGS[i]}")
+      if [[ "${ARGS[i]}" != *=* ]]; then
+        i=$((i + 1))
+        PLAN_FLAGS+=("${ARGS[i]}")
+      fi
+      break
+    fi
+  done
+done
+
+# When no commit message is provided via the -m flag, a changelog is
+# generated for each release and used as commit message
+CHANGELOG_FILE="$(mktemp)"
+trap 'rm -f "$CHANGELOG_FILE"' EXIT
 
 
 for KUBEMINOR in {14..24}
@@ -40,7 +74,7 @@ do
   echo PROCESSING KUBERNETES 1.$KUBEMINOR
   echo ==================================
 
-  ./k8s-objects-generator -kube-version "1.$KUBEMINOR" -o "$OUT_DIR"
+  "$GENERATOR" -kube-version "1.$KUBEMINOR" -o "$OUT_DIR" "${GENERATOR_FLAGS[@]}"
 
   BRANCH=release-1.$KUBEMINOR
 
@@ -59,9 +93,24 @@ do
   fi
   git reset --hard
   git clean -fd
+
+  COMMIT_MSG_FILE="$GIT_COMMIT_MSG_FILE"
+  if [ -z "$COMMIT_MSG_FILE" ]; then
+    PREVIOUS_ROOT_FLAGS=()
+    if [ -f "$GIT_DIR/swagger.json" ]; then
+      PREVIOUS_ROOT_FLAGS=(-previous-root "$GIT_DIR")
+    fi
+    "$GENERATOR" changelog \
+      -root "$OUT_DIR/src/github.com/kubewarden/k8s-objects" \
+      "${PREVIOUS_ROOT_FLAGS[@]}" \
+      "${PLAN_FLAGS[@]}" \
+      -o "$CHANGELOG_FILE"
+    COMMIT_MSG_FILE="$CHANGELOG_FILE"
+  fi
+
   cp -r $OUT_DIR/src/github.com/kubewarden/k8s-objects/* $GIT_DIR
   git add -- *
-  git commit -F "$GIT_COMMIT_MSG_FILE"
+  git commit -F "$COMMIT_MSG_FILE"
   git tag $GIT_TAG
   cd -
 done
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/kubewarden/k8s-objects-generator/split"
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
+)
+
+// Flags deciding how the refactoring plan is computed. They are accepted by
+// all the commands building a plan: the packages and the types they report
+// must be the ones of the generated code
+type planFlags struct {
+	importAliasStrategy   string
+	importAliasesFile     string
+	packageMappingFile    string
+	typeNamesFile         string
+	duplicateTypeStrategy string
+	cycleStrategy         string
+	typeOverridesFile     string
+}
+
+func addPlanFlags(flags *flag.FlagSet) *planFlags {
+	f := planFlags{}
+
+	flags.StringVar(&f.typeOverridesFile, "type-overrides", "", "JSON file mapping definition IDs to the Go types replacing them")
+	flags.StringVar(&f.importAliasStrategy, "import-alias-strategy", swagger_helpers.FULL_ALIAS_STRATEGY,
+		"How imported packages are named: 'full' (apimachinery_pkg_apis_meta_v1) or 'short' (metav1)")
+	flags.StringVar(&f.importAliasesFile, "import-aliases", "", "JSON file mapping packages to the aliases used to import them")
+	flags.StringVar(&f.packageMappingFile, "package-mapping", "", "JSON file with the rules turning definition IDs into package paths")
+	flags.StringVar(&f.cycleStrategy, "cycle-strategy", split.FAIL_CYCLE_STRATEGY,
+		"How dependency cycles between packages are handled: 'fail', 'merge' the packages or break the references with a 'raw-message'")
+	flags.StringVar(&f.typeNamesFile, "type-names", "", "JSON file mapping definition IDs to the names of the Go types generated for them")
+	flags.StringVar(&f.duplicateTypeStrategy, "duplicate-type-strategy", swagger_helpers.FAIL_DUPLICATE_TYPE_STRATEGY,
+		"How definitions mapped to the same Go type are handled: 'fail' or 'prefix' the type names with the chunk of the IDs that differs")
+
+	return &f
+}
+
+// Returns the naming options selected by the flags
+func (f *planFlags) namingOptions() (swagger_helpers.NamingOptions, error) {
+	options := swagger_helpers.NamingOptions{
+		ImportAliases:         swagger_helpers.ImportAliasStrategy{Name: f.importAliasStrategy},
+		DuplicateTypeStrategy: f.duplicateTypeStrategy,
+	}
+
+	if f.importAliasesFile != "" {
+		customAliases, err := swagger_helpers.LoadCustomImportAliases(f.importAliasesFile)
+		if err != nil {
+			return options, err
+		}
+		options.ImportAliases.Custom = customAliases
+	}
+
+	if f.packageMappingFile != "" {
+		rules, err := swagger_helpers.LoadPackageMappingRules(f.packageMappingFile)
+		if err != nil {
+			return options, err
+		}
+		options.PackageMappingRules = rules
+	}
+
+	if f.typeNamesFile != "" {
+		typeNames, err := swagger_helpers.LoadTypeNames(f.typeNamesFile)
+		if err != nil {
+			return options, err
+		}
+		options.TypeNames = typeNames
+	}
+
+	return options, nil
+}
+
+// Computes the refactoring plan of the swagger file handled by the splitter,
+// with its cycles resolved and its types overridden
+func (f *planFlags) refactoringPlan(splitter *split.Splitter) (*split.RefactoringPlan, error) {
+	options, err := f.namingOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	refactoringPlan, err := splitter.ComputeRefactoringPlan(options)
+	if err != nil {
+		return nil, err
+	}
+
+	// the references to the overridden types do not close cycles when the
+	// types are defined by other modules
+	if f.typeOverridesFile != "" {
+		overrides, err := swagger_helpers.LoadTypeOverrides(f.typeOverridesFile)
+		if err != nil {
+			return nil, err
+		}
+		if err := refactoringPlan.ApplyTypeOverrides(overrides); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := refactoringPlan.ResolveCycles(f.cycleStrategy); err != nil {
+		return nil, err
+	}
+
+	if err := refactoringPlan.CheckImportAliases(); err != nil {
+		return nil, err
+	}
+
+	return refactoringPlan, nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Prints a sample instance of a Kubernetes object, useful when writing the
+// unit tests of a policy
+func runSample(args []string) error {
+	var swaggerFile, kubeVersion, typeName, outputFormat string
+	var minimal bool
+	var seed int64
+
+	flags := flag.NewFlagSet("sample", flag.ExitOnError)
+	flags.StringVar(&swaggerFile, "f", "", "The swagger file to process")
+	flags.StringVar(&kubeVersion, "kube-version", "", "Fetch the swagger file of the specified Kubernetes version")
+	flags.StringVar(&typeName, "type", "", "Definition ID (e.g. `io.k8s.api.core.v1.Pod`) or Go type (e.g. `api/core/v1.Pod`) of the object")
+	flags.BoolVar(&minimal, "minimal", false, "Populate only the required fields")
+	flags.Int64Var(&seed, "seed", 0, "Seed used to generate random values, fixed placeholder values are used when 0")
+	flags.StringVar(&outputFormat, "output", "json", "Output format: `json` or `yaml`")
+	planOptions := addPlanFlags(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if typeName == "" {
+		return fmt.Errorf("the `-type` flag must be provided")
+	}
+	if outputFormat != "json" && outputFormat != "yaml" {
+		return fmt.Errorf("unknown output format %s", outputFormat)
+	}
+
+	refactoringPlan, err := loadRefactoringPlan(swaggerFile, kubeVersion, planOptions)
+	if err != nil {
+		return err
+	}
+
+	definition, err := refactoringPlan.LookupDefinition(typeName)
+	if err != nil {
+		return err
+	}
+
+	options := swagger_helpers.SampleOptions{
+		Minimal: minimal,
+	}
+	if seed != 0 {
+		options.Rand = rand.New(rand.NewSource(seed))
+	}
+
+	builder := swagger_helpers.NewSampleBuilder(refactoringPlan.Definitions, options)
+	sample, err := builder.Build(definition.ID)
+	if err != nil {
+		return err
+	}
+
+	var output []byte
+	if outputFormat == "yaml" {
+		output, err = yaml.Marshal(sample)
+	} else {
+		output, err = json.MarshalIndent(sample, "", "  ")
+		output = append(output, '\n')
+	}
+	if err != nil {
+		return errors.Wrapf(err, "cannot encode sample of %s", definition.ID)
+	}
+
+	_, err = os.Stdout.Write(output)
+	return err
+}
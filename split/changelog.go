@@ -0,0 +1,87 @@
+package split
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"text/template"
+)
+
+const CHANGELOG_TEMPLATE = `Kubernetes objects for Kubernetes {{ .KubernetesVersion }}
+
+* Kubernetes version: ` + "`{{ .KubernetesVersion }}`" + `
+* Generator version: ` + "`{{ .GeneratorVersion }}`" + `
+* Swagger file SHA256: ` + "`{{ .SpecHash }}`" + `
+{{ with .Diff }}
+## Changes since Kubernetes {{ .OldKubernetesVersion }}
+{{ if .IsEmpty }}
+No API changes.
+{{ end }}
+{{- with .AddedPackages }}
+### Added packages
+
+{{ range . }}* ` + "`{{ . }}`" + `
+{{ end }}{{ end }}
+{{- with .RemovedPackages }}
+### Removed packages
+
+{{ range . }}* ` + "`{{ . }}`" + `
+{{ end }}{{ end }}
+{{- with .AddedTypes }}
+### Added types
+
+{{ range . }}* ` + "`{{ . }}`" + `
+{{ end }}{{ end }}
+{{- with .RemovedTypes }}
+### Removed types
+
+{{ range . }}* ` + "`{{ . }}`" + `
+{{ end }}{{ end }}
+{{- with .ChangedTypes }}
+### Changed types
+
+{{ range . }}* ` + "`{{ .Type }}`" + `
+{{ range .AddedProperties }}  * added ` + "`{{ .Name }}`" + `
+{{ end }}{{ range .RemovedProperties }}  * removed ` + "`{{ .Name }}`" + `
+{{ end }}{{ range .ChangedProperties }}{{ if ne .OldType .NewType }}  * ` + "`{{ .Name }}`" + ` changed type from ` + "`{{ .OldType }}`" + ` to ` + "`{{ .NewType }}`" + `
+{{ end }}{{ if ne .OldRequired .NewRequired }}  * ` + "`{{ .Name }}`" + ` is {{ if .NewRequired }}now{{ else }}no longer{{ end }} required
+{{ end }}{{ end }}{{ end }}{{ end }}
+{{- else }}
+Initial release.
+{{ end }}`
+
+// Information rendered inside of the changelog of a release of the
+// generated objects
+type ChangelogData struct {
+	KubernetesVersion string
+	GeneratorVersion  string
+	// SHA256 of the swagger file the objects are generated from
+	SpecHash string
+	// API changes since the previous release, nil for the first release
+	Diff *PlanDiff
+}
+
+func NewChangelogData(kubernetesVersion, generatorVersion string, swaggerData []byte, diff *PlanDiff) ChangelogData {
+	return ChangelogData{
+		KubernetesVersion: kubernetesVersion,
+		GeneratorVersion:  generatorVersion,
+		SpecHash:          fmt.Sprintf("%x", sha256.Sum256(swaggerData)),
+		Diff:              diff,
+	}
+}
+
+// Renders the changelog using the Markdown format. The first line is a
+// plain title, which makes the changelog usable also as git commit message
+func RenderChangelog(data ChangelogData) (string, error) {
+	changelogTemplate, err := template.New("changelog").Parse(CHANGELOG_TEMPLATE)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := changelogTemplate.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
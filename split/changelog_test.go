@@ -0,0 +1,72 @@
+package split
+
+import (
+	"strings"
+	"testing"
+
+	openapi_spec "github.com/go-openapi/spec"
+)
+
+func TestRenderChangelog(t *testing.T) {
+	oldPlan := newTestPlan(t, "1.24.0", openapi_spec.Definitions{
+		"io.k8s.api.core.v1.Container": {
+			SchemaProps: openapi_spec.SchemaProps{
+				Properties: map[string]openapi_spec.Schema{
+					"image": stringProperty(""),
+				},
+			},
+		},
+	})
+	newPlan := newTestPlan(t, "1.25.0", openapi_spec.Definitions{
+		"io.k8s.api.core.v1.Container": {
+			SchemaProps: openapi_spec.SchemaProps{
+				Required: []string{"image"},
+				Properties: map[string]openapi_spec.Schema{
+					"image":      stringProperty(""),
+					"workingDir": stringProperty(""),
+				},
+			},
+		},
+		"io.k8s.api.batch.v1.Job": {
+			SchemaProps: openapi_spec.SchemaProps{
+				Properties: map[string]openapi_spec.Schema{
+					"kind": stringProperty(""),
+				},
+			},
+		},
+	})
+	diff := DiffPlans(oldPlan, newPlan)
+
+	changelog, err := RenderChangelog(NewChangelogData("1.25.0", "v0.1.0", []byte("{}"), &diff))
+	if err != nil {
+		t.Fatalf("cannot render changelog: %v", err)
+	}
+
+	if !strings.HasPrefix(changelog, "Kubernetes objects for Kubernetes 1.25.0\n\n") {
+		t.Errorf("wrong changelog title:\n%s", changelog)
+	}
+
+	expectedLines := []string{
+		"* Generator version: `v0.1.0`",
+		"* Swagger file SHA256: `44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a`",
+		"## Changes since Kubernetes 1.24.0",
+		"* `api/batch/v1`",
+		"* `api/batch/v1.Job`",
+		"* `api/core/v1.Container`",
+		"  * added `workingDir`",
+		"  * `image` is now required",
+	}
+	for _, line := range expectedLines {
+		if !strings.Contains(changelog, line+"\n") {
+			t.Errorf("cannot find line %q inside of changelog:\n%s", line, changelog)
+		}
+	}
+
+	initialChangelog, err := RenderChangelog(NewChangelogData("1.24.0", "v0.1.0", []byte("{}"), nil))
+	if err != nil {
+		t.Fatalf("cannot render changelog: %v", err)
+	}
+	if !strings.Contains(initialChangelog, "Initial release.") {
+		t.Errorf("first release not detected:\n%s", initialChangelog)
+	}
+}
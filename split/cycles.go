@@ -0,0 +1,420 @@
+package split
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	openapi_spec "github.com/go-openapi/spec"
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
+	"github.com/pkg/errors"
+)
+
+// Strategies used to handle the dependency cycles between packages
+const (
+	// Report the cycles and stop
+	FAIL_CYCLE_STRATEGY = "fail"
+
+	// Move the definitions of the packages depending on each other into one
+	// of them
+	MERGE_CYCLE_STRATEGY = "merge"
+
+	// Replace the references closing the cycles with raw JSON documents
+	RAW_MESSAGE_CYCLE_STRATEGY = "raw-message"
+)
+
+// A reference from a definition to a definition of another package
+type PackageReference struct {
+	// ID of the definition holding the reference
+	From string
+	// Position of the reference inside of the definition, see
+	// swagger_helpers.SchemaLocation
+	Path string
+	// ID of the referenced definition
+	To string
+}
+
+func (r PackageReference) String() string {
+	if r.Path == "" {
+		return fmt.Sprintf("%s -> %s", r.From, r.To)
+	}
+	return fmt.Sprintf("%s: %s -> %s", r.From, r.Path, r.To)
+}
+
+// Packages depending on each other
+type PackageCycle struct {
+	// All the strongly connected packages, sorted
+	Packages []string
+
+	// One of the loops between the packages, it starts and ends with the
+	// same package
+	Loop []string
+
+	// The references between definitions that form Loop
+	References []PackageReference
+}
+
+func (c PackageCycle) String() string {
+	var report strings.Builder
+
+	report.WriteString(strings.Join(c.Loop, " -> "))
+	for _, reference := range c.References {
+		fmt.Fprintf(&report, "\n    %s", reference)
+	}
+	if len(c.Packages) > len(c.Loop)-1 {
+		fmt.Fprintf(&report, "\n    strongly connected packages: %s", strings.Join(c.Packages, ", "))
+	}
+
+	return report.String()
+}
+
+func newCycleError(cycles []PackageCycle) error {
+	var report strings.Builder
+
+	report.WriteString("dependency cycles found between packages, use either the ")
+	fmt.Fprintf(&report, "%s or the %s cycle strategy to solve them:", MERGE_CYCLE_STRATEGY, RAW_MESSAGE_CYCLE_STRATEGY)
+	for _, cycle := range cycles {
+		fmt.Fprintf(&report, "\n  %s", cycle)
+	}
+
+	return errors.New(report.String())
+}
+
+// Returns the references between definitions of different packages, indexed
+// by the package holding them and by the referenced package. The references
+// to the definitions replaced by types of other modules are left out: the
+// generated code imports these modules instead of the referenced package
+func (r *RefactoringPlan) packageReferences() map[string]map[string][]PackageReference {
+	references := make(map[string]map[string][]PackageReference)
+
+	ids := []string{}
+	for id := range r.Definitions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		definition := r.Definitions[id]
+		for _, reference := range definition.References() {
+			target, found := r.Definitions[reference.ID]
+			if !found || target.PackageName == definition.PackageName {
+				continue
+			}
+			if override, overridden := r.Interfaces.Override("", target.PackageName, target.TypeName); overridden && override.Import != "" {
+				continue
+			}
+
+			if _, known := references[definition.PackageName]; !known {
+				references[definition.PackageName] = make(map[string][]PackageReference)
+			}
+			references[definition.PackageName][target.PackageName] = append(
+				references[definition.PackageName][target.PackageName],
+				PackageReference{From: id, Path: reference.Path, To: reference.ID})
+		}
+	}
+
+	return references
+}
+
+// Returns, sorted by name, the packages the given one depends on
+// * `references`: the references computed by packageReferences
+func sortedDependencies(references map[string]map[string][]PackageReference, pkgName string) []string {
+	dependencies := []string{}
+	for dependency := range references[pkgName] {
+		dependencies = append(dependencies, dependency)
+	}
+	sort.Strings(dependencies)
+
+	return dependencies
+}
+
+// Finds the groups of packages that depend on each other, which are the
+// strongly connected components of the dependency graph made by more than
+// one package
+func (r *RefactoringPlan) PackageCycles() []PackageCycle {
+	references := r.packageReferences()
+	pkgNames := []string{}
+	for pkgName := range r.Packages {
+		pkgNames = append(pkgNames, pkgName)
+	}
+	sort.Strings(pkgNames)
+
+	// Tarjan's algorithm
+	index := make(map[string]int)
+	lowLink := make(map[string]int)
+	onStack := make(map[string]bool)
+	stack := []string{}
+	components := [][]string{}
+
+	var connect func(pkgName string)
+	connect = func(pkgName string) {
+		index[pkgName] = len(index)
+		lowLink[pkgName] = index[pkgName]
+		stack = append(stack, pkgName)
+		onStack[pkgName] = true
+
+		for _, dependency := range sortedDependencies(references, pkgName) {
+			if _, visited := index[dependency]; !visited {
+				connect(dependency)
+				if lowLink[dependency] < lowLink[pkgName] {
+					lowLink[pkgName] = lowLink[dependency]
+				}
+			} else if onStack[dependency] && index[dependency] < lowLink[pkgName] {
+				lowLink[pkgName] = index[dependency]
+			}
+		}
+
+		if lowLink[pkgName] != index[pkgName] {
+			return
+		}
+		component := []string{}
+		for {
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[last] = false
+			component = append(component, last)
+			if last == pkgName {
+				break
+			}
+		}
+		if len(component) > 1 {
+			sort.Strings(component)
+			components = append(components, component)
+		}
+	}
+
+	for _, pkgName := range pkgNames {
+		if _, visited := index[pkgName]; !visited {
+			connect(pkgName)
+		}
+	}
+
+	sort.Slice(components, func(i, j int) bool {
+		return components[i][0] < components[j][0]
+	})
+
+	cycles := []PackageCycle{}
+	for _, component := range components {
+		loop := shortestLoop(references, component)
+		cycle := PackageCycle{Packages: component, Loop: loop}
+		for i := 0; i < len(loop)-1; i++ {
+			cycle.References = append(cycle.References, references[loop[i]][loop[i+1]]...)
+		}
+		cycles = append(cycles, cycle)
+	}
+
+	return cycles
+}
+
+// Returns the shortest loop going from the first package of the strongly
+// connected component back to it
+func shortestLoop(references map[string]map[string][]PackageReference, component []string) []string {
+	inComponent := make(map[string]bool)
+	for _, pkgName := range component {
+		inComponent[pkgName] = true
+	}
+
+	start := component[0]
+	previous := make(map[string]string)
+	queue := []string{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, dependency := range sortedDependencies(references, current) {
+			if !inComponent[dependency] {
+				continue
+			}
+			if dependency == start {
+				loop := []string{start}
+				for pkgName := current; pkgName != start; pkgName = previous[pkgName] {
+					loop = append([]string{pkgName}, loop...)
+				}
+				return append([]string{start}, loop...)
+			}
+			if _, visited := previous[dependency]; !visited {
+				previous[dependency] = current
+				queue = append(queue, dependency)
+			}
+		}
+	}
+
+	return component
+}
+
+// Removes the dependency cycles between packages using the given strategy,
+// this must be done after applying the type overrides: the references to the
+// types of other modules cannot close a cycle
+func (r *RefactoringPlan) ResolveCycles(strategy string) error {
+	switch strategy {
+	case FAIL_CYCLE_STRATEGY, MERGE_CYCLE_STRATEGY, RAW_MESSAGE_CYCLE_STRATEGY:
+	default:
+		return fmt.Errorf("unknown cycle strategy %q, must be one of %s, %s or %s",
+			strategy, FAIL_CYCLE_STRATEGY, MERGE_CYCLE_STRATEGY, RAW_MESSAGE_CYCLE_STRATEGY)
+	}
+
+	cycles := r.PackageCycles()
+	if len(cycles) == 0 {
+		return nil
+	}
+	if strategy == FAIL_CYCLE_STRATEGY {
+		return newCycleError(cycles)
+	}
+
+	references := r.packageReferences()
+	for _, cycle := range cycles {
+		log.Printf("Solving dependency cycle %s", strings.Join(cycle.Loop, " -> "))
+
+		var err error
+		if strategy == MERGE_CYCLE_STRATEGY {
+			err = r.mergePackages(cycle)
+		} else {
+			err = r.breakCycle(cycle, references)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := r.rebuild(); err != nil {
+		return err
+	}
+	if cycles := r.PackageCycles(); len(cycles) > 0 {
+		return errors.Wrap(newCycleError(cycles), "cannot solve all the dependency cycles")
+	}
+
+	return nil
+}
+
+// Moves all the definitions of the cycle into its first package, by
+// mapping the namespaces of the other packages to it
+func (r *RefactoringPlan) mergePackages(cycle PackageCycle) error {
+	target := cycle.Packages[0]
+	typeNames := make(map[string]string)
+	namespaces := []string{}
+
+	for _, pkgName := range cycle.Packages {
+		definitions := append([]*swagger_helpers.Definition{}, r.Packages[pkgName].Definitions...)
+		sort.Slice(definitions, func(i, j int) bool {
+			return definitions[i].ID < definitions[j].ID
+		})
+
+		for _, definition := range definitions {
+			if other, taken := typeNames[definition.TypeName]; taken {
+				return fmt.Errorf("cannot merge packages %s into %s: both %s and %s define %s",
+					strings.Join(cycle.Packages, ", "), target, other, definition.ID, definition.TypeName)
+			}
+			typeNames[definition.TypeName] = definition.ID
+
+			// the type name may differ from the last chunk of the ID when
+			// the type has been renamed
+			namespace := definition.ID[:strings.LastIndex(definition.ID, ".")]
+			if pkgName != target {
+				namespaces = append(namespaces, namespace)
+			}
+		}
+	}
+	sort.Strings(namespaces)
+
+	rules := []swagger_helpers.PackageMappingRule{}
+	for i, namespace := range namespaces {
+		if i > 0 && namespaces[i-1] == namespace {
+			continue
+		}
+		rules = append(rules, swagger_helpers.PackageMappingRule{
+			Regexp:  regexp.QuoteMeta(namespace),
+			Package: target,
+		})
+	}
+	log.Printf("Merging packages %s into %s", strings.Join(cycle.Packages, ", "), target)
+
+	r.mergeRules = append(r.mergeRules, rules...)
+	return nil
+}
+
+// Replaces with raw JSON documents the references of the dependencies that
+// close the loops of the cycle. These are the back edges found by a depth
+// first visit of the packages, removing them leaves no loop
+func (r *RefactoringPlan) breakCycle(cycle PackageCycle, references map[string]map[string][]PackageReference) error {
+	inCycle := make(map[string]bool)
+	for _, pkgName := range cycle.Packages {
+		inCycle[pkgName] = true
+	}
+
+	visited := make(map[string]bool)
+	onStack := make(map[string]bool)
+
+	var visit func(pkgName string) error
+	visit = func(pkgName string) error {
+		visited[pkgName] = true
+		onStack[pkgName] = true
+		defer func() { onStack[pkgName] = false }()
+
+		for _, dependency := range sortedDependencies(references, pkgName) {
+			if !inCycle[dependency] {
+				continue
+			}
+			if onStack[dependency] {
+				for _, reference := range references[pkgName][dependency] {
+					log.Printf("Breaking reference %s", reference)
+					if err := r.Definitions[reference.From].BreakReference(reference.Path); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if !visited[dependency] {
+				if err := visit(dependency); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+
+	for _, pkgName := range cycle.Packages {
+		if !visited[pkgName] {
+			if err := visit(pkgName); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Computes again the plan from its definitions, after the package mapping
+// rules or the definitions have been changed
+func (r *RefactoringPlan) rebuild() error {
+	swagger := openapi_spec.Swagger{}
+	swagger.SwaggerProps.Swagger = r.SwaggerVersion
+	swagger.SwaggerProps.Info = &openapi_spec.Info{
+		InfoProps: openapi_spec.InfoProps{Title: "kubernetes", Version: r.KubernetesVersion},
+	}
+	swagger.Definitions = make(openapi_spec.Definitions)
+	for id, definition := range r.Definitions {
+		swagger.Definitions[id] = definition.SwaggerDefinition
+	}
+
+	options := r.options
+	options.PackageMappingRules = append(
+		append([]swagger_helpers.PackageMappingRule{}, r.mergeRules...),
+		r.options.PackageMappingRules...)
+
+	plan, err := NewRefactoringPlan(&swagger, options)
+	if err != nil {
+		return errors.Wrap(err, "cannot compute the refactoring plan again")
+	}
+	plan.options = r.options
+	plan.mergeRules = r.mergeRules
+	// the packages of the overridden definitions may have changed
+	if err := plan.ApplyTypeOverrides(r.overrides); err != nil {
+		return err
+	}
+	*r = *plan
+
+	return nil
+}
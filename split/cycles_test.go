@@ -0,0 +1,287 @@
+package split
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	mapset "github.com/deckarep/golang-set"
+	openapi_spec "github.com/go-openapi/spec"
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
+)
+
+// Widget and Gadget reference each other, Gizmo depends on both of them
+// without being part of the cycle
+func newCycleTestPlan(t *testing.T) *RefactoringPlan {
+	return newRegistryTestPlan(t, cycleTestDefinitions())
+}
+
+func cycleTestDefinitions() openapi_spec.Definitions {
+	return openapi_spec.Definitions{
+		"io.k8s.api.widgets.v1.Widget": objectSchema(map[string]openapi_spec.Schema{
+			"name":    stringProperty(""),
+			"gadgets": *openapi_spec.ArrayProperty(openapi_spec.RefProperty("#/definitions/io.k8s.api.gadgets.v1.Gadget")),
+		}),
+		"io.k8s.api.gadgets.v1.Gadget": objectSchema(map[string]openapi_spec.Schema{
+			"owner": refProperty("io.k8s.api.widgets.v1.Widget"),
+		}),
+		"io.k8s.api.gizmos.v1.Gizmo": objectSchema(map[string]openapi_spec.Schema{
+			"widget": refProperty("io.k8s.api.widgets.v1.Widget"),
+			"gadget": refProperty("io.k8s.api.gadgets.v1.Gadget"),
+		}),
+	}
+}
+
+func TestPackageCycles(t *testing.T) {
+	plan := newCycleTestPlan(t)
+
+	cycles := plan.PackageCycles()
+	if len(cycles) != 1 {
+		t.Fatalf("expected one cycle, got %+v", cycles)
+	}
+
+	expected := PackageCycle{
+		Packages: []string{"api/gadgets/v1", "api/widgets/v1"},
+		Loop:     []string{"api/gadgets/v1", "api/widgets/v1", "api/gadgets/v1"},
+		References: []PackageReference{
+			{From: "io.k8s.api.gadgets.v1.Gadget", Path: "owner", To: "io.k8s.api.widgets.v1.Widget"},
+			{From: "io.k8s.api.widgets.v1.Widget", Path: "gadgets[]", To: "io.k8s.api.gadgets.v1.Gadget"},
+		},
+	}
+	if !reflect.DeepEqual(cycles[0], expected) {
+		t.Errorf("wrong cycle:\nexpected %+v\ngot      %+v", expected, cycles[0])
+	}
+
+	_, err := plan.DependenciesGraph()
+	if err == nil {
+		t.Fatal("expected the dependency graph to be rejected")
+	}
+	expectedLines := []string{
+		"  api/gadgets/v1 -> api/widgets/v1 -> api/gadgets/v1",
+		"    io.k8s.api.gadgets.v1.Gadget: owner -> io.k8s.api.widgets.v1.Widget",
+		"    io.k8s.api.widgets.v1.Widget: gadgets[] -> io.k8s.api.gadgets.v1.Gadget",
+	}
+	for _, line := range expectedLines {
+		if !strings.Contains(err.Error(), line) {
+			t.Errorf("cannot find %q inside of the report:\n%v", line, err)
+		}
+	}
+}
+
+func TestPackageCyclesOverriddenTypes(t *testing.T) {
+	plan := newCycleTestPlan(t)
+	err := plan.ApplyTypeOverrides(swagger_helpers.TypeOverrides{
+		"io.k8s.api.widgets.v1.Widget": {Import: "github.com/example/widgets", Type: "Widget"},
+	})
+	if err != nil {
+		t.Fatalf("cannot apply overrides: %v", err)
+	}
+
+	// the generated code of gadgets imports the other module, not widgets
+	if cycles := plan.PackageCycles(); len(cycles) != 0 {
+		t.Errorf("the references to types of other modules must not close cycles: %+v", cycles)
+	}
+	if err := plan.ResolveCycles(FAIL_CYCLE_STRATEGY); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := plan.DependenciesGraph(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// types written inside of the package of the definition are still
+	// dependencies
+	plan = newCycleTestPlan(t)
+	err = plan.ApplyTypeOverrides(swagger_helpers.TypeOverrides{
+		"io.k8s.api.widgets.v1.Widget": {Type: "Widget"},
+	})
+	if err != nil {
+		t.Fatalf("cannot apply overrides: %v", err)
+	}
+	if cycles := plan.PackageCycles(); len(cycles) != 1 {
+		t.Errorf("expected one cycle, got %+v", cycles)
+	}
+}
+
+func TestPackageCyclesStronglyConnected(t *testing.T) {
+	// a -> b -> c -> a, plus a -> c
+	plan := newRegistryTestPlan(t, openapi_spec.Definitions{
+		"io.k8s.api.a.v1.A": objectSchema(map[string]openapi_spec.Schema{
+			"b": refProperty("io.k8s.api.b.v1.B"),
+			"c": refProperty("io.k8s.api.c.v1.C"),
+		}),
+		"io.k8s.api.b.v1.B": objectSchema(map[string]openapi_spec.Schema{"c": refProperty("io.k8s.api.c.v1.C")}),
+		"io.k8s.api.c.v1.C": objectSchema(map[string]openapi_spec.Schema{"a": refProperty("io.k8s.api.a.v1.A")}),
+	})
+
+	cycles := plan.PackageCycles()
+	if len(cycles) != 1 {
+		t.Fatalf("expected one cycle, got %+v", cycles)
+	}
+	if !reflect.DeepEqual(cycles[0].Loop, []string{"api/a/v1", "api/c/v1", "api/a/v1"}) {
+		t.Errorf("expected the shortest loop, got %v", cycles[0].Loop)
+	}
+	if !strings.Contains(cycles[0].String(), "strongly connected packages: api/a/v1, api/b/v1, api/c/v1") {
+		t.Errorf("all the packages of the cycle must be reported:\n%s", cycles[0])
+	}
+
+	if err := plan.ResolveCycles(RAW_MESSAGE_CYCLE_STRATEGY); err != nil {
+		t.Fatalf("cannot resolve cycles: %v", err)
+	}
+	if _, err := plan.DependenciesGraph(); err != nil {
+		t.Errorf("all the cycles must be broken: %v", err)
+	}
+}
+
+func TestResolveCyclesMerge(t *testing.T) {
+	gitRepo := "github.com/kubewarden/k8s-objects"
+	plan := newCycleTestPlan(t)
+	err := plan.ApplyTypeOverrides(swagger_helpers.TypeOverrides{
+		"io.k8s.api.widgets.v1.Widget": {Type: "Widget", Equal: true},
+	})
+	if err != nil {
+		t.Fatalf("cannot apply overrides: %v", err)
+	}
+
+	if err := plan.ResolveCycles(MERGE_CYCLE_STRATEGY); err != nil {
+		t.Fatalf("cannot resolve cycles: %v", err)
+	}
+	if _, err := plan.DependenciesGraph(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, found := plan.Packages["api/widgets/v1"]; found {
+		t.Error("the merged package must be removed")
+	}
+	for _, id := range []string{"io.k8s.api.widgets.v1.Widget", "io.k8s.api.gadgets.v1.Gadget"} {
+		if plan.Definitions[id].PackageName != "api/gadgets/v1" {
+			t.Errorf("%s has not been merged: %s", id, plan.Definitions[id].PackageName)
+		}
+	}
+	gizmo := plan.Packages["api/gizmos/v1"]
+	if !gizmo.Dependencies.Equal(mapset.NewSet("api/gadgets/v1")) {
+		t.Errorf("wrong dependencies: %v", gizmo.Dependencies)
+	}
+
+	if override, found := plan.Interfaces.Override("", "api/gadgets/v1", "Widget"); !found || !override.Equal {
+		t.Errorf("the overrides must be kept by the merge: %+v", override)
+	}
+
+	// the generators name the merged packages through the plan, the other
+	// plans are not affected by the merge
+	if packageName, _, err := plan.Naming.SplitDefinitionID("io.k8s.api.widgets.v1.Widget"); err != nil || packageName != "api/gadgets/v1" {
+		t.Errorf("the naming of the plan must map the merged package: %s %v", packageName, err)
+	}
+	if _, found := newCycleTestPlan(t).Packages["api/widgets/v1"]; !found {
+		t.Error("the merge must not change the package mapping of the other plans")
+	}
+
+	swaggerFiles, err := plan.RenderNewSwaggerFiles(gitRepo)
+	if err != nil {
+		t.Fatalf("cannot render swagger files: %v", err)
+	}
+	if !strings.Contains(swaggerFiles["api/gadgets/v1"], `"$ref":"#/definitions/Widget"`) {
+		t.Errorf("the merged types must reference each other:\n%s", swaggerFiles["api/gadgets/v1"])
+	}
+	if !strings.Contains(swaggerFiles["api/gizmos/v1"], gitRepo+"/api/gadgets/v1") ||
+		strings.Contains(swaggerFiles["api/gizmos/v1"], "api/widgets/v1") {
+		t.Errorf("the references to the merged packages must be updated:\n%s", swaggerFiles["api/gizmos/v1"])
+	}
+}
+
+func TestResolveCyclesMergeRenamedType(t *testing.T) {
+	swagger := openapi_spec.Swagger{}
+	swagger.Definitions = cycleTestDefinitions()
+	plan, err := NewRefactoringPlan(&swagger, swagger_helpers.NamingOptions{
+		TypeNames: map[string]string{"io.k8s.api.widgets.v1.Widget": "Contraption"},
+	})
+	if err != nil {
+		t.Fatalf("cannot create refactoring plan: %v", err)
+	}
+
+	if err := plan.ResolveCycles(MERGE_CYCLE_STRATEGY); err != nil {
+		t.Fatalf("cannot resolve cycles: %v", err)
+	}
+	if _, err := plan.DependenciesGraph(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	widget := plan.Definitions["io.k8s.api.widgets.v1.Widget"]
+	if widget.PackageName != "api/gadgets/v1" || widget.TypeName != "Contraption" {
+		t.Errorf("the renamed type has not been merged: %s.%s", widget.PackageName, widget.TypeName)
+	}
+	if _, found := plan.Packages["api/widgets/v1"]; found {
+		t.Error("the merged package must be removed")
+	}
+}
+
+func TestResolveCyclesMergeTypeNameCollision(t *testing.T) {
+	plan := newRegistryTestPlan(t, openapi_spec.Definitions{
+		"io.k8s.api.a.v1.Spec": objectSchema(map[string]openapi_spec.Schema{"b": refProperty("io.k8s.api.b.v1.Spec")}),
+		"io.k8s.api.b.v1.Spec": objectSchema(map[string]openapi_spec.Schema{"a": refProperty("io.k8s.api.a.v1.Spec")}),
+	})
+
+	err := plan.ResolveCycles(MERGE_CYCLE_STRATEGY)
+	if err == nil || !strings.Contains(err.Error(), "both io.k8s.api.a.v1.Spec and io.k8s.api.b.v1.Spec define Spec") {
+		t.Errorf("expected the type name collision to be reported, got %v", err)
+	}
+}
+
+func TestResolveCyclesRawMessage(t *testing.T) {
+	gitRepo := "github.com/kubewarden/k8s-objects"
+	plan := newCycleTestPlan(t)
+
+	if err := plan.ResolveCycles(RAW_MESSAGE_CYCLE_STRATEGY); err != nil {
+		t.Fatalf("cannot resolve cycles: %v", err)
+	}
+	if _, err := plan.DependenciesGraph(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the packages are visited in alphabetical order, the reference from
+	// widgets back to gadgets closes the loop
+	widget := plan.Definitions["io.k8s.api.widgets.v1.Widget"]
+	resolver := swagger_helpers.NewGoTypeResolver(plan.Definitions, &plan.Interfaces, gitRepo)
+	gadgets, err := resolver.PropertyType(widget, "gadgets")
+	if err != nil {
+		t.Fatalf("cannot resolve type: %v", err)
+	}
+	if gadgets.Expr() != "[]easyjson.RawMessage" {
+		t.Errorf("the reference must be replaced by a raw message, got %s", gadgets.Expr())
+	}
+
+	gadget := plan.Definitions["io.k8s.api.gadgets.v1.Gadget"]
+	owner, err := resolver.PropertyType(gadget, "owner")
+	if err != nil {
+		t.Fatalf("cannot resolve type: %v", err)
+	}
+	if owner.Expr() != "*api_widgets_v1.Widget" {
+		t.Errorf("only the references closing the loop must be replaced, got %s", owner.Expr())
+	}
+
+	patchedSchema, err := widget.GeneratePatchedOpenAPIDef(gitRepo, &plan.Interfaces, "")
+	if err != nil {
+		t.Fatalf("cannot generate patched schema: %v", err)
+	}
+	items := patchedSchema.Properties["gadgets"].Items.Schema
+	goType, _ := items.Extensions["x-go-type"].(map[string]interface{})
+	if goType["type"] != "RawMessage" || items.Extensions["x-nullable"] != false {
+		t.Errorf("wrong extensions: %v", items.Extensions)
+	}
+	if id, _ := items.Extensions.GetString(swagger_helpers.BROKEN_REFERENCE_EXTENSION); id != "io.k8s.api.gadgets.v1.Gadget" {
+		t.Errorf("the broken reference must be recorded: %v", items.Extensions)
+	}
+}
+
+func TestResolveCyclesErrors(t *testing.T) {
+	plan := newCycleTestPlan(t)
+
+	err := plan.ResolveCycles(FAIL_CYCLE_STRATEGY)
+	if err == nil || !strings.Contains(err.Error(), "dependency cycles found between packages") {
+		t.Errorf("expected the cycles to be reported, got %v", err)
+	}
+
+	err = newRegistryTestPlan(t, openapi_spec.Definitions{}).ResolveCycles("ignore")
+	if err == nil || !strings.Contains(err.Error(), `unknown cycle strategy "ignore"`) {
+		t.Errorf("expected unknown strategies to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,242 @@
+package split
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/go-openapi/swag"
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
+	"github.com/pkg/errors"
+)
+
+const DEEP_COPY_FILE_NAME = "deep_copy.go"
+
+const DEEP_COPY_TEMPLATE = `// Code generated by k8s-objects-generator. DO NOT EDIT.
+
+package {{ .Package }}
+{{ if .Imports }}
+import (
+{{- range $path, $alias := .Imports }}
+	{{ $alias }} "{{ $path }}"
+{{- end }}
+)
+{{ end }}
+{{- range .Types }}
+// DeepCopyInto copies the receiver into out, the receiver must not be nil
+func (m *{{ .TypeName }}) DeepCopyInto(out *{{ .TypeName }}) {
+	*out = *m
+{{ .Body }}}
+
+// DeepCopy returns a deep copy of the receiver, nil when the receiver is nil
+func (m *{{ .TypeName }}) DeepCopy() *{{ .TypeName }} {
+	if m == nil {
+		return nil
+	}
+	out := new({{ .TypeName }})
+	m.DeepCopyInto(out)
+	return out
+}
+{{ end }}
+`
+
+type deepCopyType struct {
+	TypeName string
+	Body     string
+}
+
+// Writes, for each package, the DeepCopy methods of its types
+func GenerateDeepCopy(project Project, plan *RefactoringPlan) error {
+	resolver := swagger_helpers.NewGoTypeResolver(plan.Definitions, &plan.Interfaces, project.GitRepo)
+
+	for pkgName, pkg := range plan.Packages {
+		contents, err := renderDeepCopy(pkg, &resolver)
+		if err != nil {
+			return errors.Wrapf(err, "cannot render DeepCopy methods of package %s", pkgName)
+		}
+		if contents == nil {
+			continue
+		}
+
+		fileName := filepath.Join(project.Root, pkgName, DEEP_COPY_FILE_NAME)
+		if err := os.WriteFile(fileName, contents, 0644); err != nil {
+			return errors.Wrapf(err, "cannot write %s", fileName)
+		}
+	}
+
+	return nil
+}
+
+// Returns the contents of the file holding the DeepCopy methods of the types
+// of the package, nil when the package doesn't define any type
+func renderDeepCopy(pkg swagger_helpers.Package, resolver *swagger_helpers.GoTypeResolver) ([]byte, error) {
+	generator := deepCopyGenerator{imports: make(map[string]string)}
+
+	types := []deepCopyType{}
+	for _, def := range pkg.Definitions {
+		if override, found := resolver.Override(def); found && (override.Import != "" || override.DeepCopy) {
+			// the type is declared by another package, or it already
+			// declares its DeepCopyInto method
+			continue
+		}
+
+		defType, err := resolver.DefinitionType(def, pkg.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		var b strings.Builder
+		switch defType.Kind {
+		case swagger_helpers.StructGoType:
+			names := []string{}
+			for name := range def.SwaggerDefinition.Properties {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			for _, name := range names {
+				goType, err := resolver.PropertyType(def, name)
+				if err != nil {
+					return nil, errors.Wrapf(err, "cannot generate DeepCopy of %s", def.ID)
+				}
+				field := swag.ToGoName(name)
+				generator.deepCopy(&b, goType, "m."+field, "out."+field, 0)
+			}
+		default:
+			if defType.Name == "easyjson.RawMessage" {
+				// interfaces are not generated
+				continue
+			}
+			// named types, like the ones based on strings, slices and maps
+			generator.deepCopy(&b, defType, "(*m)", "(*out)", 0)
+		}
+
+		types = append(types, deepCopyType{
+			TypeName: swag.ToGoName(def.TypeName),
+			Body:     b.String(),
+		})
+	}
+
+	if len(types) == 0 {
+		return nil, nil
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].TypeName < types[j].TypeName
+	})
+
+	deepCopyTemplate, err := template.New("deep_copy").Parse(DEEP_COPY_TEMPLATE)
+	if err != nil {
+		return nil, err
+	}
+
+	templateData := struct {
+		Package string
+		Imports map[string]string
+		Types   []deepCopyType
+	}{
+		Package: filepath.Base(pkg.Name),
+		Imports: generator.imports,
+		Types:   types,
+	}
+
+	var buf bytes.Buffer
+	if err := deepCopyTemplate.Execute(&buf, templateData); err != nil {
+		return nil, err
+	}
+
+	contents, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("cannot format generated code: %v", err)
+	}
+
+	return contents, nil
+}
+
+// Generates the bodies of the DeepCopyInto methods
+type deepCopyGenerator struct {
+	// packages used by the generated code, indexed by import path
+	imports map[string]string
+}
+
+// Returns true when copying a value of the given type by assignment shares
+// memory with the original value
+func needsDeepCopy(t swagger_helpers.GoType) bool {
+	return t.Pointer || t.Kind == swagger_helpers.SliceGoType || t.Kind == swagger_helpers.MapGoType ||
+		t.Kind == swagger_helpers.BytesGoType || t.Kind == swagger_helpers.StructGoType ||
+		t.Kind == swagger_helpers.OpaqueGoType || t.HasDeepCopy
+}
+
+// Writes the statements that turn out, which holds a shallow copy of in, into
+// a deep copy of it. Both in and out must be addressable
+func (g *deepCopyGenerator) deepCopy(b *strings.Builder, t swagger_helpers.GoType, in, out string, depth int) {
+	if !needsDeepCopy(t) {
+		return
+	}
+
+	if t.Pointer {
+		elem := t.Deref()
+		g.use(elem)
+		fmt.Fprintf(b, "if %s != nil {\nin, out := &%s, &%s\n*out = new(%s)\n", in, in, out, elem.Expr())
+		if elem.Kind == swagger_helpers.StructGoType || elem.HasDeepCopy {
+			b.WriteString("(*in).DeepCopyInto(*out)\n")
+		} else {
+			b.WriteString("**out = **in\n")
+			g.deepCopy(b, elem, "(**in)", "(**out)", depth)
+		}
+		b.WriteString("}\n")
+		return
+	}
+
+	switch {
+	case t.Kind == swagger_helpers.StructGoType || t.HasDeepCopy:
+		fmt.Fprintf(b, "%s.DeepCopyInto(&%s)\n", in, out)
+	case t.Kind == swagger_helpers.OpaqueGoType:
+		// the shape of the override is not known, the value is encoded and
+		// decoded into a new one. Values that cannot be encoded are left
+		// shallow copied
+		g.use(t)
+		fmt.Fprintf(b, "if data, err := %s; err == nil {\nvar copied %s\n", opaqueMarshalCall(t, in, g.imports), t.Expr())
+		fmt.Fprintf(b, "if %s == nil {\n%s = copied\n}\n}\n", opaqueUnmarshalCall(t, "data", "copied", g.imports), out)
+	case t.Kind == swagger_helpers.BytesGoType:
+		g.use(t)
+		fmt.Fprintf(b, "if %s != nil {\nin, out := &%s, &%s\n", in, in, out)
+		fmt.Fprintf(b, "*out = make(%s, len(*in))\ncopy(*out, *in)\n}\n", t.Expr())
+	case t.Kind == swagger_helpers.SliceGoType:
+		g.use(t)
+		fmt.Fprintf(b, "if %s != nil {\nin, out := &%s, &%s\n", in, in, out)
+		fmt.Fprintf(b, "*out = make(%s, len(*in))\ncopy(*out, *in)\n", t.Expr())
+		if needsDeepCopy(*t.Elem) {
+			i := fmt.Sprintf("i%d", depth)
+			fmt.Fprintf(b, "for %s := range *in {\n", i)
+			g.deepCopy(b, *t.Elem, "(*in)["+i+"]", "(*out)["+i+"]", depth+1)
+			b.WriteString("}\n")
+		}
+		b.WriteString("}\n")
+	case t.Kind == swagger_helpers.MapGoType:
+		g.use(t)
+		k, v := fmt.Sprintf("k%d", depth), fmt.Sprintf("v%d", depth)
+		fmt.Fprintf(b, "if %s != nil {\nin, out := &%s, &%s\n", in, in, out)
+		fmt.Fprintf(b, "*out = make(%s, len(*in))\n", t.Expr())
+		if needsDeepCopy(*t.Elem) {
+			// map values are not addressable, they are copied into a
+			// variable first
+			w := fmt.Sprintf("w%d", depth)
+			fmt.Fprintf(b, "for %s, %s := range *in {\n%s := %s\n", k, v, w, v)
+			g.deepCopy(b, *t.Elem, v, w, depth+1)
+			fmt.Fprintf(b, "(*out)[%s] = %s\n}\n", k, w)
+		} else {
+			fmt.Fprintf(b, "for %s, %s := range *in {\n(*out)[%s] = %s\n}\n", k, v, k, v)
+		}
+		b.WriteString("}\n")
+	}
+}
+
+// Records the packages required by the given type
+func (g *deepCopyGenerator) use(t swagger_helpers.GoType) {
+	t.CollectImports(g.imports)
+}
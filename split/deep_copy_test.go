@@ -0,0 +1,24 @@
+package split
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
+)
+
+// The behaviour of the generated methods is tested by the DeepCopy tests kept
+// inside of testdata, which run against the generated code
+func TestRenderDeepCopy(t *testing.T) {
+	gitRepo := "github.com/kubewarden/k8s-objects"
+	plan := newGeneratedCodeTestPlan(t)
+	resolver := swagger_helpers.NewGoTypeResolver(plan.Definitions, &plan.Interfaces, gitRepo)
+
+	contents, err := renderDeepCopy(plan.Packages["api/core/v1"], &resolver)
+	if err != nil {
+		t.Fatalf("cannot render DeepCopy methods: %v", err)
+	}
+	if code := string(contents); !strings.Contains(code, "func (m *PodSpec) DeepCopyInto(out *PodSpec) {") {
+		t.Errorf("cannot find DeepCopyInto of PodSpec inside of generated code:\n%s", code)
+	}
+}
@@ -19,6 +19,14 @@ func GenerateEasyjsonFiles(project Project, plan *RefactoringPlan) error {
 		return err
 	}
 
+	// the types replacing the overridden definitions are required to build
+	// the generated code
+	for _, path := range plan.Interfaces.OverrideImports() {
+		if err := project.RunGoGet(path); err != nil {
+			return errors.Wrapf(err, "cannot fetch package %s", path)
+		}
+	}
+
 	dependenciesGraph, err := plan.DependenciesGraph()
 	if err != nil {
 		return err
@@ -41,8 +49,10 @@ func findEasyjsonTargets(root string) ([]string, error) {
 
 	walkDirFn := func(path string, d os.DirEntry, err error) (e error) {
 		if !d.IsDir() {
-			// Do not add files generated by easyjson
-			if !strings.Contains(path, "easyjson") && filepath.Ext(path) == ".go" {
+			// Do not add files generated by easyjson, nor test files
+			if !strings.Contains(path, "easyjson") &&
+				!strings.HasSuffix(path, "_test.go") &&
+				filepath.Ext(path) == ".go" {
 				easyjsonTargets = append(easyjsonTargets, path)
 			}
 		}
@@ -0,0 +1,237 @@
+package split
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/template"
+
+	"github.com/go-openapi/swag"
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
+	"github.com/pkg/errors"
+)
+
+const EMBEDDED_OBJECT_FILE_NAME = "embedded_object.go"
+
+const EMBEDDED_OBJECT_TEMPLATE = `// Code generated by k8s-objects-generator. DO NOT EDIT.
+
+package registry
+
+import (
+	"fmt"
+
+	"github.com/mailru/easyjson"
+	"github.com/mailru/easyjson/jlexer"
+	"github.com/mailru/easyjson/jwriter"
+
+	schema "{{ .SchemaImport }}"
+{{- range $path, $alias := .Imports }}
+	{{ $alias }} "{{ $path }}"
+{{- end }}
+)
+
+// Unknown holds an embedded object whose kind is not known by the registry.
+// The raw JSON document is preserved as-is
+type Unknown struct {
+	Raw easyjson.RawMessage
+	gvk schema.GroupVersionKind
+}
+
+// GroupVersionKind returns the kind found inside of the raw document
+func (u *Unknown) GroupVersionKind() schema.GroupVersionKind {
+	return u.gvk
+}
+
+// SetGroupVersionKind changes the kind returned by GroupVersionKind, the raw
+// document is not altered
+func (u *Unknown) SetGroupVersionKind(gvk schema.GroupVersionKind) {
+	u.gvk = gvk
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (u *Unknown) MarshalEasyJSON(w *jwriter.Writer) {
+	u.Raw.MarshalEasyJSON(w)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (u *Unknown) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	raw := l.Raw()
+	if !l.Ok() {
+		return
+	}
+	u.Raw = append(easyjson.RawMessage(nil), raw...)
+
+	meta := typeMeta{}
+	if err := easyjson.Unmarshal(raw, &meta); err != nil {
+		l.AddError(err)
+		return
+	}
+	u.gvk = schema.FromAPIVersionAndKind(meta.APIVersion, meta.Kind)
+}
+
+// DecodeEmbedded turns a raw object, like the ones held by RawExtension fields
+// and by embedded resources, into the Go type of its kind. Objects of unknown
+// kinds are returned as *Unknown, nil is returned when the raw object is not
+// set
+func DecodeEmbedded(raw easyjson.RawMessage) (Object, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	meta := typeMeta{}
+	if err := easyjson.Unmarshal(raw, &meta); err != nil {
+		return nil, fmt.Errorf("cannot read apiVersion and kind: %v", err)
+	}
+	gvk := schema.FromAPIVersionAndKind(meta.APIVersion, meta.Kind)
+
+	obj, err := New(gvk)
+	if err != nil {
+		obj = &Unknown{gvk: gvk}
+	}
+	if err := easyjson.Unmarshal(raw, obj); err != nil {
+		return nil, fmt.Errorf("cannot decode %s: %v", gvk, err)
+	}
+
+	return obj, nil
+}
+
+// EncodeEmbedded returns the raw representation of the object, which can be
+// stored inside of RawExtension fields and embedded resources
+func EncodeEmbedded(obj Object) (easyjson.RawMessage, error) {
+	if obj == nil {
+		return nil, nil
+	}
+
+	data, err := easyjson.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	return easyjson.RawMessage(data), nil
+}
+{{ range .Fields }}
+// Decode{{ .FuncName }} decodes the {{ .JSONName }} field of the given
+// {{ .TypeName }}, see DecodeEmbedded
+func Decode{{ .FuncName }}(m *{{ .Alias }}.{{ .TypeName }}) (Object, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return DecodeEmbedded(m.{{ .Field }})
+}
+
+// Encode{{ .FuncName }} stores obj inside of the {{ .JSONName }} field of the
+// given {{ .TypeName }}
+func Encode{{ .FuncName }}(m *{{ .Alias }}.{{ .TypeName }}, obj Object) error {
+	raw, err := EncodeEmbedded(obj)
+	if err != nil {
+		return err
+	}
+	m.{{ .Field }} = raw
+	return nil
+}
+{{ end }}
+`
+
+type embeddedObjectField struct {
+	FuncName string
+	Alias    string
+	TypeName string
+	Field    string
+	JSONName string
+}
+
+// Writes, inside of the `registry` package, the helpers that decode the
+// objects held by RawExtension fields and embedded resources
+func GenerateEmbeddedObjectHelpers(project Project, plan *RefactoringPlan) error {
+	contents, err := renderEmbeddedObjectHelpers(plan, project.GitRepo)
+	if err != nil {
+		return errors.Wrap(err, "cannot render embedded object helpers")
+	}
+
+	registryDir := filepath.Join(project.Root, REGISTRY_PACKAGE)
+	if err := os.MkdirAll(registryDir, 0777); err != nil {
+		return errors.Wrapf(err, "cannot create dir %s", registryDir)
+	}
+
+	fileName := filepath.Join(registryDir, EMBEDDED_OBJECT_FILE_NAME)
+	if err := os.WriteFile(fileName, contents, 0644); err != nil {
+		return errors.Wrapf(err, "cannot write %s", fileName)
+	}
+
+	return nil
+}
+
+func renderEmbeddedObjectHelpers(plan *RefactoringPlan, gitRepo string) ([]byte, error) {
+	schemaPackage, err := plan.Naming.SchemaPackage()
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := swagger_helpers.NewGoTypeResolver(plan.Definitions, &plan.Interfaces, gitRepo)
+	imports := make(map[string]string)
+	fields := []embeddedObjectField{}
+
+	for pkgName, pkg := range plan.Packages {
+		alias := plan.Naming.PackageAlias(pkgName)
+
+		for _, def := range pkg.Definitions {
+			for _, name := range def.EmbeddedObjectProperties() {
+				goType, err := resolver.PropertyType(def, name)
+				if err != nil {
+					return nil, errors.Wrapf(err, "cannot generate embedded object helpers of %s", def.ID)
+				}
+				if goType.Pointer || goType.Name != "easyjson.RawMessage" {
+					// e.g. RawExtension is replaced by a type override
+					log.Printf("Warning: property %s of %s is not a raw message but %s, skipping its embedded object helpers",
+						name, def.ID, goType.Expr())
+					continue
+				}
+
+				typeName := swag.ToGoName(def.TypeName)
+				field := swag.ToGoName(name)
+				fields = append(fields, embeddedObjectField{
+					FuncName: swag.ToGoName(alias) + typeName + field,
+					Alias:    alias,
+					TypeName: typeName,
+					Field:    field,
+					JSONName: name,
+				})
+				imports[fmt.Sprintf("%s/%s", gitRepo, pkgName)] = alias
+			}
+		}
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].FuncName < fields[j].FuncName
+	})
+
+	embeddedObjectTemplate, err := template.New("embedded_object").Parse(EMBEDDED_OBJECT_TEMPLATE)
+	if err != nil {
+		return nil, err
+	}
+
+	templateData := struct {
+		SchemaImport string
+		Imports      map[string]string
+		Fields       []embeddedObjectField
+	}{
+		SchemaImport: fmt.Sprintf("%s/%s", gitRepo, schemaPackage),
+		Imports:      imports,
+		Fields:       fields,
+	}
+
+	var buf bytes.Buffer
+	if err := embeddedObjectTemplate.Execute(&buf, templateData); err != nil {
+		return nil, err
+	}
+
+	contents, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("cannot format generated code: %v", err)
+	}
+
+	return contents, nil
+}
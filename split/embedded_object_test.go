@@ -0,0 +1,79 @@
+package split
+
+import (
+	"strings"
+	"testing"
+
+	openapi_spec "github.com/go-openapi/spec"
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
+)
+
+func TestRenderEmbeddedObjectHelpers(t *testing.T) {
+	gitRepo := "github.com/kubewarden/k8s-objects"
+
+	embeddedResource := openapi_spec.Schema{SchemaProps: openapi_spec.SchemaProps{Type: []string{"object"}}}
+	embeddedResource.AddExtension("x-kubernetes-embedded-resource", true)
+
+	plan := newRegistryTestPlan(t, openapi_spec.Definitions{
+		"io.k8s.apimachinery.pkg.runtime.RawExtension": {SchemaProps: openapi_spec.SchemaProps{Type: []string{"object"}}},
+		"io.k8s.api.apps.v1.ControllerRevision": objectSchema(map[string]openapi_spec.Schema{
+			"data":     refProperty("io.k8s.apimachinery.pkg.runtime.RawExtension"),
+			"revision": {SchemaProps: openapi_spec.SchemaProps{Type: []string{"integer"}}},
+		}),
+		"io.k8s.api.example.v1.Wrapper": objectSchema(map[string]openapi_spec.Schema{
+			"object": embeddedResource,
+		}),
+	})
+
+	contents, err := renderEmbeddedObjectHelpers(plan, gitRepo)
+	if err != nil {
+		t.Fatalf("cannot render embedded object helpers: %v", err)
+	}
+
+	code := string(contents)
+	expectedSnippets := []string{
+		"package registry",
+		`api_apps_v1 "github.com/kubewarden/k8s-objects/api/apps/v1"`,
+		`api_example_v1 "github.com/kubewarden/k8s-objects/api/example/v1"`,
+		"func DecodeEmbedded(raw easyjson.RawMessage) (Object, error) {",
+		"func EncodeEmbedded(obj Object) (easyjson.RawMessage, error) {",
+		"func DecodeAPIAppsV1ControllerRevisionData(m *api_apps_v1.ControllerRevision) (Object, error) {",
+		"return DecodeEmbedded(m.Data)",
+		"func EncodeAPIAppsV1ControllerRevisionData(m *api_apps_v1.ControllerRevision, obj Object) error {",
+		"m.Data = raw",
+		"func DecodeAPIExampleV1WrapperObject(m *api_example_v1.Wrapper) (Object, error) {",
+	}
+	for _, snippet := range expectedSnippets {
+		if !strings.Contains(code, snippet) {
+			t.Errorf("cannot find %s inside of generated code:\n%s", snippet, code)
+		}
+	}
+	if strings.Contains(code, "Revision(") {
+		t.Errorf("only embedded objects must have helpers:\n%s", code)
+	}
+}
+
+func TestRenderEmbeddedObjectHelpersOverriddenRawExtension(t *testing.T) {
+	gitRepo := "github.com/kubewarden/k8s-objects"
+	plan := newRegistryTestPlan(t, openapi_spec.Definitions{
+		"io.k8s.apimachinery.pkg.runtime.RawExtension": {SchemaProps: openapi_spec.SchemaProps{Type: []string{"object"}}},
+		"io.k8s.api.apps.v1.ControllerRevision": objectSchema(map[string]openapi_spec.Schema{
+			"data": refProperty("io.k8s.apimachinery.pkg.runtime.RawExtension"),
+		}),
+	})
+	err := plan.ApplyTypeOverrides(swagger_helpers.TypeOverrides{
+		"io.k8s.apimachinery.pkg.runtime.RawExtension": {Import: "github.com/example/types", Type: "RawExtension", Kind: swagger_helpers.OPAQUE_OVERRIDE},
+	})
+	if err != nil {
+		t.Fatalf("cannot apply overrides: %v", err)
+	}
+
+	contents, err := renderEmbeddedObjectHelpers(plan, gitRepo)
+	if err != nil {
+		t.Fatalf("overridden embedded objects must be skipped, got %v", err)
+	}
+	code := string(contents)
+	if strings.Contains(code, "ControllerRevisionData") || strings.Contains(code, "api_apps_v1") {
+		t.Errorf("overridden embedded objects must not have helpers:\n%s", code)
+	}
+}
@@ -0,0 +1,214 @@
+package split
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/go-openapi/swag"
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
+	"github.com/pkg/errors"
+)
+
+const EQUAL_FILE_NAME = "equal.go"
+
+const EQUAL_TEMPLATE = `// Code generated by k8s-objects-generator. DO NOT EDIT.
+
+package {{ .Package }}
+{{ if .Imports }}
+import (
+{{- range $path, $alias := .Imports }}
+	{{ $alias }} "{{ $path }}"
+{{- end }}
+)
+{{ end }}
+{{- range .Types }}
+// Equal returns true when the receiver and other are semantically equal. Nil
+// and empty slices and maps are considered equal, since both are omitted
+// from the JSON representation
+func (m *{{ .TypeName }}) Equal(other *{{ .TypeName }}) bool {
+	if m == nil || other == nil {
+		return m == other
+	}
+{{ .Body }}
+	return true
+}
+{{ end }}
+`
+
+type equalType struct {
+	TypeName string
+	Body     string
+}
+
+// Writes, for each package, the Equal methods of its types
+func GenerateEqual(project Project, plan *RefactoringPlan) error {
+	resolver := swagger_helpers.NewGoTypeResolver(plan.Definitions, &plan.Interfaces, project.GitRepo)
+
+	for pkgName, pkg := range plan.Packages {
+		contents, err := renderEqual(pkg, &resolver)
+		if err != nil {
+			return errors.Wrapf(err, "cannot render Equal methods of package %s", pkgName)
+		}
+		if contents == nil {
+			continue
+		}
+
+		fileName := filepath.Join(project.Root, pkgName, EQUAL_FILE_NAME)
+		if err := os.WriteFile(fileName, contents, 0644); err != nil {
+			return errors.Wrapf(err, "cannot write %s", fileName)
+		}
+	}
+
+	return nil
+}
+
+// Returns the contents of the file holding the Equal methods of the types of
+// the package, nil when the package doesn't define any type
+func renderEqual(pkg swagger_helpers.Package, resolver *swagger_helpers.GoTypeResolver) ([]byte, error) {
+	generator := equalGenerator{imports: make(map[string]string)}
+
+	types := []equalType{}
+	for _, def := range pkg.Definitions {
+		if override, found := resolver.Override(def); found && (override.Import != "" || override.Equal) {
+			// the type is declared by another package, or it already
+			// declares its Equal method
+			continue
+		}
+
+		defType, err := resolver.DefinitionType(def, pkg.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		var b strings.Builder
+		switch defType.Kind {
+		case swagger_helpers.StructGoType:
+			names := []string{}
+			for name := range def.SwaggerDefinition.Properties {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			for _, name := range names {
+				goType, err := resolver.PropertyType(def, name)
+				if err != nil {
+					return nil, errors.Wrapf(err, "cannot generate Equal of %s", def.ID)
+				}
+				field := swag.ToGoName(name)
+				generator.equalityCheck(&b, goType, "m."+field, "other."+field, 0)
+			}
+		default:
+			if defType.Name == "easyjson.RawMessage" {
+				// interfaces are not generated
+				continue
+			}
+			// named types, like the ones based on strings, slices and maps
+			generator.equalityCheck(&b, defType, "(*m)", "(*other)", 0)
+		}
+
+		types = append(types, equalType{
+			TypeName: swag.ToGoName(def.TypeName),
+			Body:     b.String(),
+		})
+	}
+
+	if len(types) == 0 {
+		return nil, nil
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].TypeName < types[j].TypeName
+	})
+
+	equalTemplate, err := template.New("equal").Parse(EQUAL_TEMPLATE)
+	if err != nil {
+		return nil, err
+	}
+
+	templateData := struct {
+		Package string
+		Imports map[string]string
+		Types   []equalType
+	}{
+		Package: filepath.Base(pkg.Name),
+		Imports: generator.imports,
+		Types:   types,
+	}
+
+	var buf bytes.Buffer
+	if err := equalTemplate.Execute(&buf, templateData); err != nil {
+		return nil, err
+	}
+
+	contents, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("cannot format generated code: %v", err)
+	}
+
+	return contents, nil
+}
+
+// Generates the bodies of the Equal methods
+type equalGenerator struct {
+	// packages used by the generated code, indexed by import path
+	imports map[string]string
+}
+
+// Writes the statements that return false when the two values differ
+func (g *equalGenerator) equalityCheck(b *strings.Builder, t swagger_helpers.GoType, x, y string, depth int) {
+	if t.Pointer {
+		if t.Kind == swagger_helpers.StructGoType {
+			fmt.Fprintf(b, "if !%s.Equal(%s) {\nreturn false\n}\n", x, y)
+			return
+		}
+		fmt.Fprintf(b, "if (%s == nil) != (%s == nil) {\nreturn false\n}\n", x, y)
+		if t.HasEqual {
+			// the method of the override may not handle nil receivers
+			fmt.Fprintf(b, "if %s != nil && !%s.Equal(%s) {\nreturn false\n}\n", x, x, y)
+			return
+		}
+		if t.Kind == swagger_helpers.ScalarGoType {
+			fmt.Fprintf(b, "if %s != nil && *%s != *%s {\nreturn false\n}\n", x, x, y)
+			return
+		}
+		fmt.Fprintf(b, "if %s != nil {\n", x)
+		g.equalityCheck(b, t.Deref(), "(*"+x+")", "(*"+y+")", depth)
+		b.WriteString("}\n")
+		return
+	}
+
+	switch {
+	case t.Kind == swagger_helpers.StructGoType || t.HasEqual:
+		fmt.Fprintf(b, "if !(&%s).Equal(&%s) {\nreturn false\n}\n", x, y)
+	case t.Kind == swagger_helpers.ScalarGoType:
+		fmt.Fprintf(b, "if %s != %s {\nreturn false\n}\n", x, y)
+	case t.Kind == swagger_helpers.BytesGoType:
+		// opaque values are raw JSON documents, they are compared byte
+		// by byte
+		g.imports["bytes"] = "bytes"
+		fmt.Fprintf(b, "if !bytes.Equal(%s, %s) {\nreturn false\n}\n", x, y)
+	case t.Kind == swagger_helpers.OpaqueGoType:
+		// the shape of the override is not known, the values are compared
+		// through their JSON representation
+		g.imports["bytes"] = "bytes"
+		fmt.Fprintf(b, "{\nxj, _ := %s\nyj, _ := %s\n", opaqueMarshalCall(t, x, g.imports), opaqueMarshalCall(t, y, g.imports))
+		b.WriteString("if !bytes.Equal(xj, yj) {\nreturn false\n}\n}\n")
+	case t.Kind == swagger_helpers.SliceGoType:
+		i := fmt.Sprintf("i%d", depth)
+		fmt.Fprintf(b, "if len(%s) != len(%s) {\nreturn false\n}\n", x, y)
+		fmt.Fprintf(b, "for %s := range %s {\n", i, x)
+		g.equalityCheck(b, *t.Elem, x+"["+i+"]", y+"["+i+"]", depth+1)
+		b.WriteString("}\n")
+	case t.Kind == swagger_helpers.MapGoType:
+		k, v, w := fmt.Sprintf("k%d", depth), fmt.Sprintf("v%d", depth), fmt.Sprintf("w%d", depth)
+		fmt.Fprintf(b, "if len(%s) != len(%s) {\nreturn false\n}\n", x, y)
+		fmt.Fprintf(b, "for %s, %s := range %s {\n%s, found := %s[%s]\nif !found {\nreturn false\n}\n", k, v, x, w, y, k)
+		g.equalityCheck(b, *t.Elem, v, w, depth+1)
+		b.WriteString("}\n")
+	}
+}
@@ -0,0 +1,21 @@
+package split
+
+import (
+	"testing"
+
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
+)
+
+func TestRenderEqualNamedTypes(t *testing.T) {
+	gitRepo := "github.com/kubewarden/k8s-objects"
+	plan := newGeneratedCodeTestPlan(t)
+	resolver := swagger_helpers.NewGoTypeResolver(plan.Definitions, &plan.Interfaces, gitRepo)
+
+	contents, err := renderEqual(plan.Packages["apimachinery/pkg/api/resource"], &resolver)
+	if err != nil {
+		t.Fatalf("cannot render Equal methods: %v", err)
+	}
+	if contents != nil {
+		t.Errorf("Quantity declares its own Equal method:\n%s", contents)
+	}
+}
@@ -0,0 +1,367 @@
+package split
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	openapi_spec "github.com/go-openapi/spec"
+	"github.com/go-openapi/swag"
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
+)
+
+// Directory holding the packages the generated code depends on and the tests
+// that are run against the generated code
+const GENERATED_CODE_TESTDATA = "testdata/generated_code"
+
+// Returns a plan covering the types handled by the generators: kinds, list
+// maps, enums, maps of quantities, opaque values, embedded objects and
+// overrides, both foreign and written by the generator
+func newGeneratedCodeTestPlan(t *testing.T) *RefactoringPlan {
+	containers := openapi_spec.Schema{
+		SchemaProps: openapi_spec.SchemaProps{
+			Type:  []string{"array"},
+			Items: &openapi_spec.SchemaOrArray{Schema: openapi_spec.RefSchema("#/definitions/io.k8s.api.core.v1.Container")},
+		},
+		VendorExtensible: openapi_spec.VendorExtensible{
+			Extensions: openapi_spec.Extensions{
+				"x-kubernetes-list-type":     "map",
+				"x-kubernetes-list-map-keys": []interface{}{"name"},
+			},
+		},
+	}
+	stringList := openapi_spec.Schema{
+		SchemaProps: openapi_spec.SchemaProps{
+			Type:  []string{"array"},
+			Items: &openapi_spec.SchemaOrArray{Schema: openapi_spec.StringProperty()},
+		},
+	}
+	stringMap := openapi_spec.Schema{
+		SchemaProps: openapi_spec.SchemaProps{
+			Type:                 []string{"object"},
+			AdditionalProperties: &openapi_spec.SchemaOrBool{Schema: openapi_spec.StringProperty()},
+		},
+	}
+	quantityMap := openapi_spec.Schema{
+		SchemaProps: openapi_spec.SchemaProps{
+			Type: []string{"object"},
+			AdditionalProperties: &openapi_spec.SchemaOrBool{
+				Schema: openapi_spec.RefSchema("#/definitions/io.k8s.apimachinery.pkg.api.resource.Quantity"),
+			},
+		},
+	}
+	enum := func(values ...interface{}) openapi_spec.Schema {
+		return openapi_spec.Schema{SchemaProps: openapi_spec.SchemaProps{Type: []string{"string"}, Enum: values}}
+	}
+	object := openapi_spec.Schema{SchemaProps: openapi_spec.SchemaProps{Type: []string{"object"}}}
+	embeddedResource := openapi_spec.Schema{
+		SchemaProps:      openapi_spec.SchemaProps{Type: []string{"object"}},
+		VendorExtensible: openapi_spec.VendorExtensible{Extensions: openapi_spec.Extensions{"x-kubernetes-embedded-resource": true}},
+	}
+
+	pod := kindSchema(map[string]interface{}{"group": "", "version": "v1", "kind": "Pod"})
+	pod.Properties["metadata"] = refProperty("io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta")
+	pod.Properties["spec"] = refProperty("io.k8s.api.core.v1.PodSpec")
+
+	podSpec := objectSchema(map[string]openapi_spec.Schema{
+		"containers":    containers,
+		"hostname":      stringProperty(""),
+		"nodeSelector":  stringMap,
+		"overhead":      quantityMap,
+		"restartPolicy": enum("Always", "Never"),
+		"opaque":        object,
+		"raw":           refProperty("io.k8s.apimachinery.pkg.runtime.RawExtension"),
+		"template":      embeddedResource,
+		"selector":      refProperty("io.k8s.apimachinery.pkg.apis.meta.v1.LabelSelector"),
+	})
+	podSpec.Required = []string{"containers", "restartPolicy"}
+
+	container := objectSchema(map[string]openapi_spec.Schema{
+		"name":            stringProperty(""),
+		"args":            stringList,
+		"imagePullPolicy": enum("Always", "Never", "IfNotPresent"),
+		"port":            refProperty("io.k8s.apimachinery.pkg.util.intstr.IntOrString"),
+		"startedAt":       refProperty("io.k8s.apimachinery.pkg.apis.meta.v1.Time"),
+		"data":            stringProperty("byte"),
+	})
+	container.Required = []string{"name"}
+
+	plan := newRegistryTestPlan(t, openapi_spec.Definitions{
+		"io.k8s.api.core.v1.Pod":       pod,
+		"io.k8s.api.core.v1.PodSpec":   podSpec,
+		"io.k8s.api.core.v1.Container": container,
+		"io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta": objectSchema(map[string]openapi_spec.Schema{
+			"name":              stringProperty(""),
+			"labels":            stringMap,
+			"createdAt":         stringProperty("date-time"),
+			"creationTimestamp": refProperty("io.k8s.apimachinery.pkg.apis.meta.v1.Time"),
+			"deletionTimestamp": refProperty("io.k8s.apimachinery.pkg.apis.meta.v1.MicroTime"),
+		}),
+		"io.k8s.apimachinery.pkg.apis.meta.v1.Time":      stringProperty("date-time"),
+		"io.k8s.apimachinery.pkg.apis.meta.v1.MicroTime": stringProperty("date-time"),
+		"io.k8s.apimachinery.pkg.apis.meta.v1.LabelSelector": objectSchema(map[string]openapi_spec.Schema{
+			"matchLabels": stringMap,
+		}),
+		"io.k8s.apimachinery.pkg.api.resource.Quantity":   stringProperty(""),
+		"io.k8s.apimachinery.pkg.util.intstr.IntOrString": stringProperty(swagger_helpers.INT_OR_STRING_FORMAT),
+		"io.k8s.apimachinery.pkg.runtime.RawExtension":    object,
+	})
+
+	err := plan.ApplyTypeOverrides(swagger_helpers.TypeOverrides{
+		"io.k8s.apimachinery.pkg.apis.meta.v1.Time": {
+			Import: "github.com/example/types", Type: "Time", Kind: "struct", DeepCopy: true, Equal: true,
+		},
+		"io.k8s.apimachinery.pkg.apis.meta.v1.LabelSelector": {
+			Import: "github.com/example/types", Type: "LabelSelector", Kind: "opaque",
+		},
+		"io.k8s.apimachinery.pkg.apis.meta.v1.MicroTime": {
+			Import: "github.com/example/types", Type: "MicroTime", Marshaler: "text",
+		},
+	})
+	if err != nil {
+		t.Fatalf("cannot apply overrides: %v", err)
+	}
+
+	return plan
+}
+
+// Writes the models of the plan, standing in for the ones generated by
+// swagger and easyjson. The easyjson methods rely on encoding/json
+func writeModels(t *testing.T, project Project, plan *RefactoringPlan) {
+	t.Helper()
+	resolver := swagger_helpers.NewGoTypeResolver(plan.Definitions, &plan.Interfaces, project.GitRepo)
+
+	for pkgName, pkg := range plan.Packages {
+		imports := map[string]string{
+			"encoding/json":                      "json",
+			"github.com/mailru/easyjson/jlexer":  "jlexer",
+			"github.com/mailru/easyjson/jwriter": "jwriter",
+		}
+		var b strings.Builder
+		typeNames := []string{}
+		// enums shared by many definitions are declared once
+		enums := make(map[string]openapi_spec.Schema)
+
+		for _, def := range pkg.Definitions {
+			if _, found := resolver.Override(def); found || plan.Interfaces.IsInterface(project.GitRepo, def.PackageName, def.TypeName) {
+				continue
+			}
+			defType, err := resolver.DefinitionType(def, pkg.Name)
+			if err != nil {
+				t.Fatalf("cannot resolve %s: %v", def.ID, err)
+			}
+
+			typeName := swag.ToGoName(def.TypeName)
+			typeNames = append(typeNames, typeName)
+			switch {
+			case defType.Kind == swagger_helpers.StructGoType:
+				names := []string{}
+				for name := range def.SwaggerDefinition.Properties {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+
+				fmt.Fprintf(&b, "type %s struct {\n", typeName)
+				for _, name := range names {
+					goType, err := resolver.PropertyType(def, name)
+					if err != nil {
+						t.Fatalf("cannot resolve %s of %s: %v", name, def.ID, err)
+					}
+					goType.CollectImports(imports)
+					fmt.Fprintf(&b, "%s %s `json:\"%s,omitempty\"`\n", swag.ToGoName(name), goType.Expr(), name)
+				}
+				b.WriteString("}\n\n")
+			case defType.Underlying != "":
+				fmt.Fprintf(&b, "type %s %s\n\n", typeName, defType.Underlying)
+			default:
+				defType.Name = ""
+				defType.CollectImports(imports)
+				fmt.Fprintf(&b, "type %s %s\n\n", typeName, defType.Expr())
+			}
+
+			for enumName, enum := range def.EnumDefinitions() {
+				enums[enumName] = enum
+			}
+		}
+
+		for enumName, enum := range enums {
+			// like swagger, one constant per value
+			prefix, _ := enum.Extensions.GetString(swagger_helpers.ENUM_CONSTANT_PREFIX_EXTENSION)
+			fmt.Fprintf(&b, "type %s string\n\nconst (\n", enumName)
+			for _, value := range enum.Enum {
+				fmt.Fprintf(&b, "%s%s %s = %q\n", prefix, swag.ToGoName(value.(string)), enumName, value)
+			}
+			b.WriteString(")\n\n")
+		}
+
+		for _, typeName := range typeNames {
+			fmt.Fprintf(&b, `type plain%[1]s %[1]s
+
+func (m %[1]s) MarshalEasyJSON(w *jwriter.Writer) {
+	w.Raw(json.Marshal(plain%[1]s(m)))
+}
+
+func (m *%[1]s) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	l.AddError(json.Unmarshal(l.Raw(), (*plain%[1]s)(m)))
+}
+
+`, typeName)
+		}
+
+		var source bytes.Buffer
+		fmt.Fprintf(&source, "package %s\n\nimport (\n", filepath.Base(pkgName))
+		for path, alias := range imports {
+			fmt.Fprintf(&source, "%s %q\n", alias, path)
+		}
+		fmt.Fprintf(&source, ")\n\n%s", b.String())
+		if len(typeNames) == 0 {
+			// the package holds only the types written by the generator
+			source.Reset()
+			fmt.Fprintf(&source, "package %s\n", filepath.Base(pkgName))
+		}
+
+		contents, err := format.Source(source.Bytes())
+		if err != nil {
+			t.Fatalf("cannot format models of %s: %v\n%s", pkgName, err, source.String())
+		}
+		writeFile(t, filepath.Join(project.Root, pkgName, "models.go"), contents)
+	}
+}
+
+func writeFile(t *testing.T, fileName string, contents []byte) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(fileName), 0777); err != nil {
+		t.Fatalf("cannot create dir of %s: %v", fileName, err)
+	}
+	if err := os.WriteFile(fileName, contents, 0644); err != nil {
+		t.Fatalf("cannot write %s: %v", fileName, err)
+	}
+}
+
+// Copies the files of the source directory into the destination one
+func copyDir(t *testing.T, src, dst string) {
+	t.Helper()
+	err := filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		writeFile(t, filepath.Join(dst, rel), contents)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("cannot copy %s: %v", src, err)
+	}
+}
+
+// Returns the version of the given module required by the generator, the
+// generated code is built against the same one
+func requiredVersion(t *testing.T, module string) string {
+	t.Helper()
+	goMod, err := os.ReadFile("../go.mod")
+	if err != nil {
+		t.Fatalf("cannot read go.mod: %v", err)
+	}
+	match := regexp.MustCompile(regexp.QuoteMeta(module) + ` (v\S+)`).FindSubmatch(goMod)
+	if match == nil {
+		t.Fatalf("cannot find %s inside of go.mod", module)
+	}
+	return string(match[1])
+}
+
+// Runs all the generators against a small plan, then vets the resulting
+// module and runs both the generated tests and the ones kept inside of
+// testdata. The packages required by the generated code are replaced by
+// stubs, the module cache must hold easyjson
+func TestGeneratedCode(t *testing.T) {
+	if testing.Short() {
+		t.Skip("the generated code is not built in short mode")
+	}
+	goBinary, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("cannot find the go binary")
+	}
+
+	tmp := t.TempDir()
+	gitRepo := "github.com/kubewarden/k8s-objects"
+	project := Project{
+		OutputDir: tmp,
+		GitRepo:   gitRepo,
+		Root:      filepath.Join(tmp, "src", gitRepo),
+	}
+	plan := newGeneratedCodeTestPlan(t)
+
+	writeModels(t, project, plan)
+	generators := []struct {
+		name     string
+		generate func(Project, *RefactoringPlan) error
+	}{
+		{"IntOrString", GenerateIntOrString},
+		{"Quantity", GenerateQuantity},
+		{"type override checks", GenerateTypeOverrideChecks},
+		{"GroupVersionKind helpers", GenerateGroupVersionKindFiles},
+		{"Object interface", GenerateObjectInterface},
+		{"PodSpec accessors", GeneratePodSpecAccessors},
+		{"patch metadata", GeneratePatchMetadata},
+		{"getters", GenerateGetters},
+		{"DeepCopy methods", GenerateDeepCopy},
+		{"Equal methods", GenerateEqual},
+		{"JSON patch diffs", GenerateJSONPatchDiffs},
+		{"kind registry", GenerateRegistry},
+		{"embedded object helpers", GenerateEmbeddedObjectHelpers},
+		{"round-trip tests", GenerateRoundTripTests},
+	}
+	for _, generator := range generators {
+		if err := generator.generate(project, plan); err != nil {
+			t.Fatalf("cannot generate %s: %v", generator.name, err)
+		}
+	}
+
+	stubs := filepath.Join(tmp, "stubs")
+	copyDir(t, filepath.Join(GENERATED_CODE_TESTDATA, "stubs"), stubs)
+	copyDir(t, filepath.Join(GENERATED_CODE_TESTDATA, "project"), project.Root)
+
+	easyjsonVersion := requiredVersion(t, "github.com/mailru/easyjson")
+	writeFile(t, filepath.Join(stubs, "strfmt", "go.mod"), []byte("module github.com/go-openapi/strfmt\n\ngo 1.17\n"))
+	writeFile(t, filepath.Join(stubs, "types", "go.mod"), []byte(fmt.Sprintf(
+		"module github.com/example/types\n\ngo 1.17\n\nrequire github.com/mailru/easyjson %s\n", easyjsonVersion)))
+	writeFile(t, filepath.Join(project.Root, "go.mod"), []byte(fmt.Sprintf(`module %s
+
+go 1.17
+
+require (
+	github.com/example/types v0.0.0
+	github.com/go-openapi/strfmt v0.0.0
+	github.com/mailru/easyjson %s
+)
+
+replace (
+	github.com/example/types => %s
+	github.com/go-openapi/strfmt => %s
+)
+`, gitRepo, easyjsonVersion, filepath.Join(stubs, "types"), filepath.Join(stubs, "strfmt"))))
+
+	for _, args := range [][]string{{"vet", "./..."}, {"test", "./..."}} {
+		cmd := exec.Command(goBinary, args...)
+		cmd.Dir = project.Root
+		cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off", "GOSUMDB=off", "GOWORK=off")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("go %s failed: %v\n%s", strings.Join(args, " "), err, output)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package split
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/template"
+
+	"github.com/go-openapi/swag"
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
+	"github.com/pkg/errors"
+)
+
+const GETTERS_FILE_NAME = "getters.go"
+
+const GETTERS_TEMPLATE = `// Code generated by k8s-objects-generator. DO NOT EDIT.
+
+package {{ .Package }}
+{{ if .Imports }}
+import (
+{{- range $path, $alias := .Imports }}
+	{{ $alias }} "{{ $path }}"
+{{- end }}
+)
+{{ end }}
+{{- range .Types }}
+{{- $typeName := .TypeName }}
+{{- range .Getters }}
+// Get{{ .Field }} returns {{ .JSONName }}, the zero value when it's not set or m is nil
+func (m *{{ $typeName }}) Get{{ .Field }}() {{ .ReturnType }} {
+	if m == nil{{ if .Deref }} || m.{{ .Field }} == nil{{ end }} {
+		return {{ .Zero }}
+	}
+	return {{ if .Deref }}*{{ end }}m.{{ .Field }}
+}
+{{ end }}
+{{- end }}
+`
+
+type getter struct {
+	Field      string
+	JSONName   string
+	ReturnType string
+	Zero       string
+	// true when the field is a pointer to a value that is returned by copy
+	Deref bool
+}
+
+type gettersType struct {
+	TypeName string
+	Getters  []getter
+}
+
+// Writes, for each package, the nil-safe getters of the fields of its structs
+func GenerateGetters(project Project, plan *RefactoringPlan) error {
+	resolver := swagger_helpers.NewGoTypeResolver(plan.Definitions, &plan.Interfaces, project.GitRepo)
+	podSpecs := podSpecPaths(plan.Definitions)
+
+	for pkgName, pkg := range plan.Packages {
+		contents, err := renderGetters(pkg, &resolver, podSpecs, project.GitRepo, &plan.Interfaces)
+		if err != nil {
+			return errors.Wrapf(err, "cannot render getters of package %s", pkgName)
+		}
+		if contents == nil {
+			continue
+		}
+
+		fileName := filepath.Join(project.Root, pkgName, GETTERS_FILE_NAME)
+		if err := os.WriteFile(fileName, contents, 0644); err != nil {
+			return errors.Wrapf(err, "cannot write %s", fileName)
+		}
+	}
+
+	return nil
+}
+
+// Returns the names of the getter-like methods that are already generated for
+// the definition, like the ones of the `Object` interface
+func reservedGetters(def *swagger_helpers.Definition, podSpecs map[string][]string, gitRepo string, interfaces *swagger_helpers.InterfaceRegistry) map[string]bool {
+	reserved := make(map[string]bool)
+	if isObject(def, gitRepo, interfaces) {
+		reserved["GetObjectMeta"] = true
+		reserved["GetAPIVersion"] = true
+		reserved["GetKind"] = true
+	}
+	if _, found := podSpecs[def.ID]; found {
+		reserved["GetPodSpec"] = true
+	}
+	return reserved
+}
+
+// Returns the contents of the file holding the getters of the structs of the
+// package, nil when the package doesn't define any struct
+func renderGetters(
+	pkg swagger_helpers.Package,
+	resolver *swagger_helpers.GoTypeResolver,
+	podSpecs map[string][]string,
+	gitRepo string,
+	interfaces *swagger_helpers.InterfaceRegistry,
+) ([]byte, error) {
+	imports := make(map[string]string)
+	types := []gettersType{}
+
+	for _, def := range pkg.Definitions {
+		if override, found := resolver.Override(def); found && override.Import != "" {
+			// the type is declared by another package
+			continue
+		}
+
+		defType, err := resolver.DefinitionType(def, pkg.Name)
+		if err != nil {
+			return nil, err
+		}
+		if defType.Kind != swagger_helpers.StructGoType {
+			continue
+		}
+
+		reserved := reservedGetters(def, podSpecs, gitRepo, interfaces)
+		getters := []getter{}
+		for name := range def.SwaggerDefinition.Properties {
+			field := swag.ToGoName(name)
+			if reserved["Get"+field] {
+				continue
+			}
+
+			goType, err := resolver.PropertyType(def, name)
+			if err != nil {
+				return nil, errors.Wrapf(err, "cannot generate getters of %s", def.ID)
+			}
+
+			g := getter{
+				Field:      field,
+				JSONName:   name,
+				ReturnType: goType.Expr(),
+				Zero:       "nil",
+			}
+			switch {
+			case goType.Pointer && goType.Kind == swagger_helpers.ScalarGoType:
+				g.Deref = true
+				g.ReturnType = goType.Deref().Expr()
+				g.Zero = goType.Zero
+			case !goType.Pointer && (goType.Kind == swagger_helpers.ScalarGoType || goType.Kind == swagger_helpers.OpaqueGoType):
+				g.Zero = goType.Zero
+			case !goType.Pointer && goType.Kind == swagger_helpers.StructGoType:
+				g.Zero = goType.Name + "{}"
+			}
+			goType.CollectImports(imports)
+
+			getters = append(getters, g)
+		}
+		if len(getters) == 0 {
+			continue
+		}
+		sort.Slice(getters, func(i, j int) bool {
+			return getters[i].Field < getters[j].Field
+		})
+
+		types = append(types, gettersType{
+			TypeName: swag.ToGoName(def.TypeName),
+			Getters:  getters,
+		})
+	}
+
+	if len(types) == 0 {
+		return nil, nil
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].TypeName < types[j].TypeName
+	})
+
+	gettersTemplate, err := template.New("getters").Parse(GETTERS_TEMPLATE)
+	if err != nil {
+		return nil, err
+	}
+
+	templateData := struct {
+		Package string
+		Imports map[string]string
+		Types   []gettersType
+	}{
+		Package: filepath.Base(pkg.Name),
+		Imports: imports,
+		Types:   types,
+	}
+
+	var buf bytes.Buffer
+	if err := gettersTemplate.Execute(&buf, templateData); err != nil {
+		return nil, err
+	}
+
+	contents, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("cannot format generated code: %v", err)
+	}
+
+	return contents, nil
+}
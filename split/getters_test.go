@@ -0,0 +1,26 @@
+package split
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
+)
+
+// The behaviour of the generated getters is tested by the getters tests kept
+// inside of testdata, which run against the generated code. Generating the
+// getters of the fields already handled by the Object interface makes the
+// build of the generated code fail
+func TestRenderGetters(t *testing.T) {
+	gitRepo := "github.com/kubewarden/k8s-objects"
+	plan := newGeneratedCodeTestPlan(t)
+	resolver := swagger_helpers.NewGoTypeResolver(plan.Definitions, &plan.Interfaces, gitRepo)
+
+	contents, err := renderGetters(plan.Packages["api/core/v1"], &resolver, podSpecPaths(plan.Definitions), gitRepo, &plan.Interfaces)
+	if err != nil {
+		t.Fatalf("cannot render getters: %v", err)
+	}
+	if code := string(contents); !strings.Contains(code, "func (m *PodSpec) GetHostname() string {") {
+		t.Errorf("cannot find GetHostname of PodSpec inside of generated code:\n%s", code)
+	}
+}
@@ -0,0 +1,466 @@
+package split
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Exported Go API of a package found inside of a generated tree
+type GoPackageAPI struct {
+	// Exported types, indexed by name
+	Types map[string]GoTypeAPI
+	// Exported functions, indexed by name. The value is the signature
+	Functions map[string]string
+	// Exported constants and variables, indexed by name. The value is
+	// either `const` or `var`
+	Values map[string]string
+}
+
+// The types are written with the import paths of their packages in place of
+// the package names, e.g. `*"github.com/example/types".Time`: renaming an
+// import doesn't change the API
+type GoTypeAPI struct {
+	// The type expression, `struct` for struct types
+	Definition string
+	// Exported fields, indexed by name, the embedded ones by type name. The
+	// value is the type of the field
+	Fields map[string]string
+	// Exported methods, indexed by name
+	Methods map[string]GoMethodAPI
+}
+
+type GoMethodAPI struct {
+	PointerReceiver bool
+	Signature       string
+}
+
+// Parses all the Go packages found under `root`. The packages are indexed
+// by their path relative to `root`, test files are ignored. Like the go
+// tool, the `vendor` and `testdata` directories are skipped, together with
+// the ones starting with `.` or `_`, e.g. `.git`
+func LoadGoAPI(root string) (map[string]GoPackageAPI, error) {
+	packages := make(map[string]GoPackageAPI)
+
+	walkDirFn := func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root && isIgnoredGoDir(d.Name()) {
+			return filepath.SkipDir
+		}
+
+		pkgAPI, found, err := loadGoPackageAPI(path)
+		if err != nil {
+			return errors.Wrapf(err, "cannot parse package %s", path)
+		}
+		if !found {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		packages[filepath.ToSlash(relPath)] = pkgAPI
+		return nil
+	}
+	if err := filepath.WalkDir(root, walkDirFn); err != nil {
+		return nil, err
+	}
+
+	return packages, nil
+}
+
+func isIgnoredGoDir(name string) bool {
+	return name == "vendor" || name == "testdata" ||
+		strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
+
+func loadGoPackageAPI(dir string) (GoPackageAPI, bool, error) {
+	pkgAPI := GoPackageAPI{
+		Types:     make(map[string]GoTypeAPI),
+		Functions: make(map[string]string),
+		Values:    make(map[string]string),
+	}
+
+	// the files are selected like the go tool does: test files and the ones
+	// excluded by build constraints are ignored
+	buildPkg, err := build.ImportDir(dir, 0)
+	if err != nil {
+		if _, noGo := err.(*build.NoGoError); noGo {
+			return pkgAPI, false, nil
+		}
+		return pkgAPI, false, err
+	}
+
+	fset := token.NewFileSet()
+	files := []*ast.File{}
+	for _, name := range buildPkg.GoFiles {
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+		if err != nil {
+			return pkgAPI, false, err
+		}
+		qualifyImportedTypes(file)
+		files = append(files, file)
+	}
+
+	methods := []*ast.FuncDecl{}
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				collectGenDecl(&pkgAPI, decl)
+			case *ast.FuncDecl:
+				if !decl.Name.IsExported() {
+					continue
+				}
+				if decl.Recv == nil {
+					pkgAPI.Functions[decl.Name.Name] = types.ExprString(decl.Type)
+				} else {
+					methods = append(methods, decl)
+				}
+			}
+		}
+	}
+
+	// methods are processed last, all the types are known at this point
+	for _, method := range methods {
+		recvType := method.Recv.List[0].Type
+		pointerReceiver := false
+		if star, ok := recvType.(*ast.StarExpr); ok {
+			pointerReceiver = true
+			recvType = star.X
+		}
+		ident, ok := recvType.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		typeAPI, found := pkgAPI.Types[ident.Name]
+		if !found {
+			continue
+		}
+		typeAPI.Methods[method.Name.Name] = GoMethodAPI{
+			PointerReceiver: pointerReceiver,
+			Signature:       types.ExprString(method.Type),
+		}
+	}
+
+	return pkgAPI, true, nil
+}
+
+func collectGenDecl(pkgAPI *GoPackageAPI, decl *ast.GenDecl) {
+	for _, spec := range decl.Specs {
+		switch spec := spec.(type) {
+		case *ast.TypeSpec:
+			if !spec.Name.IsExported() {
+				continue
+			}
+			typeAPI := GoTypeAPI{
+				Fields:  make(map[string]string),
+				Methods: make(map[string]GoMethodAPI),
+			}
+			if structType, ok := spec.Type.(*ast.StructType); ok {
+				typeAPI.Definition = "struct"
+				for _, field := range structType.Fields.List {
+					fieldType := types.ExprString(field.Type)
+					if len(field.Names) == 0 {
+						// embedded fields are named after their type, like
+						// go/types does
+						if name := embeddedFieldName(field.Type); ast.IsExported(name) {
+							typeAPI.Fields[name] = fieldType
+						}
+					}
+					for _, name := range field.Names {
+						if name.IsExported() {
+							typeAPI.Fields[name.Name] = fieldType
+						}
+					}
+				}
+			} else {
+				typeAPI.Definition = types.ExprString(spec.Type)
+				if spec.Assign.IsValid() {
+					typeAPI.Definition = "= " + typeAPI.Definition
+				}
+			}
+			pkgAPI.Types[spec.Name.Name] = typeAPI
+		case *ast.ValueSpec:
+			for _, name := range spec.Names {
+				if name.IsExported() {
+					pkgAPI.Values[name.Name] = decl.Tok.String()
+				}
+			}
+		}
+	}
+}
+
+// Returns the name of an embedded field, the name of its type without
+// package and type arguments
+func embeddedFieldName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedFieldName(expr.X)
+	case *ast.IndexExpr:
+		return embeddedFieldName(expr.X)
+	case *ast.IndexListExpr:
+		return embeddedFieldName(expr.X)
+	case *ast.SelectorExpr:
+		return expr.Sel.Name
+	case *ast.Ident:
+		return expr.Name
+	}
+	return types.ExprString(expr)
+}
+
+var versionSuffixRegexp = regexp.MustCompile(`^v[0-9]+$`)
+
+// Replaces the package names of the qualified identifiers of the file with
+// the quoted import paths of the packages. Without an explicit name, the
+// package is assumed to be named after the last element of its path, or
+// the previous one for major version suffixes like `/v2`
+func qualifyImportedTypes(file *ast.File) {
+	packages := make(map[string]string)
+	implicit := make(map[string]string)
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		if spec.Name != nil {
+			packages[spec.Name.Name] = importPath
+			continue
+		}
+		name := path.Base(importPath)
+		implicit[name] = importPath
+		if parent := path.Dir(importPath); versionSuffixRegexp.MatchString(name) && parent != "." {
+			implicit[path.Base(parent)] = importPath
+		}
+	}
+	for name, importPath := range implicit {
+		if _, found := packages[name]; !found {
+			packages[name] = importPath
+		}
+	}
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		selector, ok := node.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		// package names are not resolved by the parser, unlike the local
+		// identifiers
+		if ident, ok := selector.X.(*ast.Ident); ok && ident.Obj == nil {
+			if importPath, found := packages[ident.Name]; found {
+				ident.Name = strconv.Quote(importPath)
+			}
+		}
+		return true
+	})
+}
+
+// A difference between the exported API of two generated trees
+type GoAPIChange struct {
+	Package     string `json:"package"`
+	Symbol      string `json:"symbol"`
+	Description string `json:"description"`
+	Breaking    bool   `json:"breaking"`
+}
+
+// Compares the exported API of two generated trees. Removals and changes
+// of existing symbols are breaking, additions are compatible.
+func DiffGoAPI(oldAPI, newAPI map[string]GoPackageAPI) []GoAPIChange {
+	changes := []GoAPIChange{}
+
+	for pkgName, oldPkg := range oldAPI {
+		newPkg, found := newAPI[pkgName]
+		if !found {
+			changes = append(changes, GoAPIChange{
+				Package:     pkgName,
+				Description: "package removed",
+				Breaking:    true,
+			})
+			continue
+		}
+		changes = append(changes, diffGoPackageAPI(pkgName, oldPkg, newPkg)...)
+	}
+	for pkgName := range newAPI {
+		if _, found := oldAPI[pkgName]; !found {
+			changes = append(changes, GoAPIChange{
+				Package:     pkgName,
+				Description: "package added",
+			})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Package != changes[j].Package {
+			return changes[i].Package < changes[j].Package
+		}
+		return changes[i].Symbol < changes[j].Symbol
+	})
+
+	return changes
+}
+
+func diffGoPackageAPI(pkgName string, oldPkg, newPkg GoPackageAPI) []GoAPIChange {
+	changes := []GoAPIChange{}
+	change := func(symbol string, breaking bool, format string, args ...interface{}) {
+		changes = append(changes, GoAPIChange{
+			Package:     pkgName,
+			Symbol:      symbol,
+			Description: fmt.Sprintf(format, args...),
+			Breaking:    breaking,
+		})
+	}
+
+	for typeName, oldType := range oldPkg.Types {
+		newType, found := newPkg.Types[typeName]
+		if !found {
+			change(typeName, true, "type removed")
+			continue
+		}
+		if oldType.Definition != newType.Definition {
+			change(typeName, true, "type changed from %s to %s", oldType.Definition, newType.Definition)
+			continue
+		}
+
+		for fieldName, oldField := range oldType.Fields {
+			symbol := fmt.Sprintf("%s.%s", typeName, fieldName)
+			newField, found := newType.Fields[fieldName]
+			if !found {
+				change(symbol, true, "field removed")
+			} else if oldField != newField {
+				change(symbol, true, "field type changed from %s to %s", oldField, newField)
+			}
+		}
+		for fieldName, newField := range newType.Fields {
+			if _, found := oldType.Fields[fieldName]; !found {
+				change(fmt.Sprintf("%s.%s", typeName, fieldName), false, "field added with type %s", newField)
+			}
+		}
+
+		for methodName, oldMethod := range oldType.Methods {
+			symbol := fmt.Sprintf("%s.%s", typeName, methodName)
+			newMethod, found := newType.Methods[methodName]
+			switch {
+			case !found:
+				change(symbol, true, "method removed")
+			case oldMethod.Signature != newMethod.Signature:
+				change(symbol, true, "method signature changed from %s to %s",
+					oldMethod.Signature, newMethod.Signature)
+			case !oldMethod.PointerReceiver && newMethod.PointerReceiver:
+				// values of the type do not implement the method anymore
+				change(symbol, true, "method receiver changed from value to pointer")
+			case oldMethod.PointerReceiver && !newMethod.PointerReceiver:
+				change(symbol, false, "method receiver changed from pointer to value")
+			}
+		}
+		for methodName := range newType.Methods {
+			if _, found := oldType.Methods[methodName]; !found {
+				change(fmt.Sprintf("%s.%s", typeName, methodName), false, "method added")
+			}
+		}
+	}
+	for typeName := range newPkg.Types {
+		if _, found := oldPkg.Types[typeName]; !found {
+			change(typeName, false, "type added")
+		}
+	}
+
+	for funcName, oldSignature := range oldPkg.Functions {
+		newSignature, found := newPkg.Functions[funcName]
+		if !found {
+			change(funcName, true, "function removed")
+		} else if oldSignature != newSignature {
+			change(funcName, true, "function signature changed from %s to %s", oldSignature, newSignature)
+		}
+	}
+	for funcName := range newPkg.Functions {
+		if _, found := oldPkg.Functions[funcName]; !found {
+			change(funcName, false, "function added")
+		}
+	}
+
+	for valueName, oldKind := range oldPkg.Values {
+		newKind, found := newPkg.Values[valueName]
+		if !found {
+			change(valueName, true, "%s removed", oldKind)
+		} else if oldKind != newKind {
+			change(valueName, true, "changed from %s to %s", oldKind, newKind)
+		}
+	}
+	for valueName, newKind := range newPkg.Values {
+		if _, found := oldPkg.Values[valueName]; !found {
+			change(valueName, false, "%s added", newKind)
+		}
+	}
+
+	return changes
+}
+
+// Returns true when at least one of the changes is breaking
+func HasBreakingGoAPIChanges(changes []GoAPIChange) bool {
+	for _, change := range changes {
+		if change.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// Writes a human readable report of the changes, breaking changes are
+// listed first
+func WriteGoAPIChanges(w io.Writer, changes []GoAPIChange) error {
+	var b strings.Builder
+
+	if len(changes) == 0 {
+		b.WriteString("No Go API changes\n")
+	}
+
+	sections := []struct {
+		title    string
+		breaking bool
+	}{
+		{"Breaking changes", true},
+		{"Compatible changes", false},
+	}
+	for _, section := range sections {
+		header := false
+		for _, change := range changes {
+			if change.Breaking != section.breaking {
+				continue
+			}
+			if !header {
+				fmt.Fprintf(&b, "%s:\n", section.title)
+				header = true
+			}
+			symbol := change.Package
+			if change.Symbol != "" {
+				symbol = fmt.Sprintf("%s.%s", change.Package, change.Symbol)
+			}
+			fmt.Fprintf(&b, "  %s: %s\n", symbol, change.Description)
+		}
+		if header {
+			b.WriteString("\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,207 @@
+package split
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeGoFile(t *testing.T, root, pkg, contents string) {
+	dir := filepath.Join(root, pkg)
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		t.Fatalf("cannot create %s: %v", dir, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "types.go"), []byte(contents), 0644); err != nil {
+		t.Fatalf("cannot write go file: %v", err)
+	}
+}
+
+func TestDiffGoAPI(t *testing.T) {
+	oldRoot := t.TempDir()
+	newRoot := t.TempDir()
+
+	writeGoFile(t, oldRoot, "api/core/v1", `package v1
+
+const PodKind = "Pod"
+
+type Pod struct {
+	Kind     string
+	Spec     *PodSpec
+	Hostname string
+	internal string
+}
+
+type PodSpec struct {
+	Containers []*Container
+}
+
+type Container struct {
+	Name *string
+}
+
+func (m Pod) GetKind() string { return m.Kind }
+
+func (m *Pod) Validate() error { return nil }
+`)
+	writeGoFile(t, oldRoot, "api/batch/v2alpha1", `package v2alpha1
+
+type CronJob struct{}
+`)
+
+	writeGoFile(t, newRoot, "api/core/v1", `package v1
+
+type Pod struct {
+	Kind     string
+	Spec     PodSpec
+	Overhead string
+}
+
+type PodSpec struct {
+	Containers []*Container
+}
+
+type Container struct {
+	Name *string
+}
+
+func NewPod() *Pod { return &Pod{} }
+
+func (m *Pod) GetKind() string { return m.Kind }
+
+func (m Pod) Validate() error { return nil }
+`)
+
+	oldAPI, err := LoadGoAPI(oldRoot)
+	if err != nil {
+		t.Fatalf("cannot load old API: %v", err)
+	}
+	newAPI, err := LoadGoAPI(newRoot)
+	if err != nil {
+		t.Fatalf("cannot load new API: %v", err)
+	}
+
+	changes := DiffGoAPI(oldAPI, newAPI)
+
+	expected := []GoAPIChange{
+		{Package: "api/batch/v2alpha1", Symbol: "", Description: "package removed", Breaking: true},
+		{Package: "api/core/v1", Symbol: "NewPod", Description: "function added", Breaking: false},
+		{Package: "api/core/v1", Symbol: "Pod.GetKind", Description: "method receiver changed from value to pointer", Breaking: true},
+		{Package: "api/core/v1", Symbol: "Pod.Hostname", Description: "field removed", Breaking: true},
+		{Package: "api/core/v1", Symbol: "Pod.Overhead", Description: "field added with type string", Breaking: false},
+		{Package: "api/core/v1", Symbol: "Pod.Spec", Description: "field type changed from *PodSpec to PodSpec", Breaking: true},
+		{Package: "api/core/v1", Symbol: "Pod.Validate", Description: "method receiver changed from pointer to value", Breaking: false},
+		{Package: "api/core/v1", Symbol: "PodKind", Description: "const removed", Breaking: true},
+	}
+
+	if len(changes) != len(expected) {
+		t.Fatalf("wrong number of changes, expected %d got %d: %+v", len(expected), len(changes), changes)
+	}
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Errorf("wrong change, expected %+v got %+v instead", expected[i], changes[i])
+		}
+	}
+
+	if !HasBreakingGoAPIChanges(changes) {
+		t.Errorf("breaking changes not detected")
+	}
+	if HasBreakingGoAPIChanges(DiffGoAPI(oldAPI, oldAPI)) {
+		t.Errorf("a tree compared to itself must not have breaking changes")
+	}
+
+	var report bytes.Buffer
+	if err := WriteGoAPIChanges(&report, changes); err != nil {
+		t.Fatalf("cannot write report: %v", err)
+	}
+	breakingIdx := strings.Index(report.String(), "Breaking changes:")
+	compatibleIdx := strings.Index(report.String(), "Compatible changes:")
+	if breakingIdx == -1 || compatibleIdx == -1 || breakingIdx > compatibleIdx {
+		t.Errorf("wrong report layout:\n%s", report.String())
+	}
+}
+
+func TestLoadGoAPISkippedFiles(t *testing.T) {
+	root := t.TempDir()
+
+	writeGoFile(t, root, "api/core/v1", "package v1\n\ntype Pod struct{}\n")
+	for _, pkg := range []string{".git/hooks", "vendor/example.com/lib", "testdata/v1", "_output/v1", "api/core/v1/testdata"} {
+		writeGoFile(t, root, pkg, "package v1\n\ntype Ignored struct{}\n")
+	}
+	// files excluded by build constraints are not part of the API
+	ignored := "//go:build ignore\n\npackage main\n\ntype Tool struct{}\n"
+	if err := os.WriteFile(filepath.Join(root, "api/core/v1", "tool.go"), []byte(ignored), 0644); err != nil {
+		t.Fatalf("cannot write go file: %v", err)
+	}
+
+	packages, err := LoadGoAPI(root)
+	if err != nil {
+		t.Fatalf("cannot load go API: %v", err)
+	}
+	if len(packages) != 1 {
+		t.Errorf("expected only api/core/v1 to be loaded, got %v", packages)
+	}
+	pkgAPI, found := packages["api/core/v1"]
+	if !found {
+		t.Fatalf("cannot find api/core/v1")
+	}
+	if _, found := pkgAPI.Types["Pod"]; !found || len(pkgAPI.Types) != 1 {
+		t.Errorf("wrong types: %v", pkgAPI.Types)
+	}
+}
+
+func TestDiffGoAPIImportAliases(t *testing.T) {
+	oldRoot := t.TempDir()
+	newRoot := t.TempDir()
+
+	writeGoFile(t, oldRoot, "api/core/v1", `package v1
+
+import (
+	"github.com/example/types"
+	metav1 "github.com/kubewarden/k8s-objects/apimachinery/pkg/apis/meta/v1"
+)
+
+type Pod struct {
+	*metav1.ObjectMeta
+	StartedAt *types.Time
+}
+
+func (p *Pod) GetObjectMeta() *metav1.ObjectMeta {
+	return p.ObjectMeta
+}
+`)
+	writeGoFile(t, newRoot, "api/core/v1", `package v1
+
+import (
+	example_types "github.com/example/types"
+	"github.com/kubewarden/k8s-objects/apimachinery/pkg/apis/meta/v1"
+)
+
+type Pod struct {
+	*v1.ObjectMeta
+	StartedAt *example_types.Time
+}
+
+func (p *Pod) GetObjectMeta() *v1.ObjectMeta {
+	return p.ObjectMeta
+}
+`)
+
+	oldAPI, err := LoadGoAPI(oldRoot)
+	if err != nil {
+		t.Fatalf("cannot load old API: %v", err)
+	}
+	newAPI, err := LoadGoAPI(newRoot)
+	if err != nil {
+		t.Fatalf("cannot load new API: %v", err)
+	}
+
+	if changes := DiffGoAPI(oldAPI, newAPI); len(changes) != 0 {
+		t.Errorf("renaming the imports must not change the API, got %+v", changes)
+	}
+	fields := newAPI["api/core/v1"].Types["Pod"].Fields
+	if fields["ObjectMeta"] != `*"github.com/kubewarden/k8s-objects/apimachinery/pkg/apis/meta/v1".ObjectMeta` {
+		t.Errorf("embedded fields must be named after their type: %v", fields)
+	}
+}
@@ -0,0 +1,270 @@
+package split
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/go-openapi/swag"
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
+	"github.com/pkg/errors"
+)
+
+const GVK_FILE_NAME = "group_version_kind.go"
+
+// Contents of the package providing the GroupVersionKind type shared by all
+// the kinds. The package is named by the package mapping rules, like
+// `apimachinery/pkg/runtime/schema` for the Kubernetes definitions, and it's
+// always imported as `schema`
+const SCHEMA_PACKAGE_TEMPLATE = `// Code generated by k8s-objects-generator. DO NOT EDIT.
+
+package {{ .Package }}
+
+import "strings"
+
+// GroupVersionKind unambiguously identifies a kind
+type GroupVersionKind struct {
+	Group   string
+	Version string
+	Kind    string
+}
+
+// FromAPIVersionAndKind returns the GroupVersionKind described by the
+// apiVersion and kind fields of an object
+func FromAPIVersionAndKind(apiVersion, kind string) GroupVersionKind {
+	gvk := GroupVersionKind{Kind: kind}
+	if idx := strings.LastIndex(apiVersion, "/"); idx >= 0 {
+		gvk.Group = apiVersion[:idx]
+		gvk.Version = apiVersion[idx+1:]
+	} else {
+		gvk.Version = apiVersion
+	}
+	return gvk
+}
+
+// APIVersion returns the value of the apiVersion field of the kind, e.g.
+// "apps/v1" or "v1" for the kinds of the core group
+func (gvk GroupVersionKind) APIVersion() string {
+	if gvk.Group == "" {
+		return gvk.Version
+	}
+	return gvk.Group + "/" + gvk.Version
+}
+
+// Empty returns true when all the fields are empty
+func (gvk GroupVersionKind) Empty() bool {
+	return gvk.Group == "" && gvk.Version == "" && gvk.Kind == ""
+}
+
+func (gvk GroupVersionKind) String() string {
+	return gvk.APIVersion() + ", Kind=" + gvk.Kind
+}
+`
+
+const GVK_TEMPLATE = `// Code generated by k8s-objects-generator. DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+	schema "{{ .SchemaImport }}"
+)
+{{ range .Kinds }}
+{{- if .GVK }}
+const (
+	// {{ .TypeName }}Group is the API group of {{ .TypeName }}
+	{{ .TypeName }}Group = {{ printf "%q" .GVK.Group }}
+
+	// {{ .TypeName }}Version is the API version of {{ .TypeName }}
+	{{ .TypeName }}Version = {{ printf "%q" .GVK.Version }}
+
+	// {{ .TypeName }}Kind is the kind of {{ .TypeName }}
+	{{ .TypeName }}Kind = {{ printf "%q" .GVK.Kind }}
+)
+
+// New{{ .TypeName }} returns a {{ .TypeName }} with its apiVersion and kind fields set
+func New{{ .TypeName }}() *{{ .TypeName }} {
+	return &{{ .TypeName }}{
+		APIVersion: {{ printf "%q" .GVK.APIVersion }},
+		Kind:       {{ .TypeName }}Kind,
+	}
+}
+{{- end }}
+
+// GroupVersionKind returns the GroupVersionKind described by the apiVersion and kind fields
+func (m *{{ .TypeName }}) GroupVersionKind() schema.GroupVersionKind {
+	return schema.FromAPIVersionAndKind(m.APIVersion, m.Kind)
+}
+
+// SetGroupVersionKind sets the apiVersion and kind fields
+func (m *{{ .TypeName }}) SetGroupVersionKind(gvk schema.GroupVersionKind) {
+	m.APIVersion = gvk.APIVersion()
+	m.Kind = gvk.Kind
+}
+{{ end }}
+`
+
+type gvkTemplateKind struct {
+	TypeName string
+	// nil when the type is shared by many kinds, like `DeleteOptions`
+	GVK *swagger_helpers.GroupVersionKind
+}
+
+// Writes the package providing the GroupVersionKind type and, for each
+// package that defines top-level kinds, a file with their Group, Version and
+// Kind constants, a constructor and the GroupVersionKind accessors
+func GenerateGroupVersionKindFiles(project Project, plan *RefactoringPlan) error {
+	schemaPackage, err := plan.Naming.SchemaPackage()
+	if err != nil {
+		return err
+	}
+	if _, found := plan.Packages[schemaPackage]; found {
+		return fmt.Errorf("cannot generate the GroupVersionKind type inside of %s, the package holds definitions too", schemaPackage)
+	}
+
+	schemaDir := filepath.Join(project.Root, schemaPackage)
+	if err := os.MkdirAll(schemaDir, 0777); err != nil {
+		return errors.Wrapf(err, "cannot create dir %s", schemaDir)
+	}
+	schemaTemplate, err := template.New("schema").Parse(SCHEMA_PACKAGE_TEMPLATE)
+	if err != nil {
+		return err
+	}
+	var schemaContents bytes.Buffer
+	if err := schemaTemplate.Execute(&schemaContents, struct{ Package string }{filepath.Base(schemaPackage)}); err != nil {
+		return err
+	}
+	schemaFileName := filepath.Join(schemaDir, GVK_FILE_NAME)
+	if err := os.WriteFile(schemaFileName, schemaContents.Bytes(), 0644); err != nil {
+		return errors.Wrapf(err, "cannot write %s", schemaFileName)
+	}
+
+	for pkgName, pkg := range plan.Packages {
+		declared, err := declaredIdentifiers(filepath.Join(project.Root, pkgName), GVK_FILE_NAME)
+		if err != nil {
+			return errors.Wrapf(err, "cannot parse package %s", pkgName)
+		}
+		contents, err := renderGroupVersionKinds(pkg, project.GitRepo, schemaPackage, &plan.Interfaces, declared)
+		if err != nil {
+			return errors.Wrapf(err, "cannot render GroupVersionKind helpers of package %s", pkgName)
+		}
+		if contents == nil {
+			continue
+		}
+
+		fileName := filepath.Join(project.Root, pkgName, GVK_FILE_NAME)
+		if err := os.WriteFile(fileName, contents, 0644); err != nil {
+			return errors.Wrapf(err, "cannot write %s", fileName)
+		}
+	}
+
+	return nil
+}
+
+// Returns the names of the top-level declarations of the Go files already
+// written inside of the package, with the exception of the tests and of the
+// file about to be generated
+func declaredIdentifiers(dir string, fileName string) (map[string]bool, error) {
+	filter := func(info os.FileInfo) bool {
+		return info.Name() != fileName && !strings.HasSuffix(info.Name(), "_test.go")
+	}
+	packages, err := parser.ParseDir(token.NewFileSet(), dir, filter, 0)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	declared := make(map[string]bool)
+	for _, pkg := range packages {
+		for _, file := range pkg.Files {
+			for name := range file.Scope.Objects {
+				declared[name] = true
+			}
+		}
+	}
+	return declared, nil
+}
+
+// Returns the contents of the GroupVersionKind helpers of the given package,
+// nil when the package doesn't define any kind. The generation fails when the
+// constants or the constructor of a kind clash with the types of the package
+// or with the identifiers already declared by its files
+func renderGroupVersionKinds(
+	pkg swagger_helpers.Package,
+	gitRepo string,
+	schemaPackage string,
+	interfaces *swagger_helpers.InterfaceRegistry,
+	declared map[string]bool,
+) ([]byte, error) {
+	kinds := []gvkTemplateKind{}
+
+	typeNames := make(map[string]bool)
+	for _, def := range pkg.Definitions {
+		typeNames[swag.ToGoName(def.TypeName)] = true
+	}
+
+	for _, def := range pkg.Definitions {
+		if !isKind(def, gitRepo, interfaces) {
+			continue
+		}
+		gvks := def.GroupVersionKinds()
+
+		kind := gvkTemplateKind{TypeName: swag.ToGoName(def.TypeName)}
+		if len(gvks) == 1 {
+			kind.GVK = &gvks[0]
+			for _, name := range []string{kind.TypeName + "Group", kind.TypeName + "Version", kind.TypeName + "Kind", "New" + kind.TypeName} {
+				if typeNames[name] || declared[name] {
+					return nil, fmt.Errorf("cannot generate %s for %s: the package already declares it", name, def.ID)
+				}
+			}
+		}
+		kinds = append(kinds, kind)
+	}
+
+	if len(kinds) == 0 {
+		return nil, nil
+	}
+	sort.Slice(kinds, func(i, j int) bool {
+		return kinds[i].TypeName < kinds[j].TypeName
+	})
+
+	gvkTemplate, err := template.New("group_version_kind").Parse(GVK_TEMPLATE)
+	if err != nil {
+		return nil, err
+	}
+
+	templateData := struct {
+		Package      string
+		SchemaImport string
+		Kinds        []gvkTemplateKind
+	}{
+		Package:      filepath.Base(pkg.Name),
+		SchemaImport: fmt.Sprintf("%s/%s", gitRepo, schemaPackage),
+		Kinds:        kinds,
+	}
+
+	var buf bytes.Buffer
+	if err := gvkTemplate.Execute(&buf, templateData); err != nil {
+		return nil, err
+	}
+
+	contents, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("cannot format generated code: %v", err)
+	}
+
+	return contents, nil
+}
+
+// Returns true when the definition describes a top-level kind, these are the
+// types that get the GroupVersionKind helpers
+func isKind(def *swagger_helpers.Definition, gitRepo string, interfaces *swagger_helpers.InterfaceRegistry) bool {
+	return len(def.GroupVersionKinds()) > 0 &&
+		def.HasTypeMeta() &&
+		!interfaces.IsInterface(gitRepo, def.PackageName, def.TypeName)
+}
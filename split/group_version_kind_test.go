@@ -0,0 +1,140 @@
+package split
+
+import (
+	"strings"
+	"testing"
+
+	openapi_spec "github.com/go-openapi/spec"
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
+)
+
+func gvkExtension(gvks ...map[string]interface{}) openapi_spec.Extensions {
+	entries := []interface{}{}
+	for _, gvk := range gvks {
+		entries = append(entries, gvk)
+	}
+	return openapi_spec.Extensions{"x-kubernetes-group-version-kind": entries}
+}
+
+func TestRenderGroupVersionKinds(t *testing.T) {
+	gitRepo := "github.com/kubewarden/k8s-objects"
+	typeMeta := map[string]openapi_spec.Schema{
+		"apiVersion": stringProperty(""),
+		"kind":       stringProperty(""),
+	}
+
+	swagger := openapi_spec.Swagger{}
+	swagger.Definitions = openapi_spec.Definitions{
+		"io.k8s.api.apps.v1.Deployment": {
+			SchemaProps: openapi_spec.SchemaProps{Properties: typeMeta},
+			VendorExtensible: openapi_spec.VendorExtensible{
+				Extensions: gvkExtension(map[string]interface{}{
+					"group": "apps", "version": "v1", "kind": "Deployment",
+				}),
+			},
+		},
+		"io.k8s.api.apps.v1.DeleteOptions": {
+			SchemaProps: openapi_spec.SchemaProps{Properties: typeMeta},
+			VendorExtensible: openapi_spec.VendorExtensible{
+				Extensions: gvkExtension(
+					map[string]interface{}{"group": "", "version": "v1", "kind": "DeleteOptions"},
+					map[string]interface{}{"group": "apps", "version": "v1", "kind": "DeleteOptions"},
+				),
+			},
+		},
+		"io.k8s.api.apps.v1.DeploymentSpec": {
+			SchemaProps: openapi_spec.SchemaProps{Properties: typeMeta},
+		},
+	}
+
+	plan, err := NewRefactoringPlan(&swagger, swagger_helpers.NamingOptions{})
+	if err != nil {
+		t.Fatalf("Cannot create refactoring plan: %v", err)
+	}
+
+	contents, err := renderGroupVersionKinds(plan.Packages["api/apps/v1"], gitRepo, "apimachinery/pkg/runtime/schema", &plan.Interfaces, nil)
+	if err != nil {
+		t.Fatalf("cannot render GroupVersionKind helpers: %v", err)
+	}
+
+	code := string(contents)
+	expectedSnippets := []string{
+		"package v1",
+		`schema "github.com/kubewarden/k8s-objects/apimachinery/pkg/runtime/schema"`,
+		`DeploymentGroup = "apps"`,
+		`DeploymentVersion = "v1"`,
+		`DeploymentKind = "Deployment"`,
+		`APIVersion: "apps/v1",`,
+		"func NewDeployment() *Deployment {",
+		"func (m *Deployment) GroupVersionKind() schema.GroupVersionKind {",
+		"func (m *Deployment) SetGroupVersionKind(gvk schema.GroupVersionKind) {",
+		"func (m *DeleteOptions) GroupVersionKind() schema.GroupVersionKind {",
+	}
+	for _, snippet := range expectedSnippets {
+		if !strings.Contains(code, snippet) {
+			t.Errorf("cannot find %s inside of generated code:\n%s", snippet, code)
+		}
+	}
+
+	unexpectedSnippets := []string{
+		"DeleteOptionsKind",
+		"NewDeleteOptions",
+		"DeploymentSpec",
+	}
+	for _, snippet := range unexpectedSnippets {
+		if strings.Contains(code, snippet) {
+			t.Errorf("unexpected %s inside of generated code:\n%s", snippet, code)
+		}
+	}
+}
+
+func TestRenderGroupVersionKindsCollisions(t *testing.T) {
+	gitRepo := "github.com/kubewarden/k8s-objects"
+	deployment := openapi_spec.Schema{
+		SchemaProps: openapi_spec.SchemaProps{Properties: map[string]openapi_spec.Schema{
+			"apiVersion": stringProperty(""),
+			"kind":       stringProperty(""),
+		}},
+		VendorExtensible: openapi_spec.VendorExtensible{
+			Extensions: gvkExtension(map[string]interface{}{
+				"group": "apps", "version": "v1", "kind": "Deployment",
+			}),
+		},
+	}
+
+	plan := newRegistryTestPlan(t, openapi_spec.Definitions{
+		"io.k8s.api.apps.v1.Deployment":     deployment,
+		"io.k8s.api.apps.v1.DeploymentKind": objectSchema(map[string]openapi_spec.Schema{"name": stringProperty("")}),
+	})
+	_, err := renderGroupVersionKinds(plan.Packages["api/apps/v1"], gitRepo, "apimachinery/pkg/runtime/schema", &plan.Interfaces, nil)
+	if err == nil || !strings.Contains(err.Error(), "cannot generate DeploymentKind for io.k8s.api.apps.v1.Deployment") {
+		t.Errorf("expected the clash with the type to be reported, got %v", err)
+	}
+
+	plan = newRegistryTestPlan(t, openapi_spec.Definitions{"io.k8s.api.apps.v1.Deployment": deployment})
+	declared := map[string]bool{"NewDeployment": true}
+	_, err = renderGroupVersionKinds(plan.Packages["api/apps/v1"], gitRepo, "apimachinery/pkg/runtime/schema", &plan.Interfaces, declared)
+	if err == nil || !strings.Contains(err.Error(), "cannot generate NewDeployment") {
+		t.Errorf("expected the clash with the declared function to be reported, got %v", err)
+	}
+}
+
+func TestSchemaPackageMapping(t *testing.T) {
+	swagger := openapi_spec.Swagger{}
+	plan, err := NewRefactoringPlan(&swagger, swagger_helpers.NamingOptions{
+		PackageMappingRules: []swagger_helpers.PackageMappingRule{
+			{Prefix: "io.k8s.apimachinery.pkg.", Package: "machinery/"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Cannot create refactoring plan: %v", err)
+	}
+
+	contents, err := renderRegistry(plan, "github.com/kubewarden/k8s-objects")
+	if err != nil {
+		t.Fatalf("cannot render registry: %v", err)
+	}
+	if !strings.Contains(string(contents), `schema "github.com/kubewarden/k8s-objects/machinery/runtime/schema"`) {
+		t.Errorf("the package mapping rules must apply to the schema package:\n%s", contents)
+	}
+}
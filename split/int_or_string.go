@@ -0,0 +1,257 @@
+package split
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/go-openapi/swag"
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
+	"github.com/pkg/errors"
+)
+
+const INT_OR_STRING_FILE_NAME = "int_or_string.go"
+
+const INT_OR_STRING_TEMPLATE = `// Code generated by k8s-objects-generator. DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/mailru/easyjson/jlexer"
+	"github.com/mailru/easyjson/jwriter"
+)
+
+// {{ .TypeName }}Type represents the stored type of {{ .TypeName }}
+type {{ .TypeName }}Type int64
+
+const (
+	// {{ .TypeName }}Int means the {{ .TypeName }} holds an int
+	{{ .TypeName }}Int {{ .TypeName }}Type = iota
+	// {{ .TypeName }}String means the {{ .TypeName }} holds a string
+	{{ .TypeName }}String
+)
+
+// {{ .TypeName }} is a type that can hold an int32 or a string. When used in
+// JSON marshalling and unmarshalling, it produces or consumes the inner type
+type {{ .TypeName }} struct {
+	Type   {{ .TypeName }}Type
+	IntVal int32
+	StrVal string
+}
+
+// {{ .TypeName }}FromInt creates an {{ .TypeName }} object with an int32 value
+func {{ .TypeName }}FromInt(val int) {{ .TypeName }} {
+	return {{ .TypeName }}{Type: {{ .TypeName }}Int, IntVal: int32(val)}
+}
+
+// {{ .TypeName }}FromString creates an {{ .TypeName }} object with a string
+// value
+func {{ .TypeName }}FromString(val string) {{ .TypeName }} {
+	return {{ .TypeName }}{Type: {{ .TypeName }}String, StrVal: val}
+}
+
+// Parse{{ .TypeName }} parses the given string and tries to convert it to an
+// integer before falling back to a string value
+func Parse{{ .TypeName }}(val string) {{ .TypeName }} {
+	i, err := strconv.Atoi(val)
+	if err != nil {
+		return {{ .TypeName }}FromString(val)
+	}
+	return {{ .TypeName }}FromInt(i)
+}
+
+// String returns the string value, or the string representation of the int
+// value
+func (intstr {{ .TypeName }}) String() string {
+	if intstr.Type == {{ .TypeName }}String {
+		return intstr.StrVal
+	}
+	return strconv.Itoa(intstr.IntValue())
+}
+
+// IntValue returns the int value, or the result of converting the string
+// value to an int. Zero is returned when the conversion fails
+func (intstr {{ .TypeName }}) IntValue() int {
+	if intstr.Type == {{ .TypeName }}String {
+		i, _ := strconv.Atoi(intstr.StrVal)
+		return i
+	}
+	return int(intstr.IntVal)
+}
+
+// ScaledValue returns the int value, or the percentage of total expressed by
+// the string value, e.g. "25%". The result is rounded up when roundUp is set
+func (intstr {{ .TypeName }}) ScaledValue(total int, roundUp bool) (int, error) {
+	return GetScaledValueFromIntOrPercent(&intstr, total, roundUp)
+}
+
+// GetScaledValueFromIntOrPercent returns the int value, or the percentage of
+// total expressed by the string value, e.g. "25%". The result is rounded up
+// when roundUp is set
+func GetScaledValueFromIntOrPercent(intOrPercent *{{ .TypeName }}, total int, roundUp bool) (int, error) {
+	if intOrPercent == nil {
+		return 0, errors.New("nil value for {{ .TypeName }}")
+	}
+
+	switch intOrPercent.Type {
+	case {{ .TypeName }}Int:
+		return int(intOrPercent.IntVal), nil
+	case {{ .TypeName }}String:
+		s := intOrPercent.StrVal
+		if !strings.HasSuffix(s, "%") {
+			return 0, fmt.Errorf("invalid value %q: string is not a percentage", s)
+		}
+		percent, err := strconv.Atoi(strings.TrimSuffix(s, "%"))
+		if err != nil {
+			return 0, fmt.Errorf("invalid value %q: %v", s, err)
+		}
+		value := float64(percent) * float64(total) / 100
+		if roundUp {
+			return int(math.Ceil(value)), nil
+		}
+		return int(math.Floor(value)), nil
+	}
+
+	return 0, fmt.Errorf("invalid type: neither int nor percentage")
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (intstr {{ .TypeName }}) MarshalEasyJSON(w *jwriter.Writer) {
+	if intstr.Type == {{ .TypeName }}String {
+		w.String(intstr.StrVal)
+		return
+	}
+	w.Int32(intstr.IntVal)
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (intstr {{ .TypeName }}) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	intstr.MarshalEasyJSON(&w)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (intstr *{{ .TypeName }}) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	if l.IsNull() {
+		l.Skip()
+		*intstr = {{ .TypeName }}{}
+		return
+	}
+
+	raw := l.Raw()
+	if !l.Ok() {
+		return
+	}
+	value := jlexer.Lexer{Data: raw}
+	if len(raw) > 0 && raw[0] == '"' {
+		*intstr = {{ .TypeName }}FromString(value.String())
+	} else {
+		*intstr = {{ .TypeName }}{Type: {{ .TypeName }}Int, IntVal: value.Int32()}
+	}
+	if err := value.Error(); err != nil {
+		l.AddError(err)
+	}
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (intstr *{{ .TypeName }}) UnmarshalJSON(data []byte) error {
+	l := jlexer.Lexer{Data: data}
+	intstr.UnmarshalEasyJSON(&l)
+	return l.Error()
+}
+`
+
+// Writes the IntOrString type, which is not generated by swagger, inside of
+// the package of the int-or-string definition
+func GenerateIntOrString(project Project, plan *RefactoringPlan) error {
+	for _, def := range plan.Definitions {
+		if !def.IsIntOrString() {
+			continue
+		}
+		if override, _ := plan.Interfaces.Override(project.GitRepo, def.PackageName, def.TypeName); override.Import != "" {
+			// the type is provided by another package
+			continue
+		}
+
+		pkgDir := filepath.Join(project.Root, def.PackageName)
+		declared, err := declaredIdentifiers(pkgDir, INT_OR_STRING_FILE_NAME)
+		if err != nil {
+			return errors.Wrapf(err, "cannot parse package %s", def.PackageName)
+		}
+		contents, err := renderIntOrString(def, plan.Packages[def.PackageName], declared)
+		if err != nil {
+			return errors.Wrapf(err, "cannot render %s", def.ID)
+		}
+
+		if err := os.MkdirAll(pkgDir, 0777); err != nil {
+			return errors.Wrapf(err, "cannot create dir %s", pkgDir)
+		}
+		fileName := filepath.Join(pkgDir, INT_OR_STRING_FILE_NAME)
+		if err := os.WriteFile(fileName, contents, 0644); err != nil {
+			return errors.Wrapf(err, "cannot write %s", fileName)
+		}
+	}
+
+	return nil
+}
+
+// Returns the contents of the file defining the IntOrString type. The
+// generation fails when the identifiers declared next to the type clash with
+// the types of the package or with the identifiers already declared by its
+// files
+func renderIntOrString(def *swagger_helpers.Definition, pkg swagger_helpers.Package, declared map[string]bool) ([]byte, error) {
+	typeName := swag.ToGoName(def.TypeName)
+	typeNames := make(map[string]bool)
+	for _, other := range pkg.Definitions {
+		typeNames[swag.ToGoName(other.TypeName)] = true
+	}
+	identifiers := []string{
+		typeName + "Type",
+		typeName + "Int",
+		typeName + "String",
+		typeName + "FromInt",
+		typeName + "FromString",
+		"Parse" + typeName,
+		"GetScaledValueFromIntOrPercent",
+	}
+	for _, name := range identifiers {
+		if typeNames[name] || declared[name] {
+			return nil, fmt.Errorf("cannot generate %s for %s: the package already declares it", name, def.ID)
+		}
+	}
+
+	intOrStringTemplate, err := template.New("int_or_string").Parse(INT_OR_STRING_TEMPLATE)
+	if err != nil {
+		return nil, err
+	}
+
+	templateData := struct {
+		Package  string
+		TypeName string
+	}{
+		Package:  filepath.Base(def.PackageName),
+		TypeName: typeName,
+	}
+
+	var buf bytes.Buffer
+	if err := intOrStringTemplate.Execute(&buf, templateData); err != nil {
+		return nil, err
+	}
+
+	contents, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("cannot format generated code: %v", err)
+	}
+
+	return contents, nil
+}
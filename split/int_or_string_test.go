@@ -0,0 +1,52 @@
+package split
+
+import (
+	"strings"
+	"testing"
+
+	openapi_spec "github.com/go-openapi/spec"
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
+)
+
+// The behaviour of the generated type is tested by the IntOrString tests kept
+// inside of testdata, which run against the generated code
+func TestRenderIntOrString(t *testing.T) {
+	// older specs describe IntOrString as an empty object, which must not be
+	// turned into a raw message
+	plan := newRegistryTestPlan(t, openapi_spec.Definitions{
+		"io.k8s.apimachinery.pkg.util.intstr.IntOrString": {},
+	})
+	if plan.Interfaces.IsInterface("", "apimachinery/pkg/util/intstr", "IntOrString") {
+		t.Fatal("IntOrString must not be registered as an interface")
+	}
+
+	contents, err := renderIntOrString(plan.Definitions["io.k8s.apimachinery.pkg.util.intstr.IntOrString"], plan.Packages["apimachinery/pkg/util/intstr"], nil)
+	if err != nil {
+		t.Fatalf("cannot render IntOrString: %v", err)
+	}
+
+	if code := string(contents); !strings.Contains(code, "type IntOrString struct {") {
+		t.Errorf("cannot find IntOrString inside of generated code:\n%s", code)
+	}
+}
+
+func TestRenderIntOrStringCollisions(t *testing.T) {
+	plan := newRegistryTestPlan(t, openapi_spec.Definitions{
+		"io.k8s.apimachinery.pkg.util.intstr.IntOrString":     stringProperty(swagger_helpers.INT_OR_STRING_FORMAT),
+		"io.k8s.apimachinery.pkg.util.intstr.IntOrStringType": objectSchema(map[string]openapi_spec.Schema{"name": stringProperty("")}),
+	})
+	def := plan.Definitions["io.k8s.apimachinery.pkg.util.intstr.IntOrString"]
+	_, err := renderIntOrString(def, plan.Packages["apimachinery/pkg/util/intstr"], nil)
+	if err == nil || !strings.Contains(err.Error(), "cannot generate IntOrStringType for io.k8s.apimachinery.pkg.util.intstr.IntOrString") {
+		t.Errorf("expected the clash with the type to be reported, got %v", err)
+	}
+
+	plan = newRegistryTestPlan(t, openapi_spec.Definitions{
+		"io.k8s.apimachinery.pkg.util.intstr.IntOrString": stringProperty(swagger_helpers.INT_OR_STRING_FORMAT),
+	})
+	def = plan.Definitions["io.k8s.apimachinery.pkg.util.intstr.IntOrString"]
+	_, err = renderIntOrString(def, plan.Packages["apimachinery/pkg/util/intstr"], map[string]bool{"ParseIntOrString": true})
+	if err == nil || !strings.Contains(err.Error(), "cannot generate ParseIntOrString") {
+		t.Errorf("expected the clash with the declared function to be reported, got %v", err)
+	}
+}
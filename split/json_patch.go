@@ -0,0 +1,554 @@
+package split
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/go-openapi/swag"
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
+	"github.com/pkg/errors"
+)
+
+// Package, relative to the project root, that provides the PatchOperation
+// type and the helpers used by the generated Diff functions
+const JSON_PATCH_PACKAGE = "jsonpatch"
+
+const JSON_PATCH_FILE_NAME = "json_patch.go"
+
+// Contents of the jsonpatch package. This is a real package of the
+// generator, which is compiled and tested like any other one, copied as is
+// into the project
+//
+//go:embed jsonpatch/json_patch.go
+var jsonPatchPackageContents []byte
+
+const JSON_PATCH_TEMPLATE = `// Code generated by k8s-objects-generator. DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+{{- range $path, $alias := .Imports }}
+	{{ $alias }} "{{ $path }}"
+{{- end }}
+)
+{{ range .Types }}
+// Diff{{ .TypeName }} returns the JSON Patch operations that turn from into to
+func Diff{{ .TypeName }}(from, to *{{ .TypeName }}) []jsonpatch.PatchOperation {
+	if from == nil || to == nil {
+		if from == to {
+			return nil
+		}
+		if to == nil {
+			return []jsonpatch.PatchOperation{jsonpatch.Replace("", nil)}
+		}
+		return []jsonpatch.PatchOperation{jsonpatch.Replace("", to)}
+	}
+	return from.AppendDiff(nil, "", to)
+}
+
+// AppendDiff appends to ops the JSON Patch operations that turn m into other,
+// the operations are relative to path
+func (m *{{ .TypeName }}) AppendDiff(ops []jsonpatch.PatchOperation, path string, other *{{ .TypeName }}) []jsonpatch.PatchOperation {
+{{ .Body }}
+	return ops
+}
+{{ end }}
+`
+
+type jsonPatchType struct {
+	TypeName string
+	Body     string
+}
+
+// Writes the jsonpatch package and, for each package, the Diff functions of
+// its struct types
+func GenerateJSONPatchDiffs(project Project, plan *RefactoringPlan) error {
+	jsonPatchDir := filepath.Join(project.Root, JSON_PATCH_PACKAGE)
+	if err := os.MkdirAll(jsonPatchDir, 0777); err != nil {
+		return errors.Wrapf(err, "cannot create dir %s", jsonPatchDir)
+	}
+	jsonPatchFileName := filepath.Join(jsonPatchDir, JSON_PATCH_FILE_NAME)
+	contents := append([]byte("// Code generated by k8s-objects-generator. DO NOT EDIT.\n\n"), jsonPatchPackageContents...)
+	if err := os.WriteFile(jsonPatchFileName, contents, 0644); err != nil {
+		return errors.Wrapf(err, "cannot write %s", jsonPatchFileName)
+	}
+
+	resolver := swagger_helpers.NewGoTypeResolver(plan.Definitions, &plan.Interfaces, project.GitRepo)
+	for pkgName, pkg := range plan.Packages {
+		contents, err := renderJSONPatchDiffs(pkg, &resolver, plan.Definitions, project.GitRepo)
+		if err != nil {
+			return errors.Wrapf(err, "cannot render JSON patch diffs of package %s", pkgName)
+		}
+		if contents == nil {
+			continue
+		}
+
+		fileName := filepath.Join(project.Root, pkgName, JSON_PATCH_FILE_NAME)
+		if err := os.WriteFile(fileName, contents, 0644); err != nil {
+			return errors.Wrapf(err, "cannot write %s", fileName)
+		}
+	}
+
+	return nil
+}
+
+// Returns the contents of the file holding the Diff functions of the struct
+// types of the package, nil when the package has none
+func renderJSONPatchDiffs(
+	pkg swagger_helpers.Package,
+	resolver *swagger_helpers.GoTypeResolver,
+	definitions map[string]*swagger_helpers.Definition,
+	gitRepo string,
+) ([]byte, error) {
+	generator := diffGenerator{
+		resolver:    resolver,
+		definitions: definitions,
+		packageName: pkg.Name,
+		imports: map[string]string{
+			fmt.Sprintf("%s/%s", gitRepo, JSON_PATCH_PACKAGE): "jsonpatch",
+		},
+	}
+
+	types := []jsonPatchType{}
+	for _, def := range pkg.Definitions {
+		if override, found := resolver.Override(def); found && override.Import != "" {
+			// the type is declared by another package
+			continue
+		}
+
+		defType, err := resolver.DefinitionType(def, pkg.Name)
+		if err != nil {
+			return nil, err
+		}
+		if defType.Kind != swagger_helpers.StructGoType {
+			continue
+		}
+
+		body, err := generator.structBody(def)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot generate diff of %s", def.ID)
+		}
+		types = append(types, jsonPatchType{
+			TypeName: swag.ToGoName(def.TypeName),
+			Body:     body,
+		})
+	}
+
+	if len(types) == 0 {
+		return nil, nil
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].TypeName < types[j].TypeName
+	})
+
+	jsonPatchTemplate, err := template.New("json_patch").Parse(JSON_PATCH_TEMPLATE)
+	if err != nil {
+		return nil, err
+	}
+
+	templateData := struct {
+		Package string
+		Imports map[string]string
+		Types   []jsonPatchType
+	}{
+		Package: filepath.Base(pkg.Name),
+		Imports: generator.imports,
+		Types:   types,
+	}
+
+	var buf bytes.Buffer
+	if err := jsonPatchTemplate.Execute(&buf, templateData); err != nil {
+		return nil, err
+	}
+
+	contents, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("cannot format generated code: %v", err)
+	}
+
+	return contents, nil
+}
+
+// Generates the bodies of the AppendDiff methods. Fields are compared
+// according to their Go type: nil pointers, empty slices and maps and zero
+// values are considered absent, struct pointers are compared field by field
+// and list maps are compared element by element, matching them by key. The
+// values of the operations are encoded by typed code, no reflection involved.
+type diffGenerator struct {
+	resolver    *swagger_helpers.GoTypeResolver
+	definitions map[string]*swagger_helpers.Definition
+	packageName string
+	// packages used by the generated code, indexed by import path
+	imports map[string]string
+}
+
+func (g *diffGenerator) structBody(def *swagger_helpers.Definition) (string, error) {
+	names := []string{}
+	for name := range def.SwaggerDefinition.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	patchMetadata := def.PatchMetadata()
+
+	var b strings.Builder
+	for _, name := range names {
+		goType, err := g.resolver.PropertyType(def, name)
+		if err != nil {
+			return "", err
+		}
+		field := swag.ToGoName(name)
+		path := fmt.Sprintf("path + %q", "/"+strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1"))
+		listKeys := g.listMapKeys(&goType, patchMetadata[name])
+		if err := g.fieldDiff(&b, goType, "m."+field, "other."+field, path, listKeys, 0); err != nil {
+			return "", err
+		}
+	}
+
+	return b.String(), nil
+}
+
+// Returns the keys identifying the elements of a list map, nil when the
+// elements of the list must be compared by index
+func (g *diffGenerator) listMapKeys(goType *swagger_helpers.GoType, meta swagger_helpers.PatchMetadata) []string {
+	if goType.Kind != swagger_helpers.SliceGoType || goType.Elem.Kind != swagger_helpers.StructGoType || !goType.Elem.Pointer {
+		return nil
+	}
+
+	keys := []string{}
+	switch {
+	case meta.ListType == "map" && len(meta.ListMapKeys) > 0:
+		keys = meta.ListMapKeys
+	case meta.ListType == "" && meta.PatchMergeKey != "":
+		keys = []string{meta.PatchMergeKey}
+	default:
+		return nil
+	}
+
+	elemDef, found := g.definitions[goType.Elem.DefinitionID]
+	if !found {
+		return nil
+	}
+	for _, key := range keys {
+		if _, found := elemDef.SwaggerDefinition.Properties[key]; !found {
+			return nil
+		}
+	}
+	return keys
+}
+
+// Writes the statements comparing a field, zero values are considered absent
+func (g *diffGenerator) fieldDiff(b *strings.Builder, t swagger_helpers.GoType, old, new, path string, listKeys []string, depth int) error {
+	switch {
+	case t.Pointer:
+		fmt.Fprintf(b, "switch {\ncase %s == nil && %s != nil:\n", old, new)
+		fmt.Fprintf(b, "ops = append(ops, jsonpatch.Add(%s, %s))\n", path, g.valueExpr(t, new, false))
+		fmt.Fprintf(b, "case %s != nil && %s == nil:\nops = append(ops, jsonpatch.Remove(%s))\n", old, new, path)
+		fmt.Fprintf(b, "case %s != nil:\n", old)
+		var err error
+		if t.Kind == swagger_helpers.StructGoType {
+			fmt.Fprintf(b, "ops = %s.AppendDiff(ops, %s, %s)\n", old, path, new)
+		} else {
+			err = g.elemDiff(b, t.Deref(), "*"+old, "*"+new, path, depth)
+		}
+		if err != nil {
+			return err
+		}
+		b.WriteString("}\n")
+	case t.Kind == swagger_helpers.SliceGoType || t.Kind == swagger_helpers.MapGoType:
+		fmt.Fprintf(b, "switch {\ncase len(%s) == 0 && len(%s) > 0:\n", old, new)
+		fmt.Fprintf(b, "ops = append(ops, jsonpatch.Add(%s, %s))\n", path, g.valueExpr(t, new, false))
+		fmt.Fprintf(b, "case len(%s) > 0 && len(%s) == 0:\nops = append(ops, jsonpatch.Remove(%s))\n", old, new, path)
+		fmt.Fprintf(b, "case len(%s) > 0:\n", old)
+		var err error
+		if t.Kind == swagger_helpers.SliceGoType {
+			err = g.sliceDiff(b, t, old, new, path, listKeys, depth)
+		} else {
+			err = g.mapDiff(b, t, old, new, path, depth)
+		}
+		if err != nil {
+			return err
+		}
+		b.WriteString("}\n")
+	case t.HasEqual || t.Kind == swagger_helpers.OpaqueGoType:
+		// the zero value is compared through the method of the override,
+		// or through the JSON representation of the opaque ones, too
+		g.use(t)
+		equal, err := g.equalExpr(t, old, new, depth)
+		if err != nil {
+			return err
+		}
+		oldIsZero, err := g.equalExpr(t, old, "zero", depth)
+		if err != nil {
+			return err
+		}
+		newIsZero, err := g.equalExpr(t, new, "zero", depth)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(b, "if !(%s) {\nvar zero %s\nswitch {\n", equal, t.Expr())
+		fmt.Fprintf(b, "case %s:\nops = append(ops, jsonpatch.Add(%s, %s))\n", oldIsZero, path, g.valueExpr(t, new, false))
+		fmt.Fprintf(b, "case %s:\nops = append(ops, jsonpatch.Remove(%s))\n", newIsZero, path)
+		fmt.Fprintf(b, "default:\nops = append(ops, jsonpatch.Replace(%s, %s))\n}\n}\n", path, g.valueExpr(t, new, false))
+	case t.Kind == swagger_helpers.ScalarGoType:
+		g.use(t)
+		zero := t.Zero
+		if strings.HasSuffix(zero, "}") {
+			// composite literals must be parenthesized inside of conditions
+			zero = "(" + zero + ")"
+		}
+		fmt.Fprintf(b, "if %s != %s {\nswitch {\n", old, new)
+		fmt.Fprintf(b, "case %s == %s:\nops = append(ops, jsonpatch.Add(%s, %s))\n", old, zero, path, g.valueExpr(t, new, false))
+		fmt.Fprintf(b, "case %s == %s:\nops = append(ops, jsonpatch.Remove(%s))\n", new, zero, path)
+		fmt.Fprintf(b, "default:\nops = append(ops, jsonpatch.Replace(%s, %s))\n}\n}\n", path, g.valueExpr(t, new, false))
+	case t.Kind == swagger_helpers.BytesGoType:
+		fmt.Fprintf(b, "if string(%s) != string(%s) {\nswitch {\n", old, new)
+		fmt.Fprintf(b, "case len(%s) == 0:\nops = append(ops, jsonpatch.Add(%s, %s))\n", old, path, g.valueExpr(t, new, false))
+		fmt.Fprintf(b, "case len(%s) == 0:\nops = append(ops, jsonpatch.Remove(%s))\n", new, path)
+		fmt.Fprintf(b, "default:\nops = append(ops, jsonpatch.Replace(%s, %s))\n}\n}\n", path, g.valueExpr(t, new, false))
+	case t.Kind == swagger_helpers.StructGoType:
+		fmt.Fprintf(b, "ops = (&%s).AppendDiff(ops, %s, &%s)\n", old, path, new)
+	}
+	return nil
+}
+
+// Writes the statements comparing two elements of a slice or of a map, or
+// two non-nil pointers. Elements that differ are replaced, with the exception
+// of structs which are compared field by field
+func (g *diffGenerator) elemDiff(b *strings.Builder, t swagger_helpers.GoType, old, new, path string, depth int) error {
+	if t.Kind == swagger_helpers.StructGoType {
+		if t.Pointer {
+			fmt.Fprintf(b, "if %s == nil || %s == nil {\n", old, new)
+			fmt.Fprintf(b, "if %s != %s {\nops = append(ops, jsonpatch.Replace(%s, %s))\n}\n", old, new, path, g.valueExpr(t, new, true))
+			fmt.Fprintf(b, "} else {\nops = %s.AppendDiff(ops, %s, %s)\n}\n", old, path, new)
+		} else {
+			fmt.Fprintf(b, "ops = (&%s).AppendDiff(ops, %s, &%s)\n", old, path, new)
+		}
+		return nil
+	}
+
+	var differ string
+	switch {
+	case !t.Pointer && t.Kind == swagger_helpers.ScalarGoType && !t.HasEqual:
+		differ = fmt.Sprintf("%s != %s", old, new)
+	case !t.Pointer && t.Kind == swagger_helpers.BytesGoType:
+		differ = fmt.Sprintf("string(%s) != string(%s)", old, new)
+	default:
+		equal, err := g.equalExpr(t, old, new, depth)
+		if err != nil {
+			return err
+		}
+		differ = "!(" + equal + ")"
+	}
+	fmt.Fprintf(b, "if %s {\nops = append(ops, jsonpatch.Replace(%s, %s))\n}\n", differ, path, g.valueExpr(t, new, true))
+	return nil
+}
+
+func (g *diffGenerator) sliceDiff(b *strings.Builder, t swagger_helpers.GoType, old, new, path string, listKeys []string, depth int) error {
+	i := fmt.Sprintf("i%d", depth)
+
+	if len(listKeys) > 0 {
+		elemDef := g.definitions[t.Elem.DefinitionID]
+		g.use(*t.Elem)
+
+		parts := []string{}
+		for _, key := range listKeys {
+			keyType, err := g.resolver.PropertyType(elemDef, key)
+			if err != nil {
+				return err
+			}
+			field := "e." + swag.ToGoName(key)
+			if keyType.Pointer {
+				field = fmt.Sprintf("func() interface{} {\nif %s == nil {\nreturn nil\n}\nreturn *%s\n}()", field, field)
+			}
+			parts = append(parts, field)
+		}
+
+		keyFn := fmt.Sprintf("key%d", depth)
+		fmt.Fprintf(b, "%s := func(e %s) string {\nif e == nil {\nreturn \"\"\n}\n", keyFn, t.Elem.Expr())
+		fmt.Fprintf(b, "return jsonpatch.Key(%s)\n}\n", strings.Join(parts, ", "))
+		for _, list := range []struct{ name, expr string }{{"oldKeys", old}, {"newKeys", new}} {
+			keys := fmt.Sprintf("%s%d", list.name, depth)
+			fmt.Fprintf(b, "%s := make([]string, len(%s))\n", keys, list.expr)
+			fmt.Fprintf(b, "for %s, e := range %s {\n%s[%s] = %s(e)\n}\n", i, list.expr, keys, i, keyFn)
+		}
+
+		o, n, p := fmt.Sprintf("o%d", depth), fmt.Sprintf("n%d", depth), fmt.Sprintf("p%d", depth)
+		fmt.Fprintf(b, "ops = jsonpatch.DiffKeyedList(ops, %s, oldKeys%d, newKeys%d, %s,\n", path, depth, depth, g.valueExpr(t, new, false))
+		fmt.Fprintf(b, "func(%s int) interface{} {\nif %s[%s] == nil {\nreturn nil\n}\nreturn %s[%s]\n},\n", i, new, i, new, i)
+		fmt.Fprintf(b, "func(ops []jsonpatch.PatchOperation, %s string, %s, %s int) []jsonpatch.PatchOperation {\n", p, o, n)
+		if err := g.elemDiff(b, *t.Elem, old+"["+o+"]", new+"["+n+"]", p, depth+1); err != nil {
+			return err
+		}
+		b.WriteString("return ops\n})\n")
+		return nil
+	}
+
+	elemPath := fmt.Sprintf("jsonpatch.IndexPath(%s, %s)", path, i)
+	fmt.Fprintf(b, "for %s := 0; %s < len(%s) && %s < len(%s); %s++ {\n", i, i, old, i, new, i)
+	if err := g.elemDiff(b, *t.Elem, old+"["+i+"]", new+"["+i+"]", elemPath, depth+1); err != nil {
+		return err
+	}
+	b.WriteString("}\n")
+	fmt.Fprintf(b, "for %s := len(%s) - 1; %s >= len(%s); %s-- {\n", i, old, i, new, i)
+	fmt.Fprintf(b, "ops = append(ops, jsonpatch.Remove(%s))\n}\n", elemPath)
+	fmt.Fprintf(b, "for %s := len(%s); %s < len(%s); %s++ {\n", i, old, i, new, i)
+	fmt.Fprintf(b, "ops = append(ops, jsonpatch.Add(%s, %s))\n}\n", elemPath, g.valueExpr(*t.Elem, new+"["+i+"]", true))
+	return nil
+}
+
+func (g *diffGenerator) mapDiff(b *strings.Builder, t swagger_helpers.GoType, old, new, path string, depth int) error {
+	k, keys := fmt.Sprintf("k%d", depth), fmt.Sprintf("keys%d", depth)
+	oldValue, newValue := fmt.Sprintf("oldValue%d", depth), fmt.Sprintf("newValue%d", depth)
+	inOld, inNew := fmt.Sprintf("inOld%d", depth), fmt.Sprintf("inNew%d", depth)
+	elemPath := fmt.Sprintf("jsonpatch.KeyPath(%s, %s)", path, k)
+
+	fmt.Fprintf(b, "%s := make([]string, 0, len(%s)+len(%s))\n", keys, old, new)
+	for _, m := range []string{old, new} {
+		fmt.Fprintf(b, "for %s := range %s {\n%s = append(%s, %s)\n}\n", k, m, keys, keys, k)
+	}
+	fmt.Fprintf(b, "for _, %s := range jsonpatch.SortedUniqueKeys(%s) {\n", k, keys)
+	fmt.Fprintf(b, "%s, %s := %s[%s]\n%s, %s := %s[%s]\n", oldValue, inOld, old, k, newValue, inNew, new, k)
+	fmt.Fprintf(b, "switch {\ncase !%s:\nops = append(ops, jsonpatch.Remove(%s))\n", inNew, elemPath)
+	fmt.Fprintf(b, "case !%s:\nops = append(ops, jsonpatch.Add(%s, %s))\n", inOld, elemPath, g.valueExpr(*t.Elem, newValue, true))
+	b.WriteString("default:\n")
+	if err := g.elemDiff(b, *t.Elem, oldValue, newValue, elemPath, depth+1); err != nil {
+		return err
+	}
+	b.WriteString("}\n}\n")
+	return nil
+}
+
+// Returns a boolean expression that is true when the two values are equal
+func (g *diffGenerator) equalExpr(t swagger_helpers.GoType, a, b string, depth int) (string, error) {
+	if t.Pointer {
+		if t.Kind == swagger_helpers.StructGoType {
+			return fmt.Sprintf("(%s == nil && %s == nil) || (%s != nil && %s != nil && len(%s.AppendDiff(nil, \"\", %s)) == 0)",
+				a, b, a, b, a, b), nil
+		}
+		deref, err := g.equalExpr(t.Deref(), "(*"+a+")", "(*"+b+")", depth)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("(%s == nil && %s == nil) || (%s != nil && %s != nil && %s)", a, b, a, b, deref), nil
+	}
+
+	if t.HasEqual {
+		return fmt.Sprintf("(&%s).Equal(&%s)", a, b), nil
+	}
+
+	switch t.Kind {
+	case swagger_helpers.ScalarGoType:
+		return fmt.Sprintf("%s == %s", a, b), nil
+	case swagger_helpers.BytesGoType:
+		return fmt.Sprintf("string(%s) == string(%s)", a, b), nil
+	case swagger_helpers.StructGoType:
+		return fmt.Sprintf("len((&%s).AppendDiff(nil, \"\", &%s)) == 0", a, b), nil
+	case swagger_helpers.OpaqueGoType:
+		g.imports["bytes"] = "bytes"
+		return fmt.Sprintf("func() bool {\nxj, _ := %s\nyj, _ := %s\nreturn bytes.Equal(xj, yj)\n}()",
+			opaqueMarshalCall(t, a, g.imports), opaqueMarshalCall(t, b, g.imports)), nil
+	case swagger_helpers.SliceGoType:
+		i := fmt.Sprintf("i%d", depth)
+		elem, err := g.equalExpr(*t.Elem, a+"["+i+"]", b+"["+i+"]", depth+1)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("func() bool {\nif len(%s) != len(%s) {\nreturn false\n}\nfor %s := range %s {\nif !(%s) {\nreturn false\n}\n}\nreturn true\n}()",
+			a, b, i, a, elem), nil
+	case swagger_helpers.MapGoType:
+		k, v, w := fmt.Sprintf("k%d", depth), fmt.Sprintf("v%d", depth), fmt.Sprintf("w%d", depth)
+		elem, err := g.equalExpr(*t.Elem, v, w, depth+1)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("func() bool {\nif len(%s) != len(%s) {\nreturn false\n}\nfor %s, %s := range %s {\nif %s, found := %s[%s]; !found || !(%s) {\nreturn false\n}\n}\nreturn true\n}()",
+			a, b, k, v, a, w, b, k, elem), nil
+	}
+
+	return "", fmt.Errorf("values of type %s cannot be compared", t.Expr())
+}
+
+// Returns the expression used as value of the operations. Named basic types,
+// like enums, are converted to their underlying type and the generated
+// structs are easyjson.Marshaler. The other values, like slices and maps,
+// are encoded right away. When mayBeNil is set, nil pointers are encoded as
+// `null`
+func (g *diffGenerator) valueExpr(t swagger_helpers.GoType, x string, mayBeNil bool) string {
+	switch {
+	case t.Kind == swagger_helpers.StructGoType && !(t.Pointer && mayBeNil):
+		return x
+	case t.Underlying != "" && !t.Pointer:
+		return fmt.Sprintf("%s(%s)", t.Underlying, x)
+	case t.Underlying != "" && !mayBeNil:
+		return fmt.Sprintf("%s(*%s)", t.Underlying, x)
+	}
+
+	if t.Pointer && !mayBeNil {
+		t, x = t.Deref(), "*"+x
+	}
+	g.imports["github.com/mailru/easyjson/jwriter"] = "jwriter"
+	var b strings.Builder
+	b.WriteString("jsonpatch.Encode(func(w *jwriter.Writer) {\n")
+	g.encode(&b, t, x, 0)
+	b.WriteString("})")
+	return b.String()
+}
+
+// Writes the statements encoding the value with the jwriter.Writer w, like
+// easyjson does
+func (g *diffGenerator) encode(b *strings.Builder, t swagger_helpers.GoType, x string, depth int) {
+	if strings.HasPrefix(x, "*") {
+		x = "(" + x + ")"
+	}
+	if t.Pointer {
+		fmt.Fprintf(b, "if %s == nil {\nw.RawString(\"null\")\n} else {\n", x)
+		if t.Kind == swagger_helpers.StructGoType {
+			fmt.Fprintf(b, "%s.MarshalEasyJSON(w)\n", x)
+		} else {
+			g.encode(b, t.Deref(), "*"+x, depth)
+		}
+		b.WriteString("}\n")
+		return
+	}
+
+	switch {
+	case t.Underlying != "" && t.Name == t.Underlying:
+		fmt.Fprintf(b, "w.%s(%s)\n", swag.ToGoName(t.Underlying), x)
+	case t.Underlying != "":
+		fmt.Fprintf(b, "w.%s(%s(%s))\n", swag.ToGoName(t.Underlying), t.Underlying, x)
+	case t.Marshaler == swagger_helpers.EASYJSON_MARSHALER:
+		fmt.Fprintf(b, "%s.MarshalEasyJSON(w)\n", x)
+	case t.Marshaler == swagger_helpers.JSON_MARSHALER:
+		fmt.Fprintf(b, "w.Raw(%s.MarshalJSON())\n", x)
+	case t.Marshaler == swagger_helpers.TEXT_MARSHALER:
+		fmt.Fprintf(b, "w.RawText(%s.MarshalText())\n", x)
+	case t.Kind == swagger_helpers.BytesGoType:
+		fmt.Fprintf(b, "w.Base64Bytes(%s)\n", x)
+	case t.Kind == swagger_helpers.SliceGoType:
+		i, e := fmt.Sprintf("ei%d", depth), fmt.Sprintf("ee%d", depth)
+		fmt.Fprintf(b, "if %s == nil {\nw.RawString(\"null\")\n} else {\nw.RawByte('[')\n", x)
+		fmt.Fprintf(b, "for %s, %s := range %s {\nif %s > 0 {\nw.RawByte(',')\n}\n", i, e, x, i)
+		g.encode(b, *t.Elem, e, depth+1)
+		b.WriteString("}\nw.RawByte(']')\n}\n")
+	case t.Kind == swagger_helpers.MapGoType:
+		first, k, v := fmt.Sprintf("ef%d", depth), fmt.Sprintf("ek%d", depth), fmt.Sprintf("ev%d", depth)
+		fmt.Fprintf(b, "if %s == nil {\nw.RawString(\"null\")\n} else {\nw.RawByte('{')\n%s := true\n", x, first)
+		fmt.Fprintf(b, "for %s, %s := range %s {\nif !%s {\nw.RawByte(',')\n}\n%s = false\n", k, v, x, first, first)
+		fmt.Fprintf(b, "w.String(%s)\nw.RawByte(':')\n", k)
+		g.encode(b, *t.Elem, v, depth+1)
+		b.WriteString("}\nw.RawByte('}')\n}\n")
+	}
+}
+
+// Records the packages required by the given type
+func (g *diffGenerator) use(t swagger_helpers.GoType) {
+	t.CollectImports(g.imports)
+}
@@ -0,0 +1,21 @@
+package split
+
+import (
+	"testing"
+
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
+)
+
+func TestRenderJSONPatchDiffsSkipsNonStructTypes(t *testing.T) {
+	gitRepo := "github.com/kubewarden/k8s-objects"
+	plan := newGeneratedCodeTestPlan(t)
+	resolver := swagger_helpers.NewGoTypeResolver(plan.Definitions, &plan.Interfaces, gitRepo)
+
+	contents, err := renderJSONPatchDiffs(plan.Packages["apimachinery/pkg/api/resource"], &resolver, plan.Definitions, gitRepo)
+	if err != nil {
+		t.Fatalf("cannot render JSON patch diffs: %v", err)
+	}
+	if contents != nil {
+		t.Errorf("nothing must be generated for packages without structs:\n%s", contents)
+	}
+}
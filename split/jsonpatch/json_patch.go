@@ -0,0 +1,227 @@
+// Package jsonpatch provides the RFC 6902 operations returned by the Diff
+// functions of the generated types. It doesn't rely on reflection, values
+// are encoded by easyjson
+package jsonpatch
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mailru/easyjson"
+	"github.com/mailru/easyjson/jwriter"
+)
+
+// PatchOperation is a RFC 6902 JSON Patch operation. The value is either nil,
+// a string, a boolean, a number or an easyjson.Marshaler
+type PatchOperation struct {
+	Op    string
+	Path  string
+	Value interface{}
+}
+
+// Add returns an "add" operation
+func Add(path string, value interface{}) PatchOperation {
+	return PatchOperation{Op: "add", Path: path, Value: value}
+}
+
+// Remove returns a "remove" operation
+func Remove(path string) PatchOperation {
+	return PatchOperation{Op: "remove", Path: path}
+}
+
+// Replace returns a "replace" operation
+func Replace(path string, value interface{}) PatchOperation {
+	return PatchOperation{Op: "replace", Path: path, Value: value}
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (o PatchOperation) MarshalEasyJSON(w *jwriter.Writer) {
+	w.RawString("{\"op\":")
+	w.String(o.Op)
+	w.RawString(",\"path\":")
+	w.String(o.Path)
+	if o.Op != "remove" {
+		w.RawString(",\"value\":")
+		writeValue(w, o.Value)
+	}
+	w.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (o PatchOperation) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	o.MarshalEasyJSON(&w)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// Marshal encodes the operations as a JSON Patch document
+func Marshal(ops []PatchOperation) ([]byte, error) {
+	w := jwriter.Writer{}
+	w.RawByte('[')
+	for i, op := range ops {
+		if i > 0 {
+			w.RawByte(',')
+		}
+		op.MarshalEasyJSON(&w)
+	}
+	w.RawByte(']')
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+func writeValue(w *jwriter.Writer, value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		w.RawString("null")
+	case easyjson.Marshaler:
+		v.MarshalEasyJSON(w)
+	case string:
+		w.String(v)
+	case bool:
+		w.Bool(v)
+	case int32:
+		w.Int32(v)
+	case int64:
+		w.Int64(v)
+	case float32:
+		w.Float32(v)
+	case float64:
+		w.Float64(v)
+	default:
+		if w.Error == nil {
+			w.Error = errUnsupportedValue
+		}
+	}
+}
+
+var errUnsupportedValue = errors.New("jsonpatch: values must be strings, booleans, numbers or easyjson.Marshaler")
+
+// Encode returns the JSON document written right away by encode. The
+// generated code uses it for the values that are not easyjson.Marshaler, like
+// slices and maps
+func Encode(encode func(w *jwriter.Writer)) easyjson.Marshaler {
+	w := jwriter.Writer{}
+	encode(&w)
+	data, err := w.BuildBytes()
+	return encodedValue{data: data, err: err}
+}
+
+type encodedValue struct {
+	data []byte
+	err  error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v encodedValue) MarshalEasyJSON(w *jwriter.Writer) {
+	w.Raw(v.data, v.err)
+}
+
+// EscapePathSegment escapes a reference token of a JSON pointer
+func EscapePathSegment(segment string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(segment, "~", "~0"), "/", "~1")
+}
+
+// IndexPath returns the JSON pointer of the i-th element of the array found
+// at path
+func IndexPath(path string, i int) string {
+	return path + "/" + strconv.Itoa(i)
+}
+
+// KeyPath returns the JSON pointer of the given key of the object found at
+// path
+func KeyPath(path, key string) string {
+	return path + "/" + EscapePathSegment(key)
+}
+
+// SortedUniqueKeys sorts the keys and removes the duplicated ones
+func SortedUniqueKeys(keys []string) []string {
+	sort.Strings(keys)
+	unique := keys[:0]
+	for i, key := range keys {
+		if i == 0 || key != keys[i-1] {
+			unique = append(unique, key)
+		}
+	}
+	return unique
+}
+
+// Key builds the identity of an element of a list map out of the values of
+// its keys
+func Key(parts ...interface{}) string {
+	var b strings.Builder
+	for i, part := range parts {
+		if i > 0 {
+			b.WriteByte(0)
+		}
+		if part != nil {
+			fmt.Fprint(&b, part)
+		}
+	}
+	return b.String()
+}
+
+// DiffKeyedList appends the operations that turn a list map into another one.
+// Elements are matched by key: the ones that are not part of the new list are
+// removed, the new ones are added and the matching ones are compared with
+// diff. The whole list is replaced when keys are duplicated or when the
+// matching elements have been reordered.
+func DiffKeyedList(
+	ops []PatchOperation,
+	path string,
+	oldKeys, newKeys []string,
+	newList interface{},
+	value func(i int) interface{},
+	diff func(ops []PatchOperation, path string, oldIndex, newIndex int) []PatchOperation,
+) []PatchOperation {
+	oldIndexes, ok := indexKeys(oldKeys)
+	if !ok {
+		return append(ops, Replace(path, newList))
+	}
+	newIndexes, ok := indexKeys(newKeys)
+	if !ok {
+		return append(ops, Replace(path, newList))
+	}
+
+	// matching elements must keep their relative order
+	last := -1
+	for _, key := range newKeys {
+		if i, found := oldIndexes[key]; found {
+			if i < last {
+				return append(ops, Replace(path, newList))
+			}
+			last = i
+		}
+	}
+
+	for i := len(oldKeys) - 1; i >= 0; i-- {
+		if _, found := newIndexes[oldKeys[i]]; !found {
+			ops = append(ops, Remove(IndexPath(path, i)))
+		}
+	}
+
+	// after the removals, the matching elements are found at the same
+	// position they have inside of the new list, once all the previous
+	// additions have been done
+	for i, key := range newKeys {
+		if oldIndex, found := oldIndexes[key]; found {
+			ops = diff(ops, IndexPath(path, i), oldIndex, i)
+		} else {
+			ops = append(ops, Add(IndexPath(path, i), value(i)))
+		}
+	}
+
+	return ops
+}
+
+func indexKeys(keys []string) (map[string]int, bool) {
+	indexes := make(map[string]int, len(keys))
+	for i, key := range keys {
+		if _, duplicated := indexes[key]; duplicated {
+			return nil, false
+		}
+		indexes[key] = i
+	}
+	return indexes, true
+}
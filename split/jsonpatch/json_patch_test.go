@@ -0,0 +1,295 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mailru/easyjson"
+	"github.com/mailru/easyjson/jwriter"
+)
+
+// Applies the operations to the JSON document and returns the result. Only
+// the operations returned by the generated code are supported
+func apply(t *testing.T, document string, ops []PatchOperation) interface{} {
+	t.Helper()
+
+	data, err := Marshal(ops)
+	if err != nil {
+		t.Fatalf("cannot marshal operations: %v", err)
+	}
+	decoded := []struct {
+		Op    string
+		Path  string
+		Value interface{}
+	}{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("cannot decode operations %s: %v", data, err)
+	}
+
+	node := decode(t, document)
+	for _, op := range decoded {
+		tokens := []string{}
+		if op.Path != "" {
+			for _, token := range strings.Split(op.Path, "/")[1:] {
+				tokens = append(tokens, strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~"))
+			}
+		}
+		node, err = applyOperation(node, tokens, op.Op, op.Value)
+		if err != nil {
+			t.Fatalf("cannot apply %s %s to %v: %v", op.Op, op.Path, node, err)
+		}
+	}
+
+	return node
+}
+
+func applyOperation(node interface{}, tokens []string, op string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		if op != "replace" {
+			return nil, fmt.Errorf("cannot %s the whole document", op)
+		}
+		return value, nil
+	}
+
+	switch n := node.(type) {
+	case map[string]interface{}:
+		key := tokens[0]
+		child, found := n[key]
+		if len(tokens) > 1 {
+			if !found {
+				return nil, fmt.Errorf("cannot find %s", key)
+			}
+			patched, err := applyOperation(child, tokens[1:], op, value)
+			n[key] = patched
+			return n, err
+		}
+		switch {
+		case op == "add":
+			n[key] = value
+		case !found:
+			return nil, fmt.Errorf("cannot find %s", key)
+		case op == "remove":
+			delete(n, key)
+		default:
+			n[key] = value
+		}
+		return n, nil
+	case []interface{}:
+		i, err := strconv.Atoi(tokens[0])
+		if err != nil || i < 0 || i > len(n) || (i == len(n) && (op != "add" || len(tokens) > 1)) {
+			return nil, fmt.Errorf("invalid index %s", tokens[0])
+		}
+		if len(tokens) > 1 {
+			patched, err := applyOperation(n[i], tokens[1:], op, value)
+			n[i] = patched
+			return n, err
+		}
+		switch op {
+		case "add":
+			n = append(n[:i], append([]interface{}{value}, n[i:]...)...)
+		case "remove":
+			n = append(n[:i], n[i+1:]...)
+		default:
+			n[i] = value
+		}
+		return n, nil
+	}
+
+	return nil, fmt.Errorf("cannot find %s inside of a scalar", tokens[0])
+}
+
+func decode(t *testing.T, document string) interface{} {
+	t.Helper()
+
+	var node interface{}
+	if err := json.Unmarshal([]byte(document), &node); err != nil {
+		t.Fatalf("cannot decode document %s: %v", document, err)
+	}
+	return node
+}
+
+func TestMarshal(t *testing.T) {
+	ops := []PatchOperation{
+		Add("/spec/replicas", int32(3)),
+		Remove(KeyPath("/metadata/labels", "app.kubernetes.io/name")),
+		Replace(IndexPath("/spec/args", 1), "--verbose"),
+		Replace("/spec/selector", nil),
+	}
+
+	data, err := Marshal(ops)
+	if err != nil {
+		t.Fatalf("cannot marshal operations: %v", err)
+	}
+	expected := `[{"op":"add","path":"/spec/replicas","value":3},` +
+		`{"op":"remove","path":"/metadata/labels/app.kubernetes.io~1name"},` +
+		`{"op":"replace","path":"/spec/args/1","value":"--verbose"},` +
+		`{"op":"replace","path":"/spec/selector","value":null}]`
+	if string(data) != expected {
+		t.Errorf("wrong JSON patch document:\n%s\nexpected:\n%s", data, expected)
+	}
+
+	data, err = json.Marshal(ops[0])
+	if err != nil || string(data) != `{"op":"add","path":"/spec/replicas","value":3}` {
+		t.Errorf("wrong JSON encoding of the operation: %s %v", data, err)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	labels := map[string]string{"app": "test"}
+	value := Encode(func(w *jwriter.Writer) {
+		w.RawByte('{')
+		for k, v := range labels {
+			w.String(k)
+			w.RawByte(':')
+			w.String(v)
+		}
+		w.RawByte('}')
+	})
+	// the value is encoded right away
+	labels["app"] = "changed"
+
+	data, err := Marshal([]PatchOperation{Add("/metadata/labels", value)})
+	if err != nil || string(data) != `[{"op":"add","path":"/metadata/labels","value":{"app":"test"}}]` {
+		t.Errorf("wrong JSON patch document: %s %v", data, err)
+	}
+
+	if _, err := Marshal([]PatchOperation{Add("/spec/args", []string{"--verbose"})}); err == nil {
+		t.Error("values that are not easyjson.Marshaler must be rejected")
+	}
+}
+
+func TestEscapePathSegment(t *testing.T) {
+	if escaped := EscapePathSegment("a/b~c"); escaped != "a~1b~0c" {
+		t.Errorf("wrong escaped segment: %s", escaped)
+	}
+}
+
+func TestSortedUniqueKeys(t *testing.T) {
+	keys := SortedUniqueKeys([]string{"b", "a", "b", "c", "a"})
+	if !reflect.DeepEqual(keys, []string{"a", "b", "c"}) {
+		t.Errorf("wrong keys: %v", keys)
+	}
+}
+
+func TestKey(t *testing.T) {
+	if Key("a", 80) == Key("a8", 0) {
+		t.Error("the parts of the key must be kept apart")
+	}
+	if Key("http", nil) != Key("http", "") {
+		t.Error("missing parts must be encoded as empty values")
+	}
+}
+
+type port struct {
+	Name string `json:"name"`
+	Port int64  `json:"port"`
+}
+
+func (p port) MarshalEasyJSON(w *jwriter.Writer) {
+	w.RawString(`{"name":`)
+	w.String(p.Name)
+	w.RawString(`,"port":`)
+	w.Int64(p.Port)
+	w.RawByte('}')
+}
+
+func encodePorts(ports []port) easyjson.Marshaler {
+	return Encode(func(w *jwriter.Writer) {
+		w.RawByte('[')
+		for i, p := range ports {
+			if i > 0 {
+				w.RawByte(',')
+			}
+			p.MarshalEasyJSON(w)
+		}
+		w.RawByte(']')
+	})
+}
+
+// Returns the operations turning the old list of ports into the new one,
+// ports are identified by name
+func diffPorts(oldPorts, newPorts []port) []PatchOperation {
+	keys := func(ports []port) []string {
+		keys := make([]string, len(ports))
+		for i, p := range ports {
+			keys[i] = Key(p.Name)
+		}
+		return keys
+	}
+
+	return DiffKeyedList(nil, "/ports", keys(oldPorts), keys(newPorts), encodePorts(newPorts),
+		func(i int) interface{} {
+			return newPorts[i]
+		},
+		func(ops []PatchOperation, path string, oldIndex, newIndex int) []PatchOperation {
+			if oldPorts[oldIndex].Port != newPorts[newIndex].Port {
+				ops = append(ops, Replace(path+"/port", newPorts[newIndex].Port))
+			}
+			return ops
+		})
+}
+
+func TestDiffKeyedList(t *testing.T) {
+	cases := []struct {
+		name             string
+		oldList, newList []port
+		replaced         bool
+	}{
+		{
+			name:    "unchanged",
+			oldList: []port{{"http", 80}, {"https", 443}},
+			newList: []port{{"http", 80}, {"https", 443}},
+		},
+		{
+			name:    "changed element",
+			oldList: []port{{"http", 80}, {"https", 443}},
+			newList: []port{{"http", 8080}, {"https", 443}},
+		},
+		{
+			name:    "added and removed elements",
+			oldList: []port{{"http", 80}, {"metrics", 9090}, {"https", 443}},
+			newList: []port{{"grpc", 50051}, {"http", 80}, {"https", 8443}, {"admin", 8081}},
+		},
+		{
+			name:     "reordered elements",
+			oldList:  []port{{"http", 80}, {"https", 443}},
+			newList:  []port{{"https", 443}, {"http", 80}},
+			replaced: true,
+		},
+		{
+			name:     "duplicated keys",
+			oldList:  []port{{"http", 80}},
+			newList:  []port{{"http", 80}, {"http", 8080}},
+			replaced: true,
+		},
+	}
+
+	for _, c := range cases {
+		oldDocument, err := json.Marshal(map[string]interface{}{"ports": c.oldList})
+		if err != nil {
+			t.Fatalf("cannot encode the old list: %v", err)
+		}
+		newDocument, err := json.Marshal(map[string]interface{}{"ports": c.newList})
+		if err != nil {
+			t.Fatalf("cannot encode the new list: %v", err)
+		}
+
+		ops := diffPorts(c.oldList, c.newList)
+		patched := apply(t, string(oldDocument), ops)
+		if expected := decode(t, string(newDocument)); !reflect.DeepEqual(patched, expected) {
+			t.Errorf("%s: the operations %v turn %s into %v instead of %s", c.name, ops, oldDocument, patched, newDocument)
+		}
+
+		replaced := len(ops) == 1 && ops[0].Op == "replace" && ops[0].Path == "/ports"
+		if replaced != c.replaced {
+			t.Errorf("%s: expected the whole list to be replaced to be %v, got %v", c.name, c.replaced, ops)
+		}
+		if reflect.DeepEqual(c.oldList, c.newList) && len(ops) != 0 {
+			t.Errorf("%s: no operation expected, got %v", c.name, ops)
+		}
+	}
+}
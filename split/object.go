@@ -0,0 +1,146 @@
+package split
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/template"
+
+	"github.com/go-openapi/swag"
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
+	"github.com/pkg/errors"
+)
+
+const OBJECT_FILE_NAME = "object.go"
+
+const OBJECT_TEMPLATE = `// Code generated by k8s-objects-generator. DO NOT EDIT.
+
+package {{ .Package }}
+{{ if .MetaImport }}
+import (
+	{{ .MetaAlias }} "{{ .MetaImport }}"
+)
+{{ end }}
+{{- if .DefinesInterface }}
+// Object is implemented by all the kinds that have an ObjectMeta
+type Object interface {
+	GetObjectMeta() *ObjectMeta
+	SetObjectMeta(meta *ObjectMeta)
+	GetAPIVersion() string
+	GetKind() string
+}
+{{ end }}
+{{- range .TypeNames }}
+// GetObjectMeta returns the metadata of the object
+func (m *{{ . }}) GetObjectMeta() *{{ $.MetaQualifier }}ObjectMeta {
+	if m == nil {
+		return nil
+	}
+	return m.Metadata
+}
+
+// SetObjectMeta sets the metadata of the object
+func (m *{{ . }}) SetObjectMeta(meta *{{ $.MetaQualifier }}ObjectMeta) {
+	m.Metadata = meta
+}
+
+// GetAPIVersion returns the apiVersion of the object
+func (m *{{ . }}) GetAPIVersion() string {
+	if m == nil {
+		return ""
+	}
+	return m.APIVersion
+}
+
+// GetKind returns the kind of the object
+func (m *{{ . }}) GetKind() string {
+	if m == nil {
+		return ""
+	}
+	return m.Kind
+}
+{{ end }}
+`
+
+// Writes the `Object` interface inside of the package that defines
+// `ObjectMeta` and, for each package, a file that makes its kinds implement
+// the interface
+func GenerateObjectInterface(project Project, plan *RefactoringPlan) error {
+	for pkgName, pkg := range plan.Packages {
+		contents, err := renderObjectInterface(pkg, project.GitRepo, &plan.Interfaces, plan.Naming)
+		if err != nil {
+			return errors.Wrapf(err, "cannot render Object interface of package %s", pkgName)
+		}
+		if contents == nil {
+			continue
+		}
+
+		fileName := filepath.Join(project.Root, pkgName, OBJECT_FILE_NAME)
+		if err := os.WriteFile(fileName, contents, 0644); err != nil {
+			return errors.Wrapf(err, "cannot write %s", fileName)
+		}
+	}
+
+	return nil
+}
+
+// Returns true when the kind implements the `Object` interface
+func isObject(def *swagger_helpers.Definition, gitRepo string, interfaces *swagger_helpers.InterfaceRegistry) bool {
+	return isKind(def, gitRepo, interfaces) && def.HasObjectMeta()
+}
+
+// Returns the contents of the `Object` file of the given package, nil when
+// there's nothing to generate
+func renderObjectInterface(pkg swagger_helpers.Package, gitRepo string, interfaces *swagger_helpers.InterfaceRegistry, naming swagger_helpers.Naming) ([]byte, error) {
+	typeNames := []string{}
+	for _, def := range pkg.Definitions {
+		if isObject(def, gitRepo, interfaces) {
+			typeNames = append(typeNames, swag.ToGoName(def.TypeName))
+		}
+	}
+	sort.Strings(typeNames)
+
+	metaPackage := naming.ObjectMetaPackage()
+	definesInterface := pkg.Name == metaPackage
+	if len(typeNames) == 0 && !definesInterface {
+		return nil, nil
+	}
+
+	templateData := struct {
+		Package          string
+		DefinesInterface bool
+		MetaImport       string
+		MetaAlias        string
+		MetaQualifier    string
+		TypeNames        []string
+	}{
+		Package:          filepath.Base(pkg.Name),
+		DefinesInterface: definesInterface,
+		TypeNames:        typeNames,
+	}
+	if !definesInterface {
+		templateData.MetaImport = fmt.Sprintf("%s/%s", gitRepo, metaPackage)
+		templateData.MetaAlias = naming.PackageAlias(metaPackage)
+		templateData.MetaQualifier = templateData.MetaAlias + "."
+	}
+
+	objectTemplate, err := template.New("object").Parse(OBJECT_TEMPLATE)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := objectTemplate.Execute(&buf, templateData); err != nil {
+		return nil, err
+	}
+
+	contents, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("cannot format generated code: %v", err)
+	}
+
+	return contents, nil
+}
@@ -0,0 +1,58 @@
+package split
+
+import (
+	"strings"
+	"testing"
+
+	openapi_spec "github.com/go-openapi/spec"
+)
+
+func TestRenderObjectInterface(t *testing.T) {
+	gitRepo := "github.com/kubewarden/k8s-objects"
+
+	deployment := kindSchema(map[string]interface{}{"group": "apps", "version": "v1", "kind": "Deployment"})
+	deployment.Properties["metadata"] = openapi_spec.Schema{
+		SchemaProps: openapi_spec.SchemaProps{
+			Ref: openapi_spec.MustCreateRef("#/definitions/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"),
+		},
+	}
+
+	plan := newRegistryTestPlan(t, openapi_spec.Definitions{
+		"io.k8s.api.apps.v1.Deployment": deployment,
+		"io.k8s.api.apps.v1.Scale": kindSchema(
+			map[string]interface{}{"group": "apps", "version": "v1", "kind": "Scale"}),
+		"io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta": {
+			SchemaProps: openapi_spec.SchemaProps{
+				Properties: map[string]openapi_spec.Schema{
+					"name": stringProperty(""),
+				},
+			},
+		},
+	})
+
+	contents, err := renderObjectInterface(plan.Packages["api/apps/v1"], gitRepo, &plan.Interfaces, plan.Naming)
+	if err != nil {
+		t.Fatalf("cannot render Object methods: %v", err)
+	}
+
+	code := string(contents)
+	if !strings.Contains(code, "func (m *Deployment) GetObjectMeta()") {
+		t.Errorf("kinds with ObjectMeta must implement Object:\n%s", code)
+	}
+	if strings.Contains(code, "Scale") {
+		t.Errorf("kinds without ObjectMeta must not implement Object:\n%s", code)
+	}
+
+	contents, err = renderObjectInterface(plan.Packages["apimachinery/pkg/apis/meta/v1"], gitRepo, &plan.Interfaces, plan.Naming)
+	if err != nil {
+		t.Fatalf("cannot render Object interface: %v", err)
+	}
+
+	code = string(contents)
+	if !strings.Contains(code, "type Object interface {") {
+		t.Errorf("cannot find Object interface inside of generated code:\n%s", code)
+	}
+	if strings.Contains(code, "import") {
+		t.Errorf("the meta package must not import itself:\n%s", code)
+	}
+}
@@ -0,0 +1,169 @@
+package split
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/template"
+
+	"github.com/go-openapi/swag"
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
+	"github.com/pkg/errors"
+)
+
+// Package, relative to the project root, that provides the PatchMeta type
+const STRATEGIC_PATCH_PACKAGE = "apimachinery/pkg/util/strategicpatch"
+
+const PATCH_METADATA_FILE_NAME = "patch_metadata.go"
+
+const STRATEGIC_PATCH_PACKAGE_CONTENTS = `// Code generated by k8s-objects-generator. DO NOT EDIT.
+
+package strategicpatch
+
+// PatchMeta describes how a field is handled by strategic merge patches and
+// server-side apply
+type PatchMeta struct {
+	// PatchStrategy is the value of x-kubernetes-patch-strategy, e.g. "merge"
+	// or "retainKeys,merge"
+	PatchStrategy string
+
+	// PatchMergeKey is the value of x-kubernetes-patch-merge-key
+	PatchMergeKey string
+
+	// ListType is the value of x-kubernetes-list-type: "atomic", "set" or "map"
+	ListType string
+
+	// ListMapKeys is the value of x-kubernetes-list-map-keys
+	ListMapKeys []string
+}
+`
+
+const PATCH_METADATA_TEMPLATE = `// Code generated by k8s-objects-generator. DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+	"{{ .StrategicPatchImport }}"
+)
+
+// PatchMetadata holds the strategic merge patch and list-type metadata of the
+// fields of the types of this package, indexed by Go type and JSON field name
+var PatchMetadata = map[string]map[string]strategicpatch.PatchMeta{
+{{- range .Types }}
+	{{ printf "%q" .TypeName }}: {
+	{{- range .Fields }}
+		{{ printf "%q" .Name }}: {
+			{{- if .Meta.PatchStrategy }}PatchStrategy: {{ printf "%q" .Meta.PatchStrategy }}, {{ end }}
+			{{- if .Meta.PatchMergeKey }}PatchMergeKey: {{ printf "%q" .Meta.PatchMergeKey }}, {{ end }}
+			{{- if .Meta.ListType }}ListType: {{ printf "%q" .Meta.ListType }}, {{ end }}
+			{{- if .Meta.ListMapKeys }}ListMapKeys: {{ printf "%#v" .Meta.ListMapKeys }}{{ end -}}
+		},
+	{{- end }}
+	},
+{{- end }}
+}
+`
+
+type patchMetadataField struct {
+	Name string
+	Meta swagger_helpers.PatchMetadata
+}
+
+type patchMetadataType struct {
+	TypeName string
+	Fields   []patchMetadataField
+}
+
+// Writes the package providing the PatchMeta type and, for each package
+// having fields with strategic merge patch or list-type metadata, the table
+// holding them
+func GeneratePatchMetadata(project Project, plan *RefactoringPlan) error {
+	strategicPatchDir := filepath.Join(project.Root, STRATEGIC_PATCH_PACKAGE)
+	if err := os.MkdirAll(strategicPatchDir, 0777); err != nil {
+		return errors.Wrapf(err, "cannot create dir %s", strategicPatchDir)
+	}
+	strategicPatchFileName := filepath.Join(strategicPatchDir, PATCH_METADATA_FILE_NAME)
+	if err := os.WriteFile(strategicPatchFileName, []byte(STRATEGIC_PATCH_PACKAGE_CONTENTS), 0644); err != nil {
+		return errors.Wrapf(err, "cannot write %s", strategicPatchFileName)
+	}
+
+	for pkgName, pkg := range plan.Packages {
+		contents, err := renderPatchMetadata(pkg, project.GitRepo)
+		if err != nil {
+			return errors.Wrapf(err, "cannot render patch metadata of package %s", pkgName)
+		}
+		if contents == nil {
+			continue
+		}
+
+		fileName := filepath.Join(project.Root, pkgName, PATCH_METADATA_FILE_NAME)
+		if err := os.WriteFile(fileName, contents, 0644); err != nil {
+			return errors.Wrapf(err, "cannot write %s", fileName)
+		}
+	}
+
+	return nil
+}
+
+// Returns the contents of the patch metadata table of the given package, nil
+// when none of its types has patch metadata
+func renderPatchMetadata(pkg swagger_helpers.Package, gitRepo string) ([]byte, error) {
+	types := []patchMetadataType{}
+
+	for _, def := range pkg.Definitions {
+		metadata := def.PatchMetadata()
+		if len(metadata) == 0 {
+			continue
+		}
+
+		fields := []patchMetadataField{}
+		for name, meta := range metadata {
+			fields = append(fields, patchMetadataField{Name: name, Meta: meta})
+		}
+		sort.Slice(fields, func(i, j int) bool {
+			return fields[i].Name < fields[j].Name
+		})
+
+		types = append(types, patchMetadataType{
+			TypeName: swag.ToGoName(def.TypeName),
+			Fields:   fields,
+		})
+	}
+
+	if len(types) == 0 {
+		return nil, nil
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].TypeName < types[j].TypeName
+	})
+
+	patchMetadataTemplate, err := template.New("patch_metadata").Parse(PATCH_METADATA_TEMPLATE)
+	if err != nil {
+		return nil, err
+	}
+
+	templateData := struct {
+		Package              string
+		StrategicPatchImport string
+		Types                []patchMetadataType
+	}{
+		Package:              filepath.Base(pkg.Name),
+		StrategicPatchImport: fmt.Sprintf("%s/%s", gitRepo, STRATEGIC_PATCH_PACKAGE),
+		Types:                types,
+	}
+
+	var buf bytes.Buffer
+	if err := patchMetadataTemplate.Execute(&buf, templateData); err != nil {
+		return nil, err
+	}
+
+	contents, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("cannot format generated code: %v", err)
+	}
+
+	return contents, nil
+}
@@ -0,0 +1,55 @@
+package split
+
+import (
+	"strings"
+	"testing"
+
+	openapi_spec "github.com/go-openapi/spec"
+)
+
+func TestRenderPatchMetadata(t *testing.T) {
+	containers := openapi_spec.Schema{
+		SchemaProps: openapi_spec.SchemaProps{
+			Type: []string{"array"},
+		},
+		VendorExtensible: openapi_spec.VendorExtensible{
+			Extensions: openapi_spec.Extensions{
+				"x-kubernetes-patch-merge-key": "name",
+				"x-kubernetes-patch-strategy":  "merge",
+				"x-kubernetes-list-type":       "map",
+				"x-kubernetes-list-map-keys":   []interface{}{"name"},
+			},
+		},
+	}
+
+	plan := newRegistryTestPlan(t, openapi_spec.Definitions{
+		"io.k8s.api.core.v1.PodSpec": objectSchema(map[string]openapi_spec.Schema{
+			"containers": containers,
+			"hostname":   stringProperty(""),
+		}),
+		"io.k8s.api.core.v1.Container": objectSchema(map[string]openapi_spec.Schema{
+			"name": stringProperty(""),
+		}),
+	})
+
+	contents, err := renderPatchMetadata(plan.Packages["api/core/v1"], "github.com/kubewarden/k8s-objects")
+	if err != nil {
+		t.Fatalf("cannot render patch metadata: %v", err)
+	}
+
+	code := string(contents)
+	expectedSnippets := []string{
+		`"github.com/kubewarden/k8s-objects/apimachinery/pkg/util/strategicpatch"`,
+		"var PatchMetadata = map[string]map[string]strategicpatch.PatchMeta{",
+		`"PodSpec": {`,
+		`"containers": {PatchStrategy: "merge", PatchMergeKey: "name", ListType: "map", ListMapKeys: []string{"name"}},`,
+	}
+	for _, snippet := range expectedSnippets {
+		if !strings.Contains(code, snippet) {
+			t.Errorf("cannot find %s inside of generated code:\n%s", snippet, code)
+		}
+	}
+	if strings.Contains(code, "Container\"") || strings.Contains(code, "hostname") {
+		t.Errorf("fields without metadata must not be listed:\n%s", code)
+	}
+}
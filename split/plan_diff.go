@@ -0,0 +1,254 @@
+package split
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	mapset "github.com/deckarep/golang-set"
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
+)
+
+// Differences between the API described by two refactoring plans, usually
+// built from the swagger files of two Kubernetes releases.
+// Types are identified by their Go name, e.g. `api/core/v1.Pod`
+type PlanDiff struct {
+	OldKubernetesVersion string     `json:"oldKubernetesVersion"`
+	NewKubernetesVersion string     `json:"newKubernetesVersion"`
+	AddedPackages        []string   `json:"addedPackages"`
+	RemovedPackages      []string   `json:"removedPackages"`
+	AddedTypes           []string   `json:"addedTypes"`
+	RemovedTypes         []string   `json:"removedTypes"`
+	ChangedTypes         []TypeDiff `json:"changedTypes"`
+}
+
+// Changes of the properties of a type defined by both plans
+type TypeDiff struct {
+	Type              string           `json:"type"`
+	AddedProperties   []PropertySchema `json:"addedProperties"`
+	RemovedProperties []PropertySchema `json:"removedProperties"`
+	ChangedProperties []PropertyChange `json:"changedProperties"`
+}
+
+type PropertySchema struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+}
+
+type PropertyChange struct {
+	Name        string `json:"name"`
+	OldType     string `json:"oldType"`
+	NewType     string `json:"newType"`
+	OldRequired bool   `json:"oldRequired"`
+	NewRequired bool   `json:"newRequired"`
+}
+
+// Computes the differences between the `oldPlan` and the `newPlan`
+func DiffPlans(oldPlan, newPlan *RefactoringPlan) PlanDiff {
+	diff := PlanDiff{
+		OldKubernetesVersion: oldPlan.KubernetesVersion,
+		NewKubernetesVersion: newPlan.KubernetesVersion,
+		AddedPackages:        []string{},
+		RemovedPackages:      []string{},
+		AddedTypes:           []string{},
+		RemovedTypes:         []string{},
+		ChangedTypes:         []TypeDiff{},
+	}
+
+	for pkgName := range newPlan.Packages {
+		if _, found := oldPlan.Packages[pkgName]; !found {
+			diff.AddedPackages = append(diff.AddedPackages, pkgName)
+		}
+	}
+	for pkgName := range oldPlan.Packages {
+		if _, found := newPlan.Packages[pkgName]; !found {
+			diff.RemovedPackages = append(diff.RemovedPackages, pkgName)
+		}
+	}
+
+	oldTypes := definitionsByGoType(oldPlan)
+	newTypes := definitionsByGoType(newPlan)
+
+	for typeName, newDef := range newTypes {
+		oldDef, found := oldTypes[typeName]
+		if !found {
+			diff.AddedTypes = append(diff.AddedTypes, typeName)
+			continue
+		}
+
+		typeDiff := diffDefinitions(typeName, oldDef, newDef, oldPlan.Naming, newPlan.Naming)
+		if !typeDiff.IsEmpty() {
+			diff.ChangedTypes = append(diff.ChangedTypes, typeDiff)
+		}
+	}
+	for typeName := range oldTypes {
+		if _, found := newTypes[typeName]; !found {
+			diff.RemovedTypes = append(diff.RemovedTypes, typeName)
+		}
+	}
+
+	sort.Strings(diff.AddedPackages)
+	sort.Strings(diff.RemovedPackages)
+	sort.Strings(diff.AddedTypes)
+	sort.Strings(diff.RemovedTypes)
+	sort.Slice(diff.ChangedTypes, func(i, j int) bool {
+		return diff.ChangedTypes[i].Type < diff.ChangedTypes[j].Type
+	})
+
+	return diff
+}
+
+func definitionsByGoType(plan *RefactoringPlan) map[string]*swagger_helpers.Definition {
+	definitions := make(map[string]*swagger_helpers.Definition)
+	for _, def := range plan.Definitions {
+		definitions[fmt.Sprintf("%s.%s", def.PackageName, def.TypeName)] = def
+	}
+	return definitions
+}
+
+func requiredProperties(def *swagger_helpers.Definition) mapset.Set {
+	required := mapset.NewSet()
+	for _, r := range def.SwaggerDefinition.Required {
+		required.Add(r)
+	}
+	return required
+}
+
+func diffDefinitions(typeName string, oldDef, newDef *swagger_helpers.Definition, oldNaming, newNaming swagger_helpers.Naming) TypeDiff {
+	typeDiff := TypeDiff{
+		Type:              typeName,
+		AddedProperties:   []PropertySchema{},
+		RemovedProperties: []PropertySchema{},
+		ChangedProperties: []PropertyChange{},
+	}
+
+	oldRequired := requiredProperties(oldDef)
+	newRequired := requiredProperties(newDef)
+
+	for name := range newDef.SwaggerDefinition.Properties {
+		newProperty := newDef.SwaggerDefinition.Properties[name]
+		newType := swagger_helpers.DescribeSchemaType(&newProperty, newNaming)
+
+		oldProperty, found := oldDef.SwaggerDefinition.Properties[name]
+		if !found {
+			typeDiff.AddedProperties = append(typeDiff.AddedProperties, PropertySchema{
+				Name:     name,
+				Type:     newType,
+				Required: newRequired.Contains(name),
+			})
+			continue
+		}
+
+		change := PropertyChange{
+			Name:        name,
+			OldType:     swagger_helpers.DescribeSchemaType(&oldProperty, oldNaming),
+			NewType:     newType,
+			OldRequired: oldRequired.Contains(name),
+			NewRequired: newRequired.Contains(name),
+		}
+		if change.OldType != change.NewType || change.OldRequired != change.NewRequired {
+			typeDiff.ChangedProperties = append(typeDiff.ChangedProperties, change)
+		}
+	}
+
+	for name := range oldDef.SwaggerDefinition.Properties {
+		if _, found := newDef.SwaggerDefinition.Properties[name]; !found {
+			oldProperty := oldDef.SwaggerDefinition.Properties[name]
+			typeDiff.RemovedProperties = append(typeDiff.RemovedProperties, PropertySchema{
+				Name:     name,
+				Type:     swagger_helpers.DescribeSchemaType(&oldProperty, oldNaming),
+				Required: oldRequired.Contains(name),
+			})
+		}
+	}
+
+	sort.Slice(typeDiff.AddedProperties, func(i, j int) bool {
+		return typeDiff.AddedProperties[i].Name < typeDiff.AddedProperties[j].Name
+	})
+	sort.Slice(typeDiff.RemovedProperties, func(i, j int) bool {
+		return typeDiff.RemovedProperties[i].Name < typeDiff.RemovedProperties[j].Name
+	})
+	sort.Slice(typeDiff.ChangedProperties, func(i, j int) bool {
+		return typeDiff.ChangedProperties[i].Name < typeDiff.ChangedProperties[j].Name
+	})
+
+	return typeDiff
+}
+
+func (t *TypeDiff) IsEmpty() bool {
+	return len(t.AddedProperties) == 0 &&
+		len(t.RemovedProperties) == 0 &&
+		len(t.ChangedProperties) == 0
+}
+
+func (d *PlanDiff) IsEmpty() bool {
+	return len(d.AddedPackages) == 0 &&
+		len(d.RemovedPackages) == 0 &&
+		len(d.AddedTypes) == 0 &&
+		len(d.RemovedTypes) == 0 &&
+		len(d.ChangedTypes) == 0
+}
+
+func describeRequired(required bool) string {
+	if required {
+		return "required"
+	}
+	return "optional"
+}
+
+// Writes a human readable report of the differences
+func (d *PlanDiff) WriteText(w io.Writer) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Kubernetes %s -> %s\n", d.OldKubernetesVersion, d.NewKubernetesVersion)
+	if d.IsEmpty() {
+		b.WriteString("\nNo API changes\n")
+	}
+
+	sections := []struct {
+		title  string
+		marker string
+		items  []string
+	}{
+		{"Added packages", "+", d.AddedPackages},
+		{"Removed packages", "-", d.RemovedPackages},
+		{"Added types", "+", d.AddedTypes},
+		{"Removed types", "-", d.RemovedTypes},
+	}
+	for _, section := range sections {
+		if len(section.items) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s:\n", section.title)
+		for _, item := range section.items {
+			fmt.Fprintf(&b, "  %s %s\n", section.marker, item)
+		}
+	}
+
+	if len(d.ChangedTypes) > 0 {
+		b.WriteString("\nChanged types:\n")
+	}
+	for _, typeDiff := range d.ChangedTypes {
+		fmt.Fprintf(&b, "  ~ %s\n", typeDiff.Type)
+		for _, prop := range typeDiff.AddedProperties {
+			fmt.Fprintf(&b, "      + %s: %s, %s\n", prop.Name, prop.Type, describeRequired(prop.Required))
+		}
+		for _, prop := range typeDiff.RemovedProperties {
+			fmt.Fprintf(&b, "      - %s: %s, %s\n", prop.Name, prop.Type, describeRequired(prop.Required))
+		}
+		for _, change := range typeDiff.ChangedProperties {
+			if change.OldType != change.NewType {
+				fmt.Fprintf(&b, "      ~ %s: %s -> %s\n", change.Name, change.OldType, change.NewType)
+			}
+			if change.OldRequired != change.NewRequired {
+				fmt.Fprintf(&b, "      ~ %s: %s -> %s\n", change.Name,
+					describeRequired(change.OldRequired), describeRequired(change.NewRequired))
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,137 @@
+package split
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	openapi_spec "github.com/go-openapi/spec"
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
+)
+
+func newTestPlan(t *testing.T, kubernetesVersion string, definitions openapi_spec.Definitions) *RefactoringPlan {
+	swagger := openapi_spec.Swagger{}
+	info := openapi_spec.Info{}
+	info.InfoProps.Version = kubernetesVersion
+	swagger.SwaggerProps.Info = &info
+	swagger.Definitions = definitions
+
+	plan, err := NewRefactoringPlan(&swagger, swagger_helpers.NamingOptions{})
+	if err != nil {
+		t.Fatalf("Cannot create refactoring plan: %v", err)
+	}
+	return plan
+}
+
+func stringProperty(format string) openapi_spec.Schema {
+	return openapi_spec.Schema{
+		SchemaProps: openapi_spec.SchemaProps{
+			Type:   []string{"string"},
+			Format: format,
+		},
+	}
+}
+
+func TestDiffPlans(t *testing.T) {
+	oldPlan := newTestPlan(t, "1.24", openapi_spec.Definitions{
+		"io.k8s.api.core.v1.Container": {
+			SchemaProps: openapi_spec.SchemaProps{
+				Properties: map[string]openapi_spec.Schema{
+					"name":  stringProperty(""),
+					"image": stringProperty(""),
+					"port":  stringProperty("int-or-string"),
+				},
+			},
+		},
+		"io.k8s.api.batch.v2alpha1.CronJob": {
+			SchemaProps: openapi_spec.SchemaProps{
+				Properties: map[string]openapi_spec.Schema{
+					"kind": stringProperty(""),
+				},
+			},
+		},
+	})
+	newPlan := newTestPlan(t, "1.25", openapi_spec.Definitions{
+		"io.k8s.api.core.v1.Container": {
+			SchemaProps: openapi_spec.SchemaProps{
+				Required: []string{"name"},
+				Properties: map[string]openapi_spec.Schema{
+					"name":       stringProperty(""),
+					"port":       stringProperty(""),
+					"workingDir": stringProperty(""),
+				},
+			},
+		},
+		"io.k8s.api.batch.v1.CronJob": {
+			SchemaProps: openapi_spec.SchemaProps{
+				Properties: map[string]openapi_spec.Schema{
+					"kind": stringProperty(""),
+				},
+			},
+		},
+	})
+
+	diff := DiffPlans(oldPlan, newPlan)
+
+	if len(diff.AddedPackages) != 1 || diff.AddedPackages[0] != "api/batch/v1" {
+		t.Errorf("wrong added packages: %v", diff.AddedPackages)
+	}
+	if len(diff.RemovedPackages) != 1 || diff.RemovedPackages[0] != "api/batch/v2alpha1" {
+		t.Errorf("wrong removed packages: %v", diff.RemovedPackages)
+	}
+	if len(diff.AddedTypes) != 1 || diff.AddedTypes[0] != "api/batch/v1.CronJob" {
+		t.Errorf("wrong added types: %v", diff.AddedTypes)
+	}
+	if len(diff.RemovedTypes) != 1 || diff.RemovedTypes[0] != "api/batch/v2alpha1.CronJob" {
+		t.Errorf("wrong removed types: %v", diff.RemovedTypes)
+	}
+
+	if len(diff.ChangedTypes) != 1 {
+		t.Fatalf("wrong number of changed types: %+v", diff.ChangedTypes)
+	}
+	typeDiff := diff.ChangedTypes[0]
+	if typeDiff.Type != "api/core/v1.Container" {
+		t.Errorf("wrong changed type: %s", typeDiff.Type)
+	}
+	if len(typeDiff.AddedProperties) != 1 || typeDiff.AddedProperties[0].Name != "workingDir" {
+		t.Errorf("wrong added properties: %+v", typeDiff.AddedProperties)
+	}
+	if len(typeDiff.RemovedProperties) != 1 || typeDiff.RemovedProperties[0].Name != "image" {
+		t.Errorf("wrong removed properties: %+v", typeDiff.RemovedProperties)
+	}
+
+	expectedChanges := []PropertyChange{
+		{Name: "name", OldType: "string", NewType: "string", OldRequired: false, NewRequired: true},
+		{Name: "port", OldType: "string(int-or-string)", NewType: "string", OldRequired: false, NewRequired: false},
+	}
+	if len(typeDiff.ChangedProperties) != len(expectedChanges) {
+		t.Fatalf("wrong changed properties: %+v", typeDiff.ChangedProperties)
+	}
+	for i, expected := range expectedChanges {
+		if typeDiff.ChangedProperties[i] != expected {
+			t.Errorf("wrong property change, expected %+v got %+v instead",
+				expected, typeDiff.ChangedProperties[i])
+		}
+	}
+
+	var report bytes.Buffer
+	if err := diff.WriteText(&report); err != nil {
+		t.Fatalf("cannot write report: %v", err)
+	}
+	expectedLines := []string{
+		"Kubernetes 1.24 -> 1.25",
+		"  + api/batch/v1.CronJob",
+		"      ~ name: optional -> required",
+		"      ~ port: string(int-or-string) -> string",
+	}
+	for _, line := range expectedLines {
+		if !strings.Contains(report.String(), line+"\n") {
+			t.Errorf("cannot find line %q inside of report:\n%s", line, report.String())
+		}
+	}
+
+	noChanges := DiffPlans(oldPlan, oldPlan)
+	if !noChanges.IsEmpty() {
+		t.Errorf("a plan compared to itself must not have changes: %+v", noChanges)
+	}
+}
@@ -0,0 +1,231 @@
+package split
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/go-openapi/swag"
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
+	"github.com/pkg/errors"
+)
+
+// ID of the PodSpec definition
+const POD_SPEC_ID = "io.k8s.api.core.v1.PodSpec"
+
+const POD_SPEC_ACCESSOR_FILE_NAME = "pod_spec_accessor.go"
+
+const POD_SPEC_ACCESSOR_TEMPLATE = `// Code generated by k8s-objects-generator. DO NOT EDIT.
+
+package {{ .Package }}
+{{ if .CoreImport }}
+import (
+	{{ .CoreAlias }} "{{ .CoreImport }}"
+)
+{{ end }}
+{{- if .DefinesInterface }}
+// PodSpecAccessor is implemented by all the types that embed a PodSpec, like
+// Pods and the workload resources
+type PodSpecAccessor interface {
+	// GetPodSpec returns the PodSpec, nil when it's not set
+	GetPodSpec() *PodSpec
+
+	// PodSpecPath returns the JSON pointer of the PodSpec, e.g. "/spec/template/spec"
+	PodSpecPath() string
+}
+{{ end }}
+{{- range .Accessors }}
+// GetPodSpec returns the PodSpec found at {{ .Path }}, nil when it's not set
+func (m *{{ .TypeName }}) GetPodSpec() *{{ $.CoreQualifier }}PodSpec {
+	if m == nil{{ range .NilChecks }} || m.{{ . }} == nil{{ end }} {
+		return nil
+	}
+	return m.{{ .FieldPath }}
+}
+
+// PodSpecPath returns the JSON pointer of the PodSpec
+func (m *{{ .TypeName }}) PodSpecPath() string {
+	return {{ printf "%q" .Path }}
+}
+{{ end }}
+`
+
+type podSpecAccessor struct {
+	TypeName string
+	// JSON pointer of the PodSpec, e.g. `/spec/template/spec`
+	Path string
+	// Go expression of the PodSpec field, e.g. `Spec.Template.Spec`
+	FieldPath string
+	// Go expressions of the intermediate fields, which can be nil
+	NilChecks []string
+}
+
+// Returns the shortest chain of properties that leads from each definition to
+// the PodSpec. Only the definitions that embed a PodSpec, at any depth, are
+// part of the result. Arrays and maps are not traversed.
+func podSpecPaths(definitions map[string]*swagger_helpers.Definition) map[string][]string {
+	paths := make(map[string][]string)
+
+	if _, found := definitions[POD_SPEC_ID]; !found {
+		return paths
+	}
+
+	for id := range definitions {
+		if id == POD_SPEC_ID {
+			continue
+		}
+		if path := shortestPathTo(definitions, id, POD_SPEC_ID); path != nil {
+			paths[id] = path
+		}
+	}
+
+	return paths
+}
+
+// Breadth first search of the property chain leading from one definition to
+// another one, nil when there's none
+func shortestPathTo(definitions map[string]*swagger_helpers.Definition, fromID, toID string) []string {
+	type node struct {
+		id   string
+		path []string
+	}
+
+	visited := map[string]bool{fromID: true}
+	queue := []node{{id: fromID}}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		def, found := definitions[current.id]
+		if !found {
+			continue
+		}
+
+		references := def.ObjectReferences()
+		names := make([]string, 0, len(references))
+		for name := range references {
+			names = append(names, name)
+		}
+		// ensure the same path is always picked
+		sort.Strings(names)
+
+		for _, name := range names {
+			refID := references[name]
+			path := append(append([]string{}, current.path...), name)
+			if refID == toID {
+				return path
+			}
+			if !visited[refID] {
+				visited[refID] = true
+				queue = append(queue, node{id: refID, path: path})
+			}
+		}
+	}
+
+	return nil
+}
+
+// Writes the PodSpecAccessor interface inside of the package that defines
+// the PodSpec, plus its implementations
+func GeneratePodSpecAccessors(project Project, plan *RefactoringPlan) error {
+	podSpec, found := plan.Definitions[POD_SPEC_ID]
+	if !found {
+		return nil
+	}
+	paths := podSpecPaths(plan.Definitions)
+
+	for pkgName, pkg := range plan.Packages {
+		contents, err := renderPodSpecAccessors(pkg, paths, podSpec.PackageName, project.GitRepo, plan.Naming)
+		if err != nil {
+			return errors.Wrapf(err, "cannot render PodSpec accessors of package %s", pkgName)
+		}
+		if contents == nil {
+			continue
+		}
+
+		fileName := filepath.Join(project.Root, pkgName, POD_SPEC_ACCESSOR_FILE_NAME)
+		if err := os.WriteFile(fileName, contents, 0644); err != nil {
+			return errors.Wrapf(err, "cannot write %s", fileName)
+		}
+	}
+
+	return nil
+}
+
+// Returns the contents of the PodSpec accessors of the given package, nil when
+// there's nothing to generate
+func renderPodSpecAccessors(pkg swagger_helpers.Package, paths map[string][]string, corePackage, gitRepo string, naming swagger_helpers.Naming) ([]byte, error) {
+	accessors := []podSpecAccessor{}
+
+	for _, def := range pkg.Definitions {
+		path, found := paths[def.ID]
+		if !found {
+			continue
+		}
+
+		fields := []string{}
+		nilChecks := []string{}
+		for i, name := range path {
+			fields = append(fields, swag.ToGoName(name))
+			if i < len(path)-1 {
+				nilChecks = append(nilChecks, strings.Join(fields, "."))
+			}
+		}
+
+		accessors = append(accessors, podSpecAccessor{
+			TypeName:  swag.ToGoName(def.TypeName),
+			Path:      "/" + strings.Join(path, "/"),
+			FieldPath: strings.Join(fields, "."),
+			NilChecks: nilChecks,
+		})
+	}
+	sort.Slice(accessors, func(i, j int) bool {
+		return accessors[i].TypeName < accessors[j].TypeName
+	})
+
+	definesInterface := pkg.Name == corePackage && len(paths) > 0
+	if len(accessors) == 0 && !definesInterface {
+		return nil, nil
+	}
+
+	templateData := struct {
+		Package          string
+		DefinesInterface bool
+		CoreImport       string
+		CoreAlias        string
+		CoreQualifier    string
+		Accessors        []podSpecAccessor
+	}{
+		Package:          filepath.Base(pkg.Name),
+		DefinesInterface: definesInterface,
+		Accessors:        accessors,
+	}
+	if pkg.Name != corePackage {
+		templateData.CoreImport = fmt.Sprintf("%s/%s", gitRepo, corePackage)
+		templateData.CoreAlias = naming.PackageAlias(corePackage)
+		templateData.CoreQualifier = templateData.CoreAlias + "."
+	}
+
+	accessorTemplate, err := template.New("pod_spec_accessor").Parse(POD_SPEC_ACCESSOR_TEMPLATE)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := accessorTemplate.Execute(&buf, templateData); err != nil {
+		return nil, err
+	}
+
+	contents, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("cannot format generated code: %v", err)
+	}
+
+	return contents, nil
+}
@@ -0,0 +1,136 @@
+package split
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	openapi_spec "github.com/go-openapi/spec"
+)
+
+func refProperty(id string) openapi_spec.Schema {
+	return openapi_spec.Schema{
+		SchemaProps: openapi_spec.SchemaProps{
+			Ref: openapi_spec.MustCreateRef("#/definitions/" + id),
+		},
+	}
+}
+
+func objectSchema(properties map[string]openapi_spec.Schema) openapi_spec.Schema {
+	return openapi_spec.Schema{
+		SchemaProps: openapi_spec.SchemaProps{
+			Properties: properties,
+		},
+	}
+}
+
+func newPodSpecTestPlan(t *testing.T) *RefactoringPlan {
+	return newRegistryTestPlan(t, openapi_spec.Definitions{
+		"io.k8s.api.core.v1.PodSpec": objectSchema(map[string]openapi_spec.Schema{
+			"hostname": stringProperty(""),
+		}),
+		"io.k8s.api.core.v1.Pod": objectSchema(map[string]openapi_spec.Schema{
+			"spec": refProperty("io.k8s.api.core.v1.PodSpec"),
+		}),
+		"io.k8s.api.core.v1.PodTemplateSpec": objectSchema(map[string]openapi_spec.Schema{
+			"spec": refProperty("io.k8s.api.core.v1.PodSpec"),
+		}),
+		"io.k8s.api.batch.v1.JobSpec": objectSchema(map[string]openapi_spec.Schema{
+			"template": refProperty("io.k8s.api.core.v1.PodTemplateSpec"),
+		}),
+		"io.k8s.api.batch.v1.JobTemplateSpec": objectSchema(map[string]openapi_spec.Schema{
+			"spec": refProperty("io.k8s.api.batch.v1.JobSpec"),
+		}),
+		"io.k8s.api.batch.v1.CronJobSpec": objectSchema(map[string]openapi_spec.Schema{
+			"jobTemplate": refProperty("io.k8s.api.batch.v1.JobTemplateSpec"),
+		}),
+		"io.k8s.api.batch.v1.CronJob": objectSchema(map[string]openapi_spec.Schema{
+			"spec": refProperty("io.k8s.api.batch.v1.CronJobSpec"),
+		}),
+		"io.k8s.api.core.v1.PodList": {
+			SchemaProps: openapi_spec.SchemaProps{
+				Properties: map[string]openapi_spec.Schema{
+					"items": {
+						SchemaProps: openapi_spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &openapi_spec.SchemaOrArray{
+								Schema: &openapi_spec.Schema{
+									SchemaProps: openapi_spec.SchemaProps{
+										Ref: openapi_spec.MustCreateRef("#/definitions/io.k8s.api.core.v1.Pod"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestPodSpecPaths(t *testing.T) {
+	plan := newPodSpecTestPlan(t)
+
+	expected := map[string][]string{
+		"io.k8s.api.core.v1.Pod":              {"spec"},
+		"io.k8s.api.core.v1.PodTemplateSpec":  {"spec"},
+		"io.k8s.api.batch.v1.JobSpec":         {"template", "spec"},
+		"io.k8s.api.batch.v1.JobTemplateSpec": {"spec", "template", "spec"},
+		"io.k8s.api.batch.v1.CronJobSpec":     {"jobTemplate", "spec", "template", "spec"},
+		"io.k8s.api.batch.v1.CronJob":         {"spec", "jobTemplate", "spec", "template", "spec"},
+	}
+
+	paths := podSpecPaths(plan.Definitions)
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("wrong paths, expected %v got %v instead", expected, paths)
+	}
+}
+
+func TestRenderPodSpecAccessors(t *testing.T) {
+	gitRepo := "github.com/kubewarden/k8s-objects"
+	plan := newPodSpecTestPlan(t)
+	paths := podSpecPaths(plan.Definitions)
+
+	contents, err := renderPodSpecAccessors(plan.Packages["api/batch/v1"], paths, "api/core/v1", gitRepo, plan.Naming)
+	if err != nil {
+		t.Fatalf("cannot render accessors: %v", err)
+	}
+
+	code := string(contents)
+	expectedSnippets := []string{
+		`api_core_v1 "github.com/kubewarden/k8s-objects/api/core/v1"`,
+		"func (m *CronJob) GetPodSpec() *api_core_v1.PodSpec {",
+		"if m == nil || m.Spec == nil || m.Spec.JobTemplate == nil || m.Spec.JobTemplate.Spec == nil || m.Spec.JobTemplate.Spec.Template == nil {",
+		"return m.Spec.JobTemplate.Spec.Template.Spec",
+		`return "/spec/jobTemplate/spec/template/spec"`,
+	}
+	for _, snippet := range expectedSnippets {
+		if !strings.Contains(code, snippet) {
+			t.Errorf("cannot find %s inside of generated code:\n%s", snippet, code)
+		}
+	}
+	if strings.Contains(code, "PodSpecAccessor interface") {
+		t.Errorf("the interface must be defined only by the core package:\n%s", code)
+	}
+
+	contents, err = renderPodSpecAccessors(plan.Packages["api/core/v1"], paths, "api/core/v1", gitRepo, plan.Naming)
+	if err != nil {
+		t.Fatalf("cannot render accessors: %v", err)
+	}
+
+	code = string(contents)
+	expectedSnippets = []string{
+		"type PodSpecAccessor interface {",
+		"func (m *Pod) GetPodSpec() *PodSpec {",
+		"if m == nil {",
+		`return "/spec"`,
+	}
+	for _, snippet := range expectedSnippets {
+		if !strings.Contains(code, snippet) {
+			t.Errorf("cannot find %s inside of generated code:\n%s", snippet, code)
+		}
+	}
+	if strings.Contains(code, "import") || strings.Contains(code, "PodList") {
+		t.Errorf("unexpected generated code:\n%s", code)
+	}
+}
@@ -0,0 +1,1145 @@
+package split
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
+	"github.com/pkg/errors"
+)
+
+const QUANTITY_FILE_NAME = "quantity.go"
+const QUANTITY_TEST_FILE_NAME = "quantity_test.go"
+
+const QUANTITY_TEMPLATE = `// Code generated by k8s-objects-generator. DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/mailru/easyjson/jlexer"
+	"github.com/mailru/easyjson/jwriter"
+)
+
+// Format lists the three possible formattings of a quantity
+type Format string
+
+const (
+	// DecimalExponent is the format of quantities like 12e6
+	DecimalExponent = Format("DecimalExponent")
+	// BinarySI is the format of quantities like 12Mi (12 * 2^20)
+	BinarySI = Format("BinarySI")
+	// DecimalSI is the format of quantities like 12M (12 * 10^6)
+	DecimalSI = Format("DecimalSI")
+)
+
+// Scale is used for getting and setting the base-10 scaled value. Base-2
+// scales are omitted for mathematical simplicity
+type Scale int32
+
+const (
+	Nano  Scale = -9
+	Micro Scale = -6
+	Milli Scale = -3
+	Kilo  Scale = 3
+	Mega  Scale = 6
+	Giga  Scale = 9
+	Tera  Scale = 12
+	Peta  Scale = 15
+	Exa   Scale = 18
+)
+
+var (
+	// ErrFormatWrong is returned when the string is not a quantity
+	ErrFormatWrong = errors.New("quantities must match the regular expression '^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$'")
+	// ErrNumeric is returned when the numeric part of the quantity cannot be
+	// represented
+	ErrNumeric = errors.New("unable to parse numeric part of quantity")
+)
+
+var decimalSuffixes = map[string]Scale{
+	"n": Nano,
+	"u": Micro,
+	"m": Milli,
+	"":  0,
+	"k": Kilo,
+	"M": Mega,
+	"G": Giga,
+	"T": Tera,
+	"P": Peta,
+	"E": Exa,
+}
+
+var binarySuffixes = []string{"", "Ki", "Mi", "Gi", "Ti", "Pi", "Ei"}
+
+// Quantity is a fixed-point representation of a number, like "100m" or
+// "1Gi". It provides the same serialization and the same canonical form of
+// the upstream type, without relying on math/big: the number is stored as
+// value * 10^scale, with value being a 64 bit integer. Values smaller than
+// 1n are rounded up, away from zero.
+//
+// Quantities are always kept in a normalized form, hence two quantities
+// with the same value and format can be compared with ==.
+type Quantity struct {
+	value int64
+	scale Scale
+
+	// Format is used to serialize the quantity
+	Format Format
+}
+
+// NewQuantity returns a new Quantity representing the given value in the
+// given format
+func NewQuantity(value int64, format Format) *Quantity {
+	return newQuantity(value, 0, format)
+}
+
+// NewMilliQuantity returns a new Quantity representing the given value * 1/1000
+// in the given format
+func NewMilliQuantity(value int64, format Format) *Quantity {
+	return newQuantity(value, Milli, format)
+}
+
+// NewScaledQuantity returns a new Quantity representing the given value *
+// 10^scale in DecimalSI format
+func NewScaledQuantity(value int64, scale Scale) *Quantity {
+	return newQuantity(value, scale, DecimalSI)
+}
+
+func newQuantity(value int64, scale Scale, format Format) *Quantity {
+	q := &Quantity{value: value, scale: scale, Format: format}
+	q.normalize()
+	return q
+}
+
+// MustParse turns the given string into a quantity or panics
+func MustParse(str string) Quantity {
+	q, err := ParseQuantity(str)
+	if err != nil {
+		panic("cannot parse '" + str + "': " + err.Error())
+	}
+	return q
+}
+
+// ParseQuantity turns str into a Quantity, or returns an error. Quantities
+// whose significant digits do not fit into 64 bits are rounded up.
+func ParseQuantity(str string) (Quantity, error) {
+	pos := 0
+	negative := false
+	if pos < len(str) && (str[pos] == '-' || str[pos] == '+') {
+		negative = str[pos] == '-'
+		pos++
+	}
+
+	start := pos
+	for pos < len(str) && isDigit(str[pos]) {
+		pos++
+	}
+	whole := str[start:pos]
+	fraction := ""
+	if pos < len(str) && str[pos] == '.' {
+		pos++
+		start = pos
+		for pos < len(str) && isDigit(str[pos]) {
+			pos++
+		}
+		fraction = str[start:pos]
+	}
+	if whole == "" && fraction == "" {
+		return Quantity{}, ErrFormatWrong
+	}
+
+	format, binaryExponent, exponent, ok := parseSuffix(str[pos:])
+	if !ok {
+		return Quantity{}, ErrFormatWrong
+	}
+
+	digits := strings.TrimLeft(whole+fraction, "0")
+	scale := exponent - int64(len(fraction))
+	trimmed := strings.TrimRight(digits, "0")
+	scale += int64(len(digits) - len(trimmed))
+	digits = trimmed
+
+	roundUp := false
+	if len(digits) > 18 {
+		// the dropped digits are not zeros, they have been trimmed
+		scale += int64(len(digits) - 18)
+		digits = digits[:18]
+		roundUp = true
+	}
+
+	var value int64
+	if digits != "" {
+		var err error
+		if value, err = strconv.ParseInt(digits, 10, 64); err != nil {
+			return Quantity{}, ErrNumeric
+		}
+	}
+	if roundUp {
+		value++
+	}
+	if negative {
+		value = -value
+	}
+
+	if binaryExponent > 0 {
+		var ok bool
+		if value, ok = mulInt64(value, int64(1)<<(10*binaryExponent)); !ok {
+			return Quantity{}, ErrNumeric
+		}
+	}
+
+	if scale < int64(Nano) {
+		value = divideRoundingUp(value, int64(Nano)-scale)
+		scale = int64(Nano)
+	}
+	if scale > math.MaxInt32/2 {
+		return Quantity{}, ErrNumeric
+	}
+
+	return *newQuantity(value, Scale(scale), format), nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// Returns the format described by the suffix of a quantity, together with the
+// power of 1024 or the power of 10 it stands for
+func parseSuffix(suffix string) (format Format, binaryExponent int, exponent int64, ok bool) {
+	if scale, found := decimalSuffixes[suffix]; found {
+		return DecimalSI, 0, int64(scale), true
+	}
+	for i, s := range binarySuffixes {
+		if i > 0 && s == suffix {
+			return BinarySI, i, 0, true
+		}
+	}
+	if len(suffix) > 1 && (suffix[0] == 'e' || suffix[0] == 'E') {
+		exponent, err := strconv.ParseInt(suffix[1:], 10, 32)
+		if err != nil {
+			return "", 0, 0, false
+		}
+		return DecimalExponent, 0, exponent, true
+	}
+	return "", 0, 0, false
+}
+
+// Removes the trailing zeros of the value, this ensures each number has a
+// single representation
+func (q *Quantity) normalize() {
+	if q.value == 0 {
+		q.scale = 0
+		return
+	}
+	if q.scale < Nano {
+		q.value = divideRoundingUp(q.value, int64(Nano-q.scale))
+		q.scale = Nano
+	}
+	for q.value%10 == 0 {
+		q.value /= 10
+		q.scale++
+	}
+}
+
+// String returns the canonical form of the quantity: no precision is lost,
+// no fractional digits are emitted and the exponent, or the suffix, is as
+// large as possible
+func (q Quantity) String() string {
+	if q.value == 0 {
+		return "0"
+	}
+
+	switch q.Format {
+	case BinarySI:
+		if s, ok := q.binaryString(); ok {
+			return s
+		}
+		// values that are smaller than 1Ki or that are not integers are
+		// shown in decimal form
+		return q.decimalString(DecimalSI)
+	case DecimalExponent:
+		return q.decimalString(DecimalExponent)
+	default:
+		return q.decimalString(DecimalSI)
+	}
+}
+
+func (q Quantity) binaryString() (string, bool) {
+	if q.scale < 0 {
+		return "", false
+	}
+	value, ok := scaleUp(q.value, q.scale)
+	if !ok || (value > -1024 && value < 1024) {
+		return "", false
+	}
+
+	i := 0
+	for i < len(binarySuffixes)-1 && value%1024 == 0 {
+		value /= 1024
+		i++
+	}
+	return strconv.FormatInt(value, 10) + binarySuffixes[i], true
+}
+
+func (q Quantity) decimalString(format Format) string {
+	digits := strconv.FormatInt(q.value, 10)
+
+	// the exponent must be a multiple of 3
+	exponent := int64(q.scale)
+	extra := exponent % 3
+	if extra < 0 {
+		extra += 3
+	}
+	exponent -= extra
+	digits += strings.Repeat("0", int(extra))
+
+	if format == DecimalExponent {
+		if exponent == 0 {
+			return digits
+		}
+		return digits + "e" + strconv.FormatInt(exponent, 10)
+	}
+
+	if exponent > int64(Exa) {
+		digits += strings.Repeat("0", int(exponent-int64(Exa)))
+		exponent = int64(Exa)
+	}
+	for suffix, scale := range decimalSuffixes {
+		if int64(scale) == exponent {
+			return digits + suffix
+		}
+	}
+	return digits
+}
+
+// IsZero returns true if the quantity is equal to zero
+func (q Quantity) IsZero() bool {
+	return q.value == 0
+}
+
+// Sign returns 0 if the quantity is zero, -1 if the quantity is less than
+// zero, or 1 if the quantity is greater than zero
+func (q Quantity) Sign() int {
+	return sign(q.value)
+}
+
+// Cmp returns 0 if the quantity is equal to y, -1 if the quantity is less
+// than y, or 1 if the quantity is greater than y
+func (q Quantity) Cmp(y Quantity) int {
+	if q.scale >= y.scale {
+		if value, ok := scaleUp(q.value, q.scale-y.scale); ok {
+			return cmpInt64(value, y.value)
+		}
+		// the magnitude of q is larger than the one of y
+		return sign(q.value)
+	}
+
+	if value, ok := scaleUp(y.value, y.scale-q.scale); ok {
+		return cmpInt64(q.value, value)
+	}
+	return -sign(y.value)
+}
+
+// Equal returns true when the quantities have the same amount, whatever
+// their format, e.g. 1Gi and 1073741824. Nil quantities are only equal to
+// each other
+func (q *Quantity) Equal(other *Quantity) bool {
+	if q == nil || other == nil {
+		return q == other
+	}
+	return q.Cmp(*other) == 0
+}
+
+// CmpInt64 returns 0 if the quantity is equal to y, -1 if the quantity is
+// less than y, or 1 if the quantity is greater than y
+func (q Quantity) CmpInt64(y int64) int {
+	return q.Cmp(Quantity{value: y})
+}
+
+// Value returns the unscaled value of the quantity rounded up to the nearest
+// integer away from 0
+func (q Quantity) Value() int64 {
+	return q.ScaledValue(0)
+}
+
+// MilliValue returns the value of ceil(q * 1000)
+func (q Quantity) MilliValue() int64 {
+	return q.ScaledValue(Milli)
+}
+
+// ScaledValue returns the value of ceil(q / 10^scale), e.g. 1.1k scaled to
+// Kilo is 2. The result saturates when it doesn't fit into an int64
+func (q Quantity) ScaledValue(scale Scale) int64 {
+	if q.scale < scale {
+		return divideRoundingUp(q.value, int64(scale-q.scale))
+	}
+
+	value, ok := scaleUp(q.value, q.scale-scale)
+	if !ok {
+		if q.value > 0 {
+			return math.MaxInt64
+		}
+		return math.MinInt64
+	}
+	return value
+}
+
+// AsInt64 returns the value of the quantity as an int64 if it can be
+// represented exactly
+func (q Quantity) AsInt64() (int64, bool) {
+	if q.scale < 0 {
+		return 0, false
+	}
+	return scaleUp(q.value, q.scale)
+}
+
+// AsApproximateFloat64 returns a float64 representation of the quantity,
+// which may lose precision
+func (q Quantity) AsApproximateFloat64() float64 {
+	return float64(q.value) * math.Pow10(int(q.scale))
+}
+
+// Set sets the value of the quantity
+func (q *Quantity) Set(value int64) {
+	q.SetScaled(value, 0)
+}
+
+// SetMilli sets the value of the quantity to value / 1000
+func (q *Quantity) SetMilli(value int64) {
+	q.SetScaled(value, Milli)
+}
+
+// SetScaled sets the value of the quantity to value * 10^scale
+func (q *Quantity) SetScaled(value int64, scale Scale) {
+	q.value = value
+	q.scale = scale
+	q.normalize()
+}
+
+// Add adds y to the quantity. The least significant digits are rounded
+// when the result cannot be represented with 64 bits
+func (q *Quantity) Add(y Quantity) {
+	if q.IsZero() {
+		q.Format = y.Format
+	}
+	q.value, q.scale = add(q.value, q.scale, y.value, y.scale)
+	q.normalize()
+}
+
+// Sub subtracts y from the quantity. The least significant digits are rounded
+// when the result cannot be represented with 64 bits
+func (q *Quantity) Sub(y Quantity) {
+	y.Neg()
+	q.Add(y)
+}
+
+// Neg sets the quantity to its negative value
+func (q *Quantity) Neg() {
+	if q.value == math.MinInt64 {
+		q.value = divideRoundingNearest(q.value, 1)
+		q.scale++
+	}
+	q.value = -q.value
+}
+
+// Mul multiplies the quantity by y and returns true if the result is exact
+func (q *Quantity) Mul(y int64) bool {
+	exact := true
+	for {
+		if value, ok := mulInt64(q.value, y); ok {
+			q.value = value
+			q.normalize()
+			return exact
+		}
+		q.value = divideRoundingNearest(q.value, 1)
+		q.scale++
+		exact = false
+	}
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (q Quantity) MarshalEasyJSON(w *jwriter.Writer) {
+	w.String(q.String())
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (q Quantity) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	q.MarshalEasyJSON(&w)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface. Both strings
+// and numbers are accepted
+func (q *Quantity) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	if l.IsNull() {
+		l.Skip()
+		*q = Quantity{}
+		return
+	}
+
+	raw := l.Raw()
+	if !l.Ok() {
+		return
+	}
+	str := string(raw)
+	if len(raw) > 0 && raw[0] == '"' {
+		value := jlexer.Lexer{Data: raw}
+		str = value.String()
+		if err := value.Error(); err != nil {
+			l.AddError(err)
+			return
+		}
+	}
+
+	parsed, err := ParseQuantity(strings.TrimSpace(str))
+	if err != nil {
+		l.AddError(err)
+		return
+	}
+	*q = parsed
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (q *Quantity) UnmarshalJSON(data []byte) error {
+	l := jlexer.Lexer{Data: data}
+	q.UnmarshalEasyJSON(&l)
+	return l.Error()
+}
+
+func sign(value int64) int {
+	switch {
+	case value < 0:
+		return -1
+	case value > 0:
+		return 1
+	}
+	return 0
+}
+
+func cmpInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Returns value * 10^digits, false when the result doesn't fit into an int64
+func scaleUp(value int64, digits Scale) (int64, bool) {
+	if value == 0 {
+		return 0, true
+	}
+	if digits > 18 {
+		return 0, false
+	}
+	multiplier := int64(1)
+	for i := Scale(0); i < digits; i++ {
+		multiplier *= 10
+	}
+	return mulInt64(value, multiplier)
+}
+
+// Returns value / 10^digits, rounded away from zero
+func divideRoundingUp(value int64, digits int64) int64 {
+	if digits > 18 {
+		return int64(sign(value))
+	}
+	divisor := int64(1)
+	for i := int64(0); i < digits; i++ {
+		divisor *= 10
+	}
+	quotient, remainder := value/divisor, value%divisor
+	switch {
+	case remainder > 0:
+		quotient++
+	case remainder < 0:
+		quotient--
+	}
+	return quotient
+}
+
+// Returns value / 10^digits, rounded to the nearest integer, halves are
+// rounded away from zero
+func divideRoundingNearest(value int64, digits int64) int64 {
+	if digits > 18 {
+		return 0
+	}
+	divisor := int64(1)
+	for i := int64(0); i < digits; i++ {
+		divisor *= 10
+	}
+	quotient, remainder := value/divisor, value%divisor
+	switch {
+	case remainder >= divisor-remainder:
+		quotient++
+	case -remainder >= divisor+remainder:
+		quotient--
+	}
+	return quotient
+}
+
+func mulInt64(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	c := a * b
+	if (c < 0) != ((a < 0) != (b < 0)) || c/b != a {
+		return 0, false
+	}
+	return c, true
+}
+
+func addInt64(a, b int64) (int64, bool) {
+	c := a + b
+	if (c > a) != (b > 0) {
+		return 0, false
+	}
+	return c, true
+}
+
+// Returns the sum of a * 10^aScale and b * 10^bScale. The least significant
+// digits are rounded when the sum doesn't fit into 64 bits
+func add(a int64, aScale Scale, b int64, bScale Scale) (int64, Scale) {
+	for {
+		scale := aScale
+		if bScale < scale {
+			scale = bScale
+		}
+		x, xOk := scaleUp(a, aScale-scale)
+		y, yOk := scaleUp(b, bScale-scale)
+		if xOk && yOk {
+			if sum, ok := addInt64(x, y); ok {
+				return sum, scale
+			}
+		}
+
+		// drop a digit of the most precise value
+		if aScale <= bScale {
+			a = divideRoundingNearest(a, 1)
+			aScale++
+		} else {
+			b = divideRoundingNearest(b, 1)
+			bScale++
+		}
+	}
+}
+`
+
+// The examples are taken from the test suite of the upstream Quantity type
+const QUANTITY_TEST_TEMPLATE = `// Code generated by k8s-objects-generator. DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func decQuantity(value int64, scale Scale, format Format) Quantity {
+	q := newQuantity(value, scale, format)
+	return *q
+}
+
+func TestQuantityParse(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected Quantity
+	}{
+		{"0", decQuantity(0, 0, DecimalSI)},
+		{"0n", decQuantity(0, 0, DecimalSI)},
+		{"0Ki", decQuantity(0, 0, BinarySI)},
+		{"0Mi", decQuantity(0, 0, BinarySI)},
+		{"0e3", decQuantity(0, 0, DecimalExponent)},
+
+		// binary suffixes
+		{"1Ki", decQuantity(1024, 0, BinarySI)},
+		{"8Ki", decQuantity(8*1024, 0, BinarySI)},
+		{"7Mi", decQuantity(7*1024*1024, 0, BinarySI)},
+		{"6Gi", decQuantity(6*1024*1024*1024, 0, BinarySI)},
+		{"5Ti", decQuantity(5*1024*1024*1024*1024, 0, BinarySI)},
+		{"4Pi", decQuantity(4*1024*1024*1024*1024*1024, 0, BinarySI)},
+		{"3Ei", decQuantity(3*1024*1024*1024*1024*1024*1024, 0, BinarySI)},
+		{"10Ti", decQuantity(10*1024*1024*1024*1024, 0, BinarySI)},
+		{"100Ti", decQuantity(100*1024*1024*1024*1024, 0, BinarySI)},
+
+		// decimal suffixes
+		{"5n", decQuantity(5, -9, DecimalSI)},
+		{"4u", decQuantity(4, -6, DecimalSI)},
+		{"3m", decQuantity(3, -3, DecimalSI)},
+		{"9", decQuantity(9, 0, DecimalSI)},
+		{"8k", decQuantity(8, 3, DecimalSI)},
+		{"50k", decQuantity(5, 4, DecimalSI)},
+		{"7M", decQuantity(7, 6, DecimalSI)},
+		{"6G", decQuantity(6, 9, DecimalSI)},
+		{"5T", decQuantity(5, 12, DecimalSI)},
+		{"40T", decQuantity(4, 13, DecimalSI)},
+		{"300T", decQuantity(3, 14, DecimalSI)},
+		{"2P", decQuantity(2, 15, DecimalSI)},
+		{"1E", decQuantity(1, 18, DecimalSI)},
+
+		// decimal exponents
+		{"1E-3", decQuantity(1, -3, DecimalExponent)},
+		{"1e3", decQuantity(1, 3, DecimalExponent)},
+		{"1E6", decQuantity(1, 6, DecimalExponent)},
+		{"1e9", decQuantity(1, 9, DecimalExponent)},
+		{"1E12", decQuantity(1, 12, DecimalExponent)},
+		{"1e15", decQuantity(1, 15, DecimalExponent)},
+		{"1E18", decQuantity(1, 18, DecimalExponent)},
+
+		// nonstandard but still parsable
+		{"1e14", decQuantity(1, 14, DecimalExponent)},
+		{"1e13", decQuantity(1, 13, DecimalExponent)},
+		{"100.035k", decQuantity(100035, 0, DecimalSI)},
+
+		// things that look like floating point
+		{"0.001", decQuantity(1, -3, DecimalSI)},
+		{"0.0005k", decQuantity(5, -1, DecimalSI)},
+		{"0.005", decQuantity(5, -3, DecimalSI)},
+		{"0.05", decQuantity(5, -2, DecimalSI)},
+		{"0.5", decQuantity(5, -1, DecimalSI)},
+		{"0.00050k", decQuantity(5, -1, DecimalSI)},
+		{"0.00500", decQuantity(5, -3, DecimalSI)},
+		{"0.05000", decQuantity(5, -2, DecimalSI)},
+		{"0.50000", decQuantity(5, -1, DecimalSI)},
+		{"0.5e0", decQuantity(5, -1, DecimalExponent)},
+		{"0.5e-1", decQuantity(5, -2, DecimalExponent)},
+		{"0.5e-2", decQuantity(5, -3, DecimalExponent)},
+		{"10.035M", decQuantity(10035, 3, DecimalSI)},
+		{"1.2e3", decQuantity(12, 2, DecimalExponent)},
+		{"1.3E+6", decQuantity(13, 5, DecimalExponent)},
+		{"1.40e9", decQuantity(14, 8, DecimalExponent)},
+		{"1.53E12", decQuantity(153, 10, DecimalExponent)},
+		{"1.6e15", decQuantity(16, 14, DecimalExponent)},
+		{"1.7E18", decQuantity(17, 17, DecimalExponent)},
+		{"9.01", decQuantity(901, -2, DecimalSI)},
+		{"8.1k", decQuantity(81, 2, DecimalSI)},
+		{"7.123456M", decQuantity(7123456, 0, DecimalSI)},
+		{"6.987654321G", decQuantity(6987654321, 0, DecimalSI)},
+		{"5.444T", decQuantity(5444, 9, DecimalSI)},
+		{"40.1T", decQuantity(401, 11, DecimalSI)},
+		{"300.2T", decQuantity(3002, 11, DecimalSI)},
+		{"2.5P", decQuantity(25, 14, DecimalSI)},
+		{"1.01E", decQuantity(101, 16, DecimalSI)},
+		{"1.5Gi", decQuantity(1536*1024*1024, 0, BinarySI)},
+		{"0.5Ki", decQuantity(512, 0, BinarySI)},
+
+		// things that round up
+		{"3.001n", decQuantity(4, -9, DecimalSI)},
+		{"1.1E-9", decQuantity(2, -9, DecimalExponent)},
+		{"0.0000000001", decQuantity(1, -9, DecimalSI)},
+		{"0.0000000005", decQuantity(1, -9, DecimalSI)},
+		{"0.00000000050", decQuantity(1, -9, DecimalSI)},
+		{"0.5e-9", decQuantity(1, -9, DecimalExponent)},
+		{"0.9n", decQuantity(1, -9, DecimalSI)},
+		{"0.00000012345", decQuantity(124, -9, DecimalSI)},
+		{"0.00000012354", decQuantity(124, -9, DecimalSI)},
+		{"-0.0000000001", decQuantity(-1, -9, DecimalSI)},
+
+		// leading and trailing dots
+		{".001", decQuantity(1, -3, DecimalSI)},
+		{".0001k", decQuantity(1, -1, DecimalSI)},
+		{"1.", decQuantity(1, 0, DecimalSI)},
+		{"1.G", decQuantity(1, 9, DecimalSI)},
+
+		// signs
+		{"-5", decQuantity(-5, 0, DecimalSI)},
+		{"+5", decQuantity(5, 0, DecimalSI)},
+		{"-1.5Gi", decQuantity(-1536*1024*1024, 0, BinarySI)},
+	}
+
+	for _, c := range cases {
+		q, err := ParseQuantity(c.input)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.input, err)
+			continue
+		}
+		if q != c.expected {
+			t.Errorf("%s: expected %#v, got %#v", c.input, c.expected, q)
+		}
+	}
+}
+
+func TestQuantityParseInvalid(t *testing.T) {
+	cases := []string{
+		"",
+		".",
+		"-",
+		"+",
+		"Ki",
+		"1.1.1",
+		"1.1.M",
+		"1+1.0M",
+		"0.1mi",
+		"0.1am",
+		"aoeu",
+		".5i",
+		"1i",
+		"1e",
+		"1K",
+		"1ki",
+		"-3.01i",
+		"-3.01e-",
+		"1 Ki",
+		" 1",
+		"1 ",
+	}
+
+	for _, c := range cases {
+		if _, err := ParseQuantity(c); err == nil {
+			t.Errorf("%q: expected an error", c)
+		}
+	}
+
+	if _, err := ParseQuantity("1e9999999999"); err == nil {
+		t.Errorf("expected an error when the exponent is too large")
+	}
+	if _, err := ParseQuantity("8Ei"); err != ErrNumeric {
+		t.Errorf("expected a numeric error when the value is too large, got %v", err)
+	}
+}
+
+func TestQuantityString(t *testing.T) {
+	cases := []struct {
+		input    Quantity
+		expected string
+	}{
+		{decQuantity(1024*1024*1024, 0, BinarySI), "1Gi"},
+		{decQuantity(300*1024*1024, 0, BinarySI), "300Mi"},
+		{decQuantity(6*1024, 0, BinarySI), "6Ki"},
+		{decQuantity(1001*1024*1024*1024, 0, BinarySI), "1001Gi"},
+		{decQuantity(1024*1024*1024*1024, 0, BinarySI), "1Ti"},
+		{decQuantity(5, 0, BinarySI), "5"},
+		{decQuantity(500, -3, BinarySI), "500m"},
+		{decQuantity(1, 9, DecimalSI), "1G"},
+		{decQuantity(1000, 6, DecimalSI), "1G"},
+		{decQuantity(1000000, 3, DecimalSI), "1G"},
+		{decQuantity(1000000000, 0, DecimalSI), "1G"},
+		{decQuantity(1, -3, DecimalSI), "1m"},
+		{decQuantity(80, -3, DecimalSI), "80m"},
+		{decQuantity(1080, -3, DecimalSI), "1080m"},
+		{decQuantity(108, -2, DecimalSI), "1080m"},
+		{decQuantity(10800, -4, DecimalSI), "1080m"},
+		{decQuantity(300, 6, DecimalSI), "300M"},
+		{decQuantity(1, 12, DecimalSI), "1T"},
+		{decQuantity(1234567, 6, DecimalSI), "1234567M"},
+		{decQuantity(1234567, -3, BinarySI), "1234567m"},
+		{decQuantity(3, 3, DecimalSI), "3k"},
+		{decQuantity(1025, 0, BinarySI), "1025"},
+		{decQuantity(0, 0, DecimalSI), "0"},
+		{decQuantity(0, 0, BinarySI), "0"},
+		{decQuantity(1, 9, DecimalExponent), "1e9"},
+		{decQuantity(1, -3, DecimalExponent), "1e-3"},
+		{decQuantity(1, -9, DecimalExponent), "1e-9"},
+		{decQuantity(80, -3, DecimalExponent), "80e-3"},
+		{decQuantity(300, 6, DecimalExponent), "300e6"},
+		{decQuantity(1, 12, DecimalExponent), "1e12"},
+		{decQuantity(1, 3, DecimalExponent), "1e3"},
+		{decQuantity(3, 3, DecimalExponent), "3e3"},
+		{decQuantity(3, 3, DecimalSI), "3k"},
+		{decQuantity(0, 0, DecimalExponent), "0"},
+		{decQuantity(1, -9, DecimalSI), "1n"},
+		{decQuantity(80, -9, DecimalSI), "80n"},
+		{decQuantity(1080, -9, DecimalSI), "1080n"},
+		{decQuantity(108, -8, DecimalSI), "1080n"},
+		{decQuantity(10800, -10, DecimalSI), "1080n"},
+		{decQuantity(1, -6, DecimalSI), "1u"},
+		{decQuantity(80, -6, DecimalSI), "80u"},
+		{decQuantity(1080, -6, DecimalSI), "1080u"},
+		{decQuantity(1, 21, DecimalSI), "1000E"},
+		{decQuantity(-1, -3, DecimalSI), "-1m"},
+		{decQuantity(-1024, 0, BinarySI), "-1Ki"},
+	}
+
+	for _, c := range cases {
+		if s := c.input.String(); s != c.expected {
+			t.Errorf("%#v: expected %s, got %s", c.input, c.expected, s)
+		}
+	}
+}
+
+func TestQuantityCanonical(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected string
+	}{
+		{"1000", "1k"},
+		{"1024Ki", "1Mi"},
+		{"1.5Gi", "1536Mi"},
+		{"0.5", "500m"},
+		{"0.1", "100m"},
+		{"1E3", "1e3"},
+		{"1.5e3", "1500"},
+		{"0.0000000001", "1n"},
+		{"-0.0000000001", "-1n"},
+		{"0.5Ki", "512"},
+		{"1536", "1536"},
+		{"100m", "100m"},
+		{"0.0", "0"},
+		{"-0", "0"},
+		{"12345678901234567890", "12345678901234567900"},
+	}
+
+	for _, c := range cases {
+		q := MustParse(c.input)
+		if s := q.String(); s != c.expected {
+			t.Errorf("%s: expected %s, got %s", c.input, c.expected, s)
+		}
+		again := MustParse(q.String())
+		if again.Cmp(q) != 0 {
+			t.Errorf("%s: %s doesn't parse to the same value", c.input, q.String())
+		}
+	}
+}
+
+func TestQuantityCmp(t *testing.T) {
+	cases := []struct {
+		x, y     string
+		expected int
+	}{
+		{"0", "0", 0},
+		{"1", "1000m", 0},
+		{"1Ki", "1024", 0},
+		{"1k", "1Ki", -1},
+		{"100m", "0.1", 0},
+		{"-1", "1", -1},
+		{"1n", "0", 1},
+		{"1E", "1n", 1},
+		{"-1E", "1n", -1},
+		{"1e100", "1", 1},
+		{"-1e100", "1", -1},
+		{"1", "1e100", -1},
+		{"1", "-1e100", 1},
+	}
+
+	for _, c := range cases {
+		if r := MustParse(c.x).Cmp(MustParse(c.y)); r != c.expected {
+			t.Errorf("%s cmp %s: expected %d, got %d", c.x, c.y, c.expected, r)
+		}
+	}
+
+	one, same, other := MustParse("1Gi"), MustParse("1073741824"), MustParse("1G")
+	if !one.Equal(&same) || one.Equal(&other) {
+		t.Errorf("expected 1Gi to be equal to 1073741824 only")
+	}
+	if one.Equal(nil) || !(*Quantity)(nil).Equal(nil) {
+		t.Errorf("expected nil quantities to be equal to each other only")
+	}
+
+	if MustParse("2k").CmpInt64(2000) != 0 {
+		t.Errorf("expected 2k to be equal to 2000")
+	}
+	if MustParse("1.5").CmpInt64(1) != 1 {
+		t.Errorf("expected 1.5 to be greater than 1")
+	}
+}
+
+func TestQuantityArithmetic(t *testing.T) {
+	cases := []struct {
+		x, y       string
+		sum, delta string
+	}{
+		{"1", "1", "2", "0"},
+		{"1Gi", "512Mi", "1536Mi", "512Mi"},
+		{"100m", "1", "1100m", "-900m"},
+		{"1k", "1n", "1000000000001n", "999999999999n"},
+		{"0", "5Ki", "5Ki", "-5Ki"},
+		{"-1", "1", "0", "-2"},
+	}
+
+	for _, c := range cases {
+		sum := MustParse(c.x)
+		sum.Add(MustParse(c.y))
+		if sum.String() != c.sum {
+			t.Errorf("%s + %s: expected %s, got %s", c.x, c.y, c.sum, sum.String())
+		}
+
+		delta := MustParse(c.x)
+		delta.Sub(MustParse(c.y))
+		if delta.String() != c.delta {
+			t.Errorf("%s - %s: expected %s, got %s", c.x, c.y, c.delta, delta.String())
+		}
+	}
+
+	// precision is lost instead of overflowing
+	huge := MustParse("9E")
+	huge.Add(MustParse("9E"))
+	if huge.String() != "18E" {
+		t.Errorf("expected 18E, got %s", huge.String())
+	}
+	large := MustParse("1e30")
+	large.Add(MustParse("1"))
+	if large.Cmp(MustParse("1e30")) != 0 {
+		t.Errorf("expected 1e30, got %s", large.String())
+	}
+
+	q := MustParse("1.5")
+	if !q.Mul(3) || q.String() != "4500m" {
+		t.Errorf("expected 4500m, got %s", q.String())
+	}
+	q.Neg()
+	if q.String() != "-4500m" {
+		t.Errorf("expected -4500m, got %s", q.String())
+	}
+}
+
+func TestQuantityValues(t *testing.T) {
+	cases := []struct {
+		input string
+		value int64
+		milli int64
+		kilo  int64
+	}{
+		{"0", 0, 0, 0},
+		{"1", 1, 1000, 1},
+		{"1.1", 2, 1100, 1},
+		{"-1.1", -2, -1100, -1},
+		{"100m", 1, 100, 1},
+		{"1n", 1, 1, 1},
+		{"1.5k", 1500, 1500000, 2},
+		{"1Ki", 1024, 1024000, 2},
+		{"1E", 1000000000000000000, 9223372036854775807, 1000000000000000},
+	}
+
+	for _, c := range cases {
+		q := MustParse(c.input)
+		if v := q.Value(); v != c.value {
+			t.Errorf("%s: expected value %d, got %d", c.input, c.value, v)
+		}
+		if v := q.MilliValue(); v != c.milli {
+			t.Errorf("%s: expected milli value %d, got %d", c.input, c.milli, v)
+		}
+		if v := q.ScaledValue(Kilo); v != c.kilo {
+			t.Errorf("%s: expected kilo value %d, got %d", c.input, c.kilo, v)
+		}
+	}
+
+	if v, ok := MustParse("1Mi").AsInt64(); !ok || v != 1024*1024 {
+		t.Errorf("expected 1Mi to be %d, got %d", 1024*1024, v)
+	}
+	if _, ok := MustParse("100m").AsInt64(); ok {
+		t.Errorf("expected 100m not to be an integer")
+	}
+	if f := MustParse("1.5").AsApproximateFloat64(); f != 1.5 {
+		t.Errorf("expected 1.5, got %f", f)
+	}
+	if q := NewMilliQuantity(1500, DecimalSI); q.String() != "1500m" {
+		t.Errorf("expected 1500m, got %s", q.String())
+	}
+	if q := NewQuantity(2048, BinarySI); q.String() != "2Ki" {
+		t.Errorf("expected 2Ki, got %s", q.String())
+	}
+}
+
+func TestQuantityJSON(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected string
+	}{
+		{"\"1Gi\"", "\"1Gi\""},
+		{"\"1000\"", "\"1k\""},
+		{"\"0.5\"", "\"500m\""},
+		{"1024", "\"1024\""},
+		{"1.5", "\"1500m\""},
+		{"null", "\"0\""},
+	}
+
+	for _, c := range cases {
+		var q Quantity
+		if err := json.Unmarshal([]byte(c.input), &q); err != nil {
+			t.Errorf("%s: unexpected error: %v", c.input, err)
+			continue
+		}
+		data, err := json.Marshal(q)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.input, err)
+			continue
+		}
+		if string(data) != c.expected {
+			t.Errorf("%s: expected %s, got %s", c.input, c.expected, string(data))
+		}
+	}
+
+	for _, input := range []string{"\"1.1.1\"", "true", "{}"} {
+		var q Quantity
+		if err := json.Unmarshal([]byte(input), &q); err == nil {
+			t.Errorf("%s: expected an error", input)
+		}
+	}
+}
+`
+
+// Writes the Quantity type, which is not generated by swagger, inside of the
+// package of the Quantity definition, together with its tests. The upstream
+// type relies on math/big, which cannot be used with TinyGo
+func GenerateQuantity(project Project, plan *RefactoringPlan) error {
+	for _, def := range plan.Definitions {
+		if !def.IsQuantity() {
+			continue
+		}
+		if override, _ := plan.Interfaces.Override(project.GitRepo, def.PackageName, def.TypeName); override.Import != "" {
+			// the type is provided by another package
+			continue
+		}
+
+		pkgDir := filepath.Join(project.Root, def.PackageName)
+		if err := os.MkdirAll(pkgDir, 0777); err != nil {
+			return errors.Wrapf(err, "cannot create dir %s", pkgDir)
+		}
+
+		files := map[string]string{
+			QUANTITY_FILE_NAME:      QUANTITY_TEMPLATE,
+			QUANTITY_TEST_FILE_NAME: QUANTITY_TEST_TEMPLATE,
+		}
+		for name, text := range files {
+			contents, err := renderQuantity(def, text)
+			if err != nil {
+				return errors.Wrapf(err, "cannot render %s of %s", name, def.ID)
+			}
+
+			fileName := filepath.Join(pkgDir, name)
+			if err := os.WriteFile(fileName, contents, 0644); err != nil {
+				return errors.Wrapf(err, "cannot write %s", fileName)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Returns the contents of one of the files of the Quantity type
+func renderQuantity(def *swagger_helpers.Definition, text string) ([]byte, error) {
+	quantityTemplate, err := template.New("quantity").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	templateData := struct {
+		Package string
+	}{
+		Package: filepath.Base(def.PackageName),
+	}
+
+	var buf bytes.Buffer
+	if err := quantityTemplate.Execute(&buf, templateData); err != nil {
+		return nil, err
+	}
+
+	contents, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("cannot format generated code: %v", err)
+	}
+
+	return contents, nil
+}
@@ -0,0 +1,31 @@
+package split
+
+import (
+	"strings"
+	"testing"
+
+	openapi_spec "github.com/go-openapi/spec"
+)
+
+// The behaviour of the generated type is tested by the tests generated next
+// to it and by the Quantity tests kept inside of testdata, which run against
+// the generated code
+func TestRenderQuantity(t *testing.T) {
+	plan := newRegistryTestPlan(t, openapi_spec.Definitions{
+		"io.k8s.apimachinery.pkg.api.resource.Quantity": stringProperty(""),
+	})
+	def := plan.Definitions["io.k8s.apimachinery.pkg.api.resource.Quantity"]
+
+	contents, err := renderQuantity(def, QUANTITY_TEMPLATE)
+	if err != nil {
+		t.Fatalf("cannot render Quantity: %v", err)
+	}
+
+	code := string(contents)
+	if !strings.Contains(code, "type Quantity struct {") {
+		t.Errorf("cannot find Quantity inside of generated code:\n%s", code)
+	}
+	if strings.Contains(code, `"math/big"`) {
+		t.Error("Quantity must not depend on math/big")
+	}
+}
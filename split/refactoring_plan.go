@@ -2,6 +2,7 @@ package split
 
 import (
 	"fmt"
+	"sort"
 
 	openapi_spec "github.com/go-openapi/spec"
 	"github.com/heimdalr/dag"
@@ -12,13 +13,28 @@ import (
 // Holds information about how the big swagger file is going to be splitted
 type RefactoringPlan struct {
 	Packages          map[string]swagger_helpers.Package
+	Definitions       map[string]*swagger_helpers.Definition
 	Interfaces        swagger_helpers.InterfaceRegistry
 	SwaggerVersion    string
 	KubernetesVersion string
+
+	// Names the packages, types and imports of the generated code, it must
+	// be used by all the generators
+	Naming swagger_helpers.Naming
+
+	options swagger_helpers.NamingOptions
+
+	// rules moving the definitions of the merged packages, they take
+	// precedence over the ones of the options
+	mergeRules []swagger_helpers.PackageMappingRule
+
+	// the overrides applied to the plan, indexed by definition ID
+	overrides swagger_helpers.TypeOverrides
 }
 
-func NewRefactoringPlan(swagger *openapi_spec.Swagger) (*RefactoringPlan, error) {
+func NewRefactoringPlan(swagger *openapi_spec.Swagger, options swagger_helpers.NamingOptions) (*RefactoringPlan, error) {
 	packages := make(map[string]swagger_helpers.Package)
+	definitions := make(map[string]*swagger_helpers.Definition)
 	interfaces := swagger_helpers.NewInterfaceRegistry()
 
 	kubernetesVersion := "undefined"
@@ -26,20 +42,44 @@ func NewRefactoringPlan(swagger *openapi_spec.Swagger) (*RefactoringPlan, error)
 		kubernetesVersion = swagger.Info.Version
 	}
 
+	ids := []string{}
+	for id := range swagger.Definitions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	naming, err := swagger_helpers.NewNaming(options, ids)
+	if err != nil {
+		return nil, err
+	}
+
 	for id, definition := range swagger.Definitions {
-		newDefinitionRefactoringPlan, err := swagger_helpers.NewDefinition(definition, id)
+		newDefinitionRefactoringPlan, err := swagger_helpers.NewDefinition(definition, id, naming)
 		if err != nil {
 			return nil, errors.Wrapf(err, "cannot parse definition with id %s", id)
 		}
 
-		if ((len(definition.Type) == 1 && definition.Type[0] == "object") || (len(definition.Type) == 0)) &&
-			len(definition.Properties) == 0 &&
-			definition.AdditionalProperties == nil {
-
-			// this is a go interface
+		if newDefinitionRefactoringPlan.IsCustomType() {
+			// the type is written by the generator, Quantity declares an
+			// Equal method comparing the amounts instead of their format
+			interfaces.RegisterOverride(
+				newDefinitionRefactoringPlan.PackageName,
+				newDefinitionRefactoringPlan.TypeName,
+				swagger_helpers.TypeOverride{
+					Type:  newDefinitionRefactoringPlan.TypeName,
+					Equal: newDefinitionRefactoringPlan.IsQuantity(),
+				})
+		} else if len(definition.Properties) == 0 &&
+			(swagger_helpers.IsOpaqueSchema(&definition) ||
+				(((len(definition.Type) == 1 && definition.Type[0] == "object") || (len(definition.Type) == 0)) &&
+					definition.AdditionalProperties == nil)) {
+
+			// this is a go interface, like the definitions whose only Go
+			// type would be `interface{}`
 			interfaces.RegisterInterface(newDefinitionRefactoringPlan.PackageName, newDefinitionRefactoringPlan.TypeName)
 		}
 
+		definitions[id] = newDefinitionRefactoringPlan
+
 		pkg, pkgKnown := packages[newDefinitionRefactoringPlan.PackageName]
 		if !pkgKnown {
 			pkg = swagger_helpers.NewPackage(newDefinitionRefactoringPlan.PackageName)
@@ -53,12 +93,72 @@ func NewRefactoringPlan(swagger *openapi_spec.Swagger) (*RefactoringPlan, error)
 		SwaggerVersion:    swagger.Swagger,
 		KubernetesVersion: kubernetesVersion,
 		Packages:          packages,
+		Definitions:       definitions,
 		Interfaces:        interfaces,
+		Naming:            naming,
+		options:           options,
 	}, nil
 }
 
+// Replaces the Go types of the given definitions. The overrides take
+// precedence over the built-in rules, e.g. an object without properties is
+// not turned into a `easyjson.RawMessage` when it's overridden
+func (r *RefactoringPlan) ApplyTypeOverrides(overrides swagger_helpers.TypeOverrides) error {
+	for _, id := range overrides.IDs() {
+		override := overrides[id]
+		if err := override.Validate(); err != nil {
+			return errors.Wrapf(err, "invalid override of %s", id)
+		}
+
+		definition, found := r.Definitions[id]
+		if !found {
+			return fmt.Errorf("cannot override %s: unknown definition", id)
+		}
+		r.Interfaces.RegisterOverride(definition.PackageName, definition.TypeName, override)
+
+		if r.overrides == nil {
+			r.overrides = make(swagger_helpers.TypeOverrides)
+		}
+		r.overrides[id] = override
+	}
+
+	return nil
+}
+
+// Ensures the packages of the plan, together with the external packages
+// defining the overridden types, are imported using distinct aliases
+func (r *RefactoringPlan) CheckImportAliases() error {
+	packages := r.Interfaces.OverrideImports()
+	for pkgName := range r.Packages {
+		packages = append(packages, pkgName)
+	}
+
+	return r.Naming.CheckImportAliases(packages)
+}
+
+// Finds a definition either by its original ID (e.g. `io.k8s.api.core.v1.Pod`)
+// or by its Go type (e.g. `api/core/v1.Pod`)
+func (r *RefactoringPlan) LookupDefinition(name string) (*swagger_helpers.Definition, error) {
+	if definition, found := r.Definitions[name]; found {
+		return definition, nil
+	}
+
+	for _, definition := range r.Definitions {
+		if fmt.Sprintf("%s.%s", definition.PackageName, definition.TypeName) == name {
+			return definition, nil
+		}
+	}
+
+	return nil, fmt.Errorf("cannot find definition %s", name)
+}
+
 func (r *RefactoringPlan) DependenciesGraph() (*dag.DAG, error) {
+	if cycles := r.PackageCycles(); len(cycles) > 0 {
+		return nil, newCycleError(cycles)
+	}
+
 	dependenciesGraph := dag.NewDAG()
+	references := r.packageReferences()
 
 	for pkgName, pkg := range r.Packages {
 		if _, err := dependenciesGraph.GetVertex(pkgName); err != nil {
@@ -70,15 +170,16 @@ func (r *RefactoringPlan) DependenciesGraph() (*dag.DAG, error) {
 		}
 
 		for depName := range pkg.Dependencies.Iterator().C {
-			name := depName.(string)
+			if _, found := r.Packages[depName.(string)]; !found {
+				return nil, fmt.Errorf("unsolved dependency: cannot find package %s inside of list of known packages", depName)
+			}
+		}
 
+		// the references to overridden types of other modules are not
+		// dependencies
+		for _, name := range sortedDependencies(references, pkgName) {
 			// ensure the dependency is known by the DAG
 			if _, err := dependenciesGraph.GetVertex(name); err != nil {
-				_, found := r.Packages[name]
-				if !found {
-					return nil, fmt.Errorf("unsolved dependency: cannot find package %s inside of list of known packages", name)
-				}
-
 				// the package is not yet known by the DAG
 				err := dependenciesGraph.AddVertexByID(name, name)
 				if err != nil {
@@ -87,7 +188,7 @@ func (r *RefactoringPlan) DependenciesGraph() (*dag.DAG, error) {
 			}
 
 			// register the dependency relation
-			// this namespace depends on `depName`
+			// this namespace depends on `name`
 			if err := dependenciesGraph.AddEdge(name, pkgName); err != nil {
 				return nil, errors.Wrapf(err,
 					"Cannot register the dependency relation that %s has against %s",
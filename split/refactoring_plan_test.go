@@ -1,9 +1,11 @@
 package split
 
 import (
+	"strings"
 	"testing"
 
 	openapi_spec "github.com/go-openapi/spec"
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
 )
 
 func TestNewRefactoringPlan(t *testing.T) {
@@ -46,7 +48,7 @@ func TestNewRefactoringPlan(t *testing.T) {
 		},
 	}
 
-	plan, err := NewRefactoringPlan(&swagger)
+	plan, err := NewRefactoringPlan(&swagger, swagger_helpers.NamingOptions{})
 	if err != nil {
 		t.Errorf("Cannot create refactoring plan: %v", err)
 	}
@@ -73,3 +75,91 @@ func TestNewRefactoringPlan(t *testing.T) {
 		t.Errorf("wrong number of packages found inside of the plan: %d", len(plan.Packages))
 	}
 }
+
+func TestCheckImportAliases(t *testing.T) {
+	swagger := openapi_spec.Swagger{}
+	swagger.Definitions = openapi_spec.Definitions{
+		"io.k8s.api.storage.v1.StorageClass":        {SchemaProps: openapi_spec.SchemaProps{Properties: map[string]openapi_spec.Schema{"provisioner": stringProperty("")}}},
+		"io.k8s.apimachinery.pkg.apis.meta.v1.Time": stringProperty("date-time"),
+	}
+	overrides := swagger_helpers.TypeOverrides{
+		"io.k8s.apimachinery.pkg.apis.meta.v1.Time": {Import: "github.com/example/storage/v1", Type: "Time"},
+	}
+
+	newPlan := func(strategy string) *RefactoringPlan {
+		plan, err := NewRefactoringPlan(&swagger, swagger_helpers.NamingOptions{
+			ImportAliases: swagger_helpers.ImportAliasStrategy{Name: strategy},
+		})
+		if err != nil {
+			t.Fatalf("Cannot create refactoring plan: %v", err)
+		}
+		if err := plan.ApplyTypeOverrides(overrides); err != nil {
+			t.Fatalf("cannot apply overrides: %v", err)
+		}
+		return plan
+	}
+
+	if err := newPlan(swagger_helpers.FULL_ALIAS_STRATEGY).CheckImportAliases(); err != nil {
+		t.Errorf("full aliases must not collide: %v", err)
+	}
+
+	err := newPlan(swagger_helpers.SHORT_ALIAS_STRATEGY).CheckImportAliases()
+	if err == nil || !strings.Contains(err.Error(), "storagev1: api/storage/v1, github.com/example/storage/v1") {
+		t.Errorf("expected the collision of the storagev1 alias to be reported, got %v", err)
+	}
+}
+
+func TestNewRefactoringPlanInvalidNamingOptions(t *testing.T) {
+	swagger := openapi_spec.Swagger{}
+	_, err := NewRefactoringPlan(&swagger, swagger_helpers.NamingOptions{
+		ImportAliases: swagger_helpers.ImportAliasStrategy{Name: "tiny"},
+	})
+	if err == nil || !strings.Contains(err.Error(), `unknown import alias strategy "tiny"`) {
+		t.Errorf("expected the invalid strategy to be reported, got %v", err)
+	}
+}
+
+func TestNewRefactoringPlanDuplicateTypes(t *testing.T) {
+	options := swagger_helpers.NamingOptions{
+		PackageMappingRules: []swagger_helpers.PackageMappingRule{
+			{Regexp: `(com|org)\.example\.(.*)`, Package: "example.$2"},
+		},
+	}
+
+	swagger := openapi_spec.Swagger{}
+	swagger.Definitions = openapi_spec.Definitions{
+		"com.example.v1.Widget": objectSchema(map[string]openapi_spec.Schema{"name": stringProperty("")}),
+		"org.example.v1.Widget": objectSchema(map[string]openapi_spec.Schema{"size": stringProperty("")}),
+		"com.example.v1.Gadget": objectSchema(map[string]openapi_spec.Schema{"widget": refProperty("org.example.v1.Widget")}),
+	}
+
+	_, err := NewRefactoringPlan(&swagger, options)
+	if err == nil || !strings.Contains(err.Error(), "example/v1.Widget: com.example.v1.Widget, org.example.v1.Widget") {
+		t.Fatalf("expected the duplicated type to be reported, got %v", err)
+	}
+
+	options.DuplicateTypeStrategy = swagger_helpers.PREFIX_DUPLICATE_TYPE_STRATEGY
+	plan, err := NewRefactoringPlan(&swagger, options)
+	if err != nil {
+		t.Fatalf("Cannot create refactoring plan: %v", err)
+	}
+	if plan.Definitions["org.example.v1.Widget"].TypeName != "OrgWidget" {
+		t.Errorf("wrong type name: %s", plan.Definitions["org.example.v1.Widget"].TypeName)
+	}
+
+	swaggerFiles, err := plan.RenderNewSwaggerFiles("github.com/kubewarden/k8s-objects")
+	if err != nil {
+		t.Fatalf("cannot render swagger files: %v", err)
+	}
+	for _, snippet := range []string{`"ComWidget":`, `"OrgWidget":`, `"$ref":"#/definitions/OrgWidget"`} {
+		if !strings.Contains(swaggerFiles["example/v1"], snippet) {
+			t.Errorf("cannot find %s inside of:\n%s", snippet, swaggerFiles["example/v1"])
+		}
+	}
+
+	// the options of a plan must not leak into the ones computed later
+	options.DuplicateTypeStrategy = ""
+	if _, err := NewRefactoringPlan(&swagger, options); err == nil {
+		t.Error("expected the duplicated type to be reported again")
+	}
+}
@@ -0,0 +1,270 @@
+package split
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/go-openapi/swag"
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
+	"github.com/pkg/errors"
+)
+
+// Package, relative to the project root, that maps every kind to its Go type
+const REGISTRY_PACKAGE = "registry"
+
+const REGISTRY_FILE_NAME = "registry.go"
+
+const REGISTRY_TEMPLATE = `// Code generated by k8s-objects-generator. DO NOT EDIT.
+
+package registry
+
+import (
+	"fmt"
+
+	"github.com/mailru/easyjson"
+	"github.com/mailru/easyjson/jlexer"
+
+	schema "{{ .SchemaImport }}"
+{{- range .Imports }}
+	{{ .Alias }} "{{ .Path }}"
+{{- end }}
+)
+
+// Object is implemented by all the kinds known by the registry
+type Object interface {
+	easyjson.Marshaler
+	easyjson.Unmarshaler
+	GroupVersionKind() schema.GroupVersionKind
+	SetGroupVersionKind(gvk schema.GroupVersionKind)
+}
+
+var constructors = map[schema.GroupVersionKind]func() Object{
+{{- range .Kinds }}
+	{Group: {{ printf "%q" .GVK.Group }}, Version: {{ printf "%q" .GVK.Version }}, Kind: {{ printf "%q" .GVK.Kind }}}: func() Object { return &{{ .Alias }}.{{ .TypeName }}{} },
+{{- end }}
+}
+
+// Register associates the constructor to the given kind, replacing the
+// previous one
+func Register(gvk schema.GroupVersionKind, constructor func() Object) {
+	constructors[gvk] = constructor
+}
+
+// New returns an empty object of the given kind, with its apiVersion and kind
+// fields set
+func New(gvk schema.GroupVersionKind) (Object, error) {
+	constructor, found := constructors[gvk]
+	if !found {
+		return nil, fmt.Errorf("unknown kind %s", gvk)
+	}
+
+	obj := constructor()
+	obj.SetGroupVersionKind(gvk)
+	return obj, nil
+}
+
+// Decode unmarshals the raw JSON object into the Go type of its kind, which is
+// identified by the apiVersion and kind fields
+func Decode(raw []byte) (Object, schema.GroupVersionKind, error) {
+	meta := typeMeta{}
+	if err := easyjson.Unmarshal(raw, &meta); err != nil {
+		return nil, schema.GroupVersionKind{}, fmt.Errorf("cannot read apiVersion and kind: %v", err)
+	}
+	if meta.APIVersion == "" || meta.Kind == "" {
+		return nil, schema.GroupVersionKind{}, fmt.Errorf("apiVersion and kind must be set")
+	}
+
+	gvk := schema.FromAPIVersionAndKind(meta.APIVersion, meta.Kind)
+	obj, err := New(gvk)
+	if err != nil {
+		return nil, gvk, err
+	}
+
+	if err := easyjson.Unmarshal(raw, obj); err != nil {
+		return nil, gvk, fmt.Errorf("cannot decode %s: %v", gvk, err)
+	}
+
+	return obj, gvk, nil
+}
+
+// typeMeta holds the fields identifying the kind of an object, all the other
+// fields are skipped while decoding
+type typeMeta struct {
+	APIVersion string
+	Kind       string
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (t *typeMeta) UnmarshalEasyJSON(in *jlexer.Lexer) {
+	if in.IsNull() {
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "apiVersion":
+			t.APIVersion = in.String()
+		case "kind":
+			t.Kind = in.String()
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+}
+`
+
+type registryImport struct {
+	Alias string
+	Path  string
+}
+
+type registryKind struct {
+	GVK      swagger_helpers.GroupVersionKind
+	Alias    string
+	TypeName string
+}
+
+// Writes the `registry` package, which maps every GroupVersionKind to the Go
+// type that implements it
+func GenerateRegistry(project Project, plan *RefactoringPlan) error {
+	contents, err := renderRegistry(plan, project.GitRepo)
+	if err != nil {
+		return errors.Wrap(err, "cannot render registry package")
+	}
+
+	registryDir := filepath.Join(project.Root, REGISTRY_PACKAGE)
+	if err := os.MkdirAll(registryDir, 0777); err != nil {
+		return errors.Wrapf(err, "cannot create dir %s", registryDir)
+	}
+
+	fileName := filepath.Join(registryDir, REGISTRY_FILE_NAME)
+	if err := os.WriteFile(fileName, contents, 0644); err != nil {
+		return errors.Wrapf(err, "cannot write %s", fileName)
+	}
+
+	return nil
+}
+
+// Returns the definition registered for each kind. When more definitions
+// claim the same kind, a warning is logged and the one claiming the fewest
+// kinds wins: generic types, like the ones used by the aggregated APIs,
+// claim all the kinds of their group. The definition ID breaks the ties
+func registryOwners(plan *RefactoringPlan, gitRepo string) map[swagger_helpers.GroupVersionKind]*swagger_helpers.Definition {
+	claims := make(map[swagger_helpers.GroupVersionKind][]*swagger_helpers.Definition)
+	for _, def := range plan.Definitions {
+		if !isKind(def, gitRepo, &plan.Interfaces) {
+			continue
+		}
+		for _, gvk := range def.GroupVersionKinds() {
+			claims[gvk] = append(claims[gvk], def)
+		}
+	}
+
+	owners := make(map[swagger_helpers.GroupVersionKind]*swagger_helpers.Definition)
+	for gvk, defs := range claims {
+		sort.Slice(defs, func(i, j int) bool {
+			a, b := len(defs[i].GroupVersionKinds()), len(defs[j].GroupVersionKinds())
+			if a != b {
+				return a < b
+			}
+			return defs[i].ID < defs[j].ID
+		})
+		owners[gvk] = defs[0]
+
+		if len(defs) > 1 {
+			ids := []string{}
+			for _, def := range defs {
+				ids = append(ids, def.ID)
+			}
+			log.Printf("Warning: kind %s is claimed by %s, registering %s",
+				gvk, strings.Join(ids, ", "), defs[0].ID)
+		}
+	}
+
+	return owners
+}
+
+func renderRegistry(plan *RefactoringPlan, gitRepo string) ([]byte, error) {
+	schemaPackage, err := plan.Naming.SchemaPackage()
+	if err != nil {
+		return nil, err
+	}
+
+	imports := []registryImport{}
+	kinds := []registryKind{}
+
+	importedPackages := make(map[string]bool)
+	for gvk, def := range registryOwners(plan, gitRepo) {
+		kinds = append(kinds, registryKind{
+			GVK:      gvk,
+			Alias:    plan.Naming.PackageAlias(def.PackageName),
+			TypeName: swag.ToGoName(def.TypeName),
+		})
+		importedPackages[def.PackageName] = true
+	}
+
+	for pkgName := range importedPackages {
+		imports = append(imports, registryImport{
+			Alias: plan.Naming.PackageAlias(pkgName),
+			Path:  fmt.Sprintf("%s/%s", gitRepo, pkgName),
+		})
+	}
+
+	sort.Slice(imports, func(i, j int) bool {
+		return imports[i].Path < imports[j].Path
+	})
+	sort.Slice(kinds, func(i, j int) bool {
+		a, b := kinds[i].GVK, kinds[j].GVK
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		if a.Version != b.Version {
+			return a.Version < b.Version
+		}
+		return a.Kind < b.Kind
+	})
+
+	registryTemplate, err := template.New("registry").Parse(REGISTRY_TEMPLATE)
+	if err != nil {
+		return nil, err
+	}
+
+	templateData := struct {
+		SchemaImport string
+		Imports      []registryImport
+		Kinds        []registryKind
+	}{
+		SchemaImport: fmt.Sprintf("%s/%s", gitRepo, schemaPackage),
+		Imports:      imports,
+		Kinds:        kinds,
+	}
+
+	var buf bytes.Buffer
+	if err := registryTemplate.Execute(&buf, templateData); err != nil {
+		return nil, err
+	}
+
+	contents, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("cannot format generated code: %v", err)
+	}
+
+	return contents, nil
+}
@@ -0,0 +1,96 @@
+package split
+
+import (
+	"strings"
+	"testing"
+
+	openapi_spec "github.com/go-openapi/spec"
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
+)
+
+func newRegistryTestPlan(t *testing.T, definitions openapi_spec.Definitions) *RefactoringPlan {
+	swagger := openapi_spec.Swagger{}
+	swagger.Definitions = definitions
+
+	plan, err := NewRefactoringPlan(&swagger, swagger_helpers.NamingOptions{})
+	if err != nil {
+		t.Fatalf("Cannot create refactoring plan: %v", err)
+	}
+	return plan
+}
+
+func kindSchema(gvks ...map[string]interface{}) openapi_spec.Schema {
+	return openapi_spec.Schema{
+		SchemaProps: openapi_spec.SchemaProps{
+			Properties: map[string]openapi_spec.Schema{
+				"apiVersion": stringProperty(""),
+				"kind":       stringProperty(""),
+			},
+		},
+		VendorExtensible: openapi_spec.VendorExtensible{
+			Extensions: gvkExtension(gvks...),
+		},
+	}
+}
+
+func TestRenderRegistry(t *testing.T) {
+	gitRepo := "github.com/kubewarden/k8s-objects"
+	plan := newRegistryTestPlan(t, openapi_spec.Definitions{
+		"io.k8s.api.apps.v1.Deployment": kindSchema(
+			map[string]interface{}{"group": "apps", "version": "v1", "kind": "Deployment"}),
+		"io.k8s.api.core.v1.Pod": kindSchema(
+			map[string]interface{}{"group": "", "version": "v1", "kind": "Pod"}),
+		"io.k8s.api.core.v1.PodSpec": {
+			SchemaProps: openapi_spec.SchemaProps{
+				Properties: map[string]openapi_spec.Schema{
+					"hostname": stringProperty(""),
+				},
+			},
+		},
+	})
+
+	contents, err := renderRegistry(plan, gitRepo)
+	if err != nil {
+		t.Fatalf("cannot render registry: %v", err)
+	}
+
+	code := string(contents)
+	if !strings.Contains(code, `{Group: "apps", Version: "v1", Kind: "Deployment"}: func() Object { return &api_apps_v1.Deployment{} },`) {
+		t.Errorf("kinds must be sorted by group:\n%s", code)
+	}
+	if strings.Contains(code, "PodSpec") {
+		t.Errorf("only kinds must be registered:\n%s", code)
+	}
+}
+
+func TestRenderRegistryDuplicatedKind(t *testing.T) {
+	pod := map[string]interface{}{"group": "", "version": "v1", "kind": "Pod"}
+	service := map[string]interface{}{"group": "", "version": "v1", "kind": "Service"}
+	plan := newRegistryTestPlan(t, openapi_spec.Definitions{
+		"io.k8s.api.core.v1.Pod":      kindSchema(pod),
+		"io.k8s.api.legacy.v1.Pod":    kindSchema(pod),
+		"io.k8s.api.aaa.v1.Generic":   kindSchema(pod, service),
+		"io.k8s.api.legacy.v1.Legacy": kindSchema(map[string]interface{}{"group": "legacy", "version": "v1", "kind": "Legacy"}),
+	})
+
+	contents, err := renderRegistry(plan, "github.com/kubewarden/k8s-objects")
+	if err != nil {
+		t.Fatalf("cannot render registry: %v", err)
+	}
+
+	// the definition claiming the fewest kinds wins, then the lowest ID. The
+	// spaces aligning the entries are ignored
+	code := strings.Join(strings.Fields(string(contents)), " ")
+	for _, snippet := range []string{
+		`{Group: "", Version: "v1", Kind: "Pod"}: func() Object { return &api_core_v1.Pod{} },`,
+		`{Group: "", Version: "v1", Kind: "Service"}: func() Object { return &api_aaa_v1.Generic{} },`,
+		`{Group: "legacy", Version: "v1", Kind: "Legacy"}: func() Object { return &api_legacy_v1.Legacy{} },`,
+	} {
+		if !strings.Contains(code, snippet) {
+			t.Errorf("cannot find %s inside of:\n%s", snippet, code)
+		}
+	}
+	if strings.Contains(code, "api_legacy_v1.Pod") {
+		t.Errorf("a kind must be registered only once:\n%s", code)
+	}
+}
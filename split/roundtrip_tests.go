@@ -0,0 +1,174 @@
+package split
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/template"
+
+	"github.com/go-openapi/swag"
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
+	"github.com/pkg/errors"
+)
+
+const ROUNDTRIP_TEST_FILE_NAME = "roundtrip_test.go"
+
+const ROUNDTRIP_TEST_TEMPLATE = `// Code generated by k8s-objects-generator. DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/mailru/easyjson"
+)
+
+type roundTripObject interface {
+	easyjson.Marshaler
+	easyjson.Unmarshaler
+}
+
+// Ensures the easyjson helpers do not lose any field defined by the schema
+func TestEasyJSONRoundTrip(t *testing.T) {
+	cases := []struct {
+		typeName string
+		obj      roundTripObject
+		data     string
+	}{
+{{- range .Cases }}
+		{
+			typeName: {{ printf "%q" .TypeName }},
+			obj:      &{{ .TypeName }}{},
+			data:     {{ printf "%q" .Data }},
+		},
+{{- end }}
+	}
+
+	for _, testCase := range cases {
+		if err := easyjson.Unmarshal([]byte(testCase.data), testCase.obj); err != nil {
+			t.Errorf("%s: cannot unmarshal sample document: %v", testCase.typeName, err)
+			continue
+		}
+
+		data, err := easyjson.Marshal(testCase.obj)
+		if err != nil {
+			t.Errorf("%s: cannot marshal object: %v", testCase.typeName, err)
+			continue
+		}
+
+		var expected, actual interface{}
+		if err := json.Unmarshal([]byte(testCase.data), &expected); err != nil {
+			t.Errorf("%s: cannot decode sample document: %v", testCase.typeName, err)
+			continue
+		}
+		if err := json.Unmarshal(data, &actual); err != nil {
+			t.Errorf("%s: cannot decode marshaled object: %v", testCase.typeName, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("%s: round trip altered the document\nexpected: %s\ngot:      %s",
+				testCase.typeName, testCase.data, string(data))
+		}
+	}
+}
+`
+
+type roundTripTestCase struct {
+	TypeName string
+	Data     string
+}
+
+// Writes, for each package, a test file that ensures all the generated
+// types can be unmarshaled and marshaled back by easyjson without losing
+// any data
+func GenerateRoundTripTests(project Project, plan *RefactoringPlan) error {
+	builder := swagger_helpers.NewSampleBuilder(plan.Definitions, swagger_helpers.SampleOptions{})
+
+	for pkgName, pkg := range plan.Packages {
+		contents, err := renderRoundTripTests(pkg, &builder, project.GitRepo, &plan.Interfaces)
+		if err != nil {
+			return errors.Wrapf(err, "cannot render round trip tests of package %s", pkgName)
+		}
+		if contents == nil {
+			continue
+		}
+
+		fileName := filepath.Join(project.Root, pkgName, ROUNDTRIP_TEST_FILE_NAME)
+		if err := os.WriteFile(fileName, contents, 0644); err != nil {
+			return errors.Wrapf(err, "cannot write %s", fileName)
+		}
+	}
+
+	return nil
+}
+
+// Returns the contents of the round trip test file of the given package,
+// nil when the package doesn't define any struct
+func renderRoundTripTests(pkg swagger_helpers.Package, builder *swagger_helpers.SampleBuilder, gitRepo string, interfaces *swagger_helpers.InterfaceRegistry) ([]byte, error) {
+	cases := []roundTripTestCase{}
+
+	for _, def := range pkg.Definitions {
+		// only structs have easyjson helpers
+		if len(def.SwaggerDefinition.Properties) == 0 || interfaces.IsInterface(gitRepo, def.PackageName, def.TypeName) {
+			continue
+		}
+		if override, found := interfaces.Override(gitRepo, def.PackageName, def.TypeName); found && override.Import != "" {
+			// the type is declared by another package
+			continue
+		}
+
+		sample, err := builder.Build(def.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := json.Marshal(sample)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot encode sample of %s", def.ID)
+		}
+
+		cases = append(cases, roundTripTestCase{
+			TypeName: swag.ToGoName(def.TypeName),
+			Data:     string(data),
+		})
+	}
+
+	if len(cases) == 0 {
+		return nil, nil
+	}
+	sort.Slice(cases, func(i, j int) bool {
+		return cases[i].TypeName < cases[j].TypeName
+	})
+
+	testTemplate, err := template.New("roundtrip_test").Parse(ROUNDTRIP_TEST_TEMPLATE)
+	if err != nil {
+		return nil, err
+	}
+
+	templateData := struct {
+		Package string
+		Cases   []roundTripTestCase
+	}{
+		Package: filepath.Base(pkg.Name),
+		Cases:   cases,
+	}
+
+	var buf bytes.Buffer
+	if err := testTemplate.Execute(&buf, templateData); err != nil {
+		return nil, err
+	}
+
+	contents, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("cannot format generated code: %v", err)
+	}
+
+	return contents, nil
+}
@@ -0,0 +1,58 @@
+package split
+
+import (
+	"strings"
+	"testing"
+
+	openapi_spec "github.com/go-openapi/spec"
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
+)
+
+func TestRenderRoundTripTests(t *testing.T) {
+	gitRepo := "github.com/kubewarden/k8s-objects"
+
+	swagger := openapi_spec.Swagger{}
+	swagger.Definitions = make(openapi_spec.Definitions)
+	swagger.Definitions["io.k8s.api.core.v1.Binding"] = openapi_spec.Schema{
+		SchemaProps: openapi_spec.SchemaProps{
+			Properties: map[string]openapi_spec.Schema{
+				"kind": {
+					SchemaProps: openapi_spec.SchemaProps{Type: []string{"string"}},
+				},
+			},
+		},
+	}
+	// an interface, no round trip test must be generated for it
+	swagger.Definitions["io.k8s.api.core.v1.Raw"] = openapi_spec.Schema{
+		SchemaProps: openapi_spec.SchemaProps{
+			Type: []string{"object"},
+		},
+	}
+
+	plan, err := NewRefactoringPlan(&swagger, swagger_helpers.NamingOptions{})
+	if err != nil {
+		t.Fatalf("Cannot create refactoring plan: %v", err)
+	}
+
+	builder := swagger_helpers.NewSampleBuilder(plan.Definitions, swagger_helpers.SampleOptions{})
+	contents, err := renderRoundTripTests(plan.Packages["api/core/v1"], &builder, gitRepo, &plan.Interfaces)
+	if err != nil {
+		t.Fatalf("cannot render tests: %v", err)
+	}
+
+	code := string(contents)
+	expectedSnippets := []string{
+		"package v1",
+		`typeName: "Binding"`,
+		`obj:      &Binding{}`,
+		`data:     "{\"kind\":\"sample\"}"`,
+	}
+	for _, snippet := range expectedSnippets {
+		if !strings.Contains(code, snippet) {
+			t.Errorf("cannot find %s inside of generated code:\n%s", snippet, code)
+		}
+	}
+	if strings.Contains(code, "&Raw{}") {
+		t.Errorf("interfaces must not be tested:\n%s", code)
+	}
+}
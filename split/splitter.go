@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 
 	openapi_spec "github.com/go-openapi/spec"
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
 	"github.com/pkg/errors"
 )
 
@@ -21,18 +22,27 @@ func NewSplitter(swaggerFile string) (Splitter, error) {
 		return Splitter{}, errors.Wrapf(err, "cannot read swagger file %s", swaggerFile)
 	}
 
-	swagger := openapi_spec.Swagger{}
-	if err = swagger.UnmarshalJSON(data); err != nil {
+	splitter, err := NewSplitterFromData(data)
+	if err != nil {
 		return Splitter{}, errors.Wrapf(err, "cannot decode swagger file %s", swaggerFile)
 	}
 
+	return splitter, nil
+}
+
+func NewSplitterFromData(data []byte) (Splitter, error) {
+	swagger := openapi_spec.Swagger{}
+	if err := swagger.UnmarshalJSON(data); err != nil {
+		return Splitter{}, err
+	}
+
 	return Splitter{
 		vanillaSwagger: swagger,
 	}, nil
 }
 
-func (s *Splitter) ComputeRefactoringPlan() (*RefactoringPlan, error) {
-	return NewRefactoringPlan(&s.vanillaSwagger)
+func (s *Splitter) ComputeRefactoringPlan(options swagger_helpers.NamingOptions) (*RefactoringPlan, error) {
+	return NewRefactoringPlan(&s.vanillaSwagger, options)
 }
 
 type walkerStateSwaggerData struct {
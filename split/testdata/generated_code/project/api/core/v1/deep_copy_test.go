@@ -0,0 +1,67 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/example/types"
+	"github.com/kubewarden/k8s-objects/apimachinery/pkg/api/resource"
+	"github.com/kubewarden/k8s-objects/apimachinery/pkg/util/intstr"
+	"github.com/mailru/easyjson"
+)
+
+func TestDeepCopy(t *testing.T) {
+	mutations := map[string]func(*Pod){
+		"map":             func(p *Pod) { p.Metadata.Labels["app"] = "changed" },
+		"slice":           func(p *Pod) { p.Spec.Containers[0].Args[0] = "--quiet" },
+		"slice item":      func(p *Pod) { p.Spec.Containers[0].Name = stringPointer("sidecar") },
+		"bytes":           func(p *Pod) { p.Spec.Containers[0].Data[0] = 'D' },
+		"raw message":     func(p *Pod) { p.Spec.Raw[2] = 'K' },
+		"pointer":         func(p *Pod) { *p.Spec.RestartPolicy = RestartPolicyNever },
+		"IntOrString":     func(p *Pod) { *p.Spec.Containers[0].Port = intstr.IntOrStringFromString("http") },
+		"Quantity":        func(p *Pod) { p.Spec.Overhead["memory"].Set(1) },
+		"struct override": func(p *Pod) { *p.Spec.Containers[0].StartedAt = types.NewTime("2023-01-01T00:00:00Z") },
+		"opaque override": func(p *Pod) { p.Spec.Selector.MatchLabels["app"] = "changed" },
+	}
+
+	for name, mutate := range mutations {
+		pod := newTestPod()
+		pod.Spec.Selector = &types.LabelSelector{MatchLabels: map[string]string{"app": "test"}}
+		original := encode(t, pod)
+
+		copied := pod.DeepCopy()
+		if encode(t, copied) != original {
+			t.Fatalf("%s: the copy %s differs from the original %s", name, encode(t, copied), original)
+		}
+		mutate(copied)
+		if encode(t, pod) != original {
+			t.Errorf("%s: changing the copy altered the original: %s", name, encode(t, pod))
+		}
+		if encode(t, copied) == original {
+			t.Errorf("%s: the change must be applied to the copy", name)
+		}
+	}
+}
+
+func TestDeepCopyNil(t *testing.T) {
+	if (*Pod)(nil).DeepCopy() != nil {
+		t.Error("the copy of nil must be nil")
+	}
+
+	copied := (&PodSpec{Containers: []*Container{}, NodeSelector: map[string]string{}, Opaque: easyjson.RawMessage{}}).DeepCopy()
+	if copied.Containers == nil || copied.NodeSelector == nil || copied.Opaque == nil {
+		t.Errorf("empty collections must not be copied as nil: %+v", copied)
+	}
+	copied = (&PodSpec{}).DeepCopy()
+	if copied.Containers != nil || copied.NodeSelector != nil || copied.Opaque != nil || copied.Selector != nil {
+		t.Errorf("nil fields must be copied as nil: %+v", copied)
+	}
+}
+
+func TestDeepCopyQuantity(t *testing.T) {
+	quantity := resource.MustParse("1Gi")
+	copied := quantity.DeepCopy()
+	copied.Set(1)
+	if quantity.String() != "1Gi" {
+		t.Errorf("changing the copy altered the original: %s", quantity.String())
+	}
+}
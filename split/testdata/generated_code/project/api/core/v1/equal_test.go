@@ -0,0 +1,88 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/example/types"
+	"github.com/kubewarden/k8s-objects/apimachinery/pkg/api/resource"
+	"github.com/kubewarden/k8s-objects/apimachinery/pkg/util/intstr"
+	"github.com/mailru/easyjson"
+)
+
+func TestEqual(t *testing.T) {
+	mutations := map[string]func(*Pod){
+		"scalar":          func(p *Pod) { p.Spec.Hostname = "b" },
+		"map value":       func(p *Pod) { p.Metadata.Labels["app"] = "changed" },
+		"map key":         func(p *Pod) { p.Metadata.Labels = map[string]string{"name": "test"} },
+		"slice length":    func(p *Pod) { p.Spec.Containers[0].Args = append(p.Spec.Containers[0].Args, "--quiet") },
+		"slice item":      func(p *Pod) { p.Spec.Containers[0].Name = stringPointer("sidecar") },
+		"nil pointer":     func(p *Pod) { p.Spec.RestartPolicy = nil },
+		"enum":            func(p *Pod) { p.Spec.Containers[0].ImagePullPolicy = PullNever },
+		"bytes":           func(p *Pod) { p.Spec.Containers[0].Data = []byte("other") },
+		"raw message":     func(p *Pod) { p.Spec.Raw = easyjson.RawMessage(`{"kind":"Other"}`) },
+		"IntOrString":     func(p *Pod) { *p.Spec.Containers[0].Port = intstr.IntOrStringFromString("80") },
+		"Quantity":        func(p *Pod) { p.Spec.Overhead["memory"].Set(1) },
+		"struct override": func(p *Pod) { *p.Spec.Containers[0].StartedAt = types.NewTime("2023-01-01T00:00:00Z") },
+		"opaque override": func(p *Pod) { p.Spec.Selector.MatchLabels["app"] = "changed" },
+	}
+
+	for name, mutate := range mutations {
+		newPod := func() *Pod {
+			pod := newTestPod()
+			pod.Spec.Selector = &types.LabelSelector{MatchLabels: map[string]string{"app": "test"}}
+			return pod
+		}
+		pod, other := newPod(), newPod()
+		if !pod.Equal(other) || !other.Equal(pod) {
+			t.Fatalf("%s: pods built the same way must be equal", name)
+		}
+		mutate(other)
+		if pod.Equal(other) || other.Equal(pod) {
+			t.Errorf("%s: the change must be detected", name)
+		}
+	}
+}
+
+func TestEqualNilAndEmpty(t *testing.T) {
+	if !(*PodSpec)(nil).Equal(nil) || (*PodSpec)(nil).Equal(&PodSpec{}) || (&PodSpec{}).Equal(nil) {
+		t.Error("nil must only be equal to nil")
+	}
+
+	empty := &PodSpec{
+		Containers:   []*Container{},
+		NodeSelector: map[string]string{},
+		Opaque:       easyjson.RawMessage{},
+		Overhead:     map[string]*resource.Quantity{},
+	}
+	if !(&PodSpec{}).Equal(empty) || !empty.Equal(&PodSpec{}) {
+		t.Error("nil and empty collections must be equal")
+	}
+	if !(&Container{}).Equal(&Container{Args: []string{}, Data: []byte{}}) {
+		t.Error("nil and empty slices and bytes must be equal")
+	}
+
+	restartPolicy := RestartPolicy("")
+	if (&PodSpec{}).Equal(&PodSpec{RestartPolicy: &restartPolicy}) {
+		t.Error("nil pointers must not be equal to pointers to the zero value")
+	}
+}
+
+func TestEqualSemantics(t *testing.T) {
+	binary, decimal := resource.MustParse("1Gi"), resource.MustParse("1073741824")
+	a := &PodSpec{Overhead: map[string]*resource.Quantity{"memory": &binary}}
+	b := &PodSpec{Overhead: map[string]*resource.Quantity{"memory": &decimal}}
+	if !a.Equal(b) {
+		t.Error("quantities must be compared by amount")
+	}
+
+	x, y := types.NewTime("2022-01-01T00:00:00Z"), types.NewTime("2022-01-01T00:00:00Z")
+	if !(&Container{StartedAt: &x}).Equal(&Container{StartedAt: &y}) {
+		t.Error("overrides must be compared with their Equal method")
+	}
+
+	a = &PodSpec{Selector: &types.LabelSelector{MatchLabels: map[string]string{"a": "1", "b": "2"}}}
+	b = &PodSpec{Selector: &types.LabelSelector{MatchLabels: map[string]string{"b": "2", "a": "1"}}}
+	if !a.Equal(b) {
+		t.Error("opaque overrides must be compared through their JSON representation")
+	}
+}
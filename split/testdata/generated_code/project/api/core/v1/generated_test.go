@@ -0,0 +1,64 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/example/types"
+	"github.com/go-openapi/strfmt"
+	"github.com/kubewarden/k8s-objects/apimachinery/pkg/api/resource"
+	meta_v1 "github.com/kubewarden/k8s-objects/apimachinery/pkg/apis/meta/v1"
+	"github.com/kubewarden/k8s-objects/apimachinery/pkg/util/intstr"
+	"github.com/mailru/easyjson"
+)
+
+func stringPointer(s string) *string {
+	return &s
+}
+
+// Returns the JSON encoding of the value, used to compare values without
+// going through the generated Equal methods
+func encode(t *testing.T, v easyjson.Marshaler) string {
+	t.Helper()
+	data, err := easyjson.Marshal(v)
+	if err != nil {
+		t.Fatalf("cannot encode %T: %v", v, err)
+	}
+	return string(data)
+}
+
+func newTestPod() *Pod {
+	startedAt := types.NewTime("2022-01-01T00:00:00Z")
+	quantity := resource.MustParse("1Gi")
+	port := intstr.IntOrStringFromInt(80)
+	restartPolicy := RestartPolicyAlways
+
+	pod := NewPod()
+	pod.Metadata = &meta_v1.ObjectMeta{Name: "pod", Labels: map[string]string{"app": "test"}}
+	pod.Spec = &PodSpec{
+		Containers: []*Container{
+			{
+				Name:            stringPointer("main"),
+				Args:            []string{"--verbose"},
+				Data:            strfmt.Base64("data"),
+				ImagePullPolicy: PullAlways,
+				Port:            &port,
+				StartedAt:       &startedAt,
+			},
+		},
+		Hostname:      "a",
+		Overhead:      map[string]*resource.Quantity{"memory": &quantity},
+		RestartPolicy: &restartPolicy,
+		Raw:           easyjson.RawMessage(`{"kind":"Raw"}`),
+	}
+	return pod
+}
+
+func TestPodAccessors(t *testing.T) {
+	pod := newTestPod()
+	if pod.GroupVersionKind().String() != "v1, Kind=Pod" {
+		t.Errorf("wrong GroupVersionKind: %s", pod.GroupVersionKind())
+	}
+	if pod.GetPodSpec() != pod.Spec || pod.GetObjectMeta() != pod.Metadata {
+		t.Error("the accessors must return the fields of the pod")
+	}
+}
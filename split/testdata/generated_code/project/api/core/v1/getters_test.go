@@ -0,0 +1,47 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/example/types"
+	"github.com/kubewarden/k8s-objects/apimachinery/pkg/util/intstr"
+)
+
+func TestGetters(t *testing.T) {
+	pod := newTestPod()
+	pod.Spec.Selector = &types.LabelSelector{MatchLabels: map[string]string{"app": "test"}}
+
+	container := pod.GetSpec().GetContainers()[0]
+	if container.GetName() != "main" || container.GetArgs()[0] != "--verbose" || string(container.GetData()) != "data" {
+		t.Errorf("the getters must return the fields: %+v", container)
+	}
+	if container.GetPort() != intstr.IntOrStringFromInt(80) || container.GetImagePullPolicy() != PullAlways {
+		t.Errorf("the getters must dereference the fields: %+v", container)
+	}
+	startedAt := container.GetStartedAt()
+	if !startedAt.Equal(container.StartedAt) {
+		t.Errorf("wrong startedAt: %s", startedAt.String())
+	}
+	if pod.GetSpec().GetRestartPolicy() != RestartPolicyAlways || pod.GetSpec().GetSelector() != pod.Spec.Selector {
+		t.Errorf("the getters must return the fields: %+v", pod.Spec)
+	}
+	if pod.GetMetadata().GetLabels()["app"] != "test" || pod.GetSpec().GetOverhead()["memory"].String() != "1Gi" {
+		t.Error("the getters of other packages must return the fields")
+	}
+}
+
+func TestGettersZeroValues(t *testing.T) {
+	var pod *Pod
+	if pod.GetSpec().GetHostname() != "" || pod.GetSpec().GetContainers() != nil || pod.GetMetadata().GetName() != "" {
+		t.Error("the getters must return the zero value when the receiver is nil")
+	}
+
+	container := &Container{}
+	if container.GetName() != "" || container.GetPort() != (intstr.IntOrString{}) || container.GetStartedAt().String() != "" {
+		t.Errorf("the getters must return the zero value when pointers are not set: %+v", container)
+	}
+	spec := &PodSpec{}
+	if spec.GetRestartPolicy() != "" || spec.GetSelector() != nil || spec.GetNodeSelector() != nil || spec.GetRaw() != nil {
+		t.Errorf("the getters must return the zero value when the fields are not set: %+v", spec)
+	}
+}
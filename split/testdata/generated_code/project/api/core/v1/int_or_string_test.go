@@ -0,0 +1,67 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/kubewarden/k8s-objects/apimachinery/pkg/util/intstr"
+	"github.com/mailru/easyjson"
+)
+
+func TestIntOrString(t *testing.T) {
+	if v := intstr.ParseIntOrString("80"); v.Type != intstr.IntOrStringInt || v.IntValue() != 80 || v.String() != "80" {
+		t.Errorf("numbers must be parsed as int: %+v", v)
+	}
+	if v := intstr.ParseIntOrString("http"); v != intstr.IntOrStringFromString("http") || v.IntValue() != 0 || v.String() != "http" {
+		t.Errorf("other values must be parsed as string: %+v", v)
+	}
+
+	cases := []struct {
+		value    intstr.IntOrString
+		roundUp  bool
+		expected int
+	}{
+		{intstr.IntOrStringFromInt(3), false, 3},
+		{intstr.IntOrStringFromString("25%"), false, 2},
+		{intstr.IntOrStringFromString("25%"), true, 3},
+	}
+	for _, c := range cases {
+		scaled, err := c.value.ScaledValue(10, c.roundUp)
+		if err != nil || scaled != c.expected {
+			t.Errorf("wrong scaled value of %s: %d %v, expected %d", c.value.String(), scaled, err, c.expected)
+		}
+	}
+	if _, err := intstr.IntOrStringFromString("http").ScaledValue(10, false); err == nil {
+		t.Error("strings that are not percentages cannot be scaled")
+	}
+	if _, err := intstr.GetScaledValueFromIntOrPercent(nil, 10, false); err == nil {
+		t.Error("nil values cannot be scaled")
+	}
+}
+
+func TestIntOrStringJSON(t *testing.T) {
+	cases := map[string]intstr.IntOrString{
+		`{"port":80}`:     intstr.IntOrStringFromInt(80),
+		`{"port":"http"}`: intstr.IntOrStringFromString("http"),
+		`{"port":"80"}`:   intstr.IntOrStringFromString("80"),
+	}
+	for document, expected := range cases {
+		container := &Container{}
+		if err := easyjson.Unmarshal([]byte(document), container); err != nil {
+			t.Fatalf("cannot decode %s: %v", document, err)
+		}
+		if container.GetPort() != expected {
+			t.Errorf("wrong port decoded from %s: %+v", document, container.Port)
+		}
+		if encoded := encode(t, container); encoded != document {
+			t.Errorf("wrong encoding of %s: %s", document, encoded)
+		}
+	}
+
+	container := &Container{}
+	if err := easyjson.Unmarshal([]byte(`{"port":null}`), container); err != nil || container.Port != nil {
+		t.Errorf("null must be decoded as nil: %+v %v", container.Port, err)
+	}
+	if err := easyjson.Unmarshal([]byte(`{"port":true}`), container); err == nil {
+		t.Error("values that are neither int nor string must be rejected")
+	}
+}
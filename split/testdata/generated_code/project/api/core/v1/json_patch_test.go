@@ -0,0 +1,183 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/example/types"
+	"github.com/kubewarden/k8s-objects/apimachinery/pkg/api/resource"
+	"github.com/kubewarden/k8s-objects/apimachinery/pkg/util/intstr"
+	"github.com/kubewarden/k8s-objects/jsonpatch"
+	"github.com/mailru/easyjson"
+)
+
+// Applies the operations to the JSON document and returns the result. Only
+// the operations returned by the generated code are supported
+func applyPatch(t *testing.T, document string, ops []jsonpatch.PatchOperation) string {
+	t.Helper()
+
+	data, err := jsonpatch.Marshal(ops)
+	if err != nil {
+		t.Fatalf("cannot marshal operations: %v", err)
+	}
+	decoded := []struct {
+		Op    string
+		Path  string
+		Value interface{}
+	}{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("cannot decode operations %s: %v", data, err)
+	}
+
+	var node interface{}
+	if err := json.Unmarshal([]byte(document), &node); err != nil {
+		t.Fatalf("cannot decode document %s: %v", document, err)
+	}
+	for _, op := range decoded {
+		tokens := []string{}
+		for _, token := range strings.Split(op.Path, "/")[1:] {
+			tokens = append(tokens, strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~"))
+		}
+		node, err = applyOperation(node, tokens, op.Op, op.Value)
+		if err != nil {
+			t.Fatalf("cannot apply %s %s to %s: %v", op.Op, op.Path, document, err)
+		}
+	}
+
+	patched, err := json.Marshal(node)
+	if err != nil {
+		t.Fatalf("cannot encode the patched document: %v", err)
+	}
+	return string(patched)
+}
+
+func applyOperation(node interface{}, tokens []string, op string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		if op != "replace" {
+			return nil, fmt.Errorf("cannot %s the whole document", op)
+		}
+		return value, nil
+	}
+
+	switch n := node.(type) {
+	case map[string]interface{}:
+		key := tokens[0]
+		child, found := n[key]
+		if len(tokens) > 1 {
+			if !found {
+				return nil, fmt.Errorf("cannot find %s", key)
+			}
+			patched, err := applyOperation(child, tokens[1:], op, value)
+			n[key] = patched
+			return n, err
+		}
+		switch {
+		case op == "add":
+			n[key] = value
+		case !found:
+			return nil, fmt.Errorf("cannot find %s", key)
+		case op == "remove":
+			delete(n, key)
+		default:
+			n[key] = value
+		}
+		return n, nil
+	case []interface{}:
+		i, err := strconv.Atoi(tokens[0])
+		if err != nil || i < 0 || i > len(n) || (i == len(n) && (op != "add" || len(tokens) > 1)) {
+			return nil, fmt.Errorf("invalid index %s", tokens[0])
+		}
+		if len(tokens) > 1 {
+			patched, err := applyOperation(n[i], tokens[1:], op, value)
+			n[i] = patched
+			return n, err
+		}
+		switch op {
+		case "add":
+			n = append(n[:i], append([]interface{}{value}, n[i:]...)...)
+		case "remove":
+			n = append(n[:i], n[i+1:]...)
+		default:
+			n[i] = value
+		}
+		return n, nil
+	}
+
+	return nil, fmt.Errorf("cannot find %s inside of a scalar", tokens[0])
+}
+
+func TestDiff(t *testing.T) {
+	mutations := map[string]func(*Pod){
+		"scalar":          func(p *Pod) { p.Spec.Hostname = "b" },
+		"removed scalar":  func(p *Pod) { p.Spec.Hostname = "" },
+		"map value":       func(p *Pod) { p.Metadata.Labels["app"] = "changed" },
+		"map key":         func(p *Pod) { p.Metadata.Labels = map[string]string{"name": "test"} },
+		"added map":       func(p *Pod) { p.Spec.NodeSelector = map[string]string{"disk": "ssd"} },
+		"removed map":     func(p *Pod) { p.Metadata.Labels = nil },
+		"slice item":      func(p *Pod) { p.Spec.Containers[0].Args[0] = "--quiet" },
+		"slice length":    func(p *Pod) { p.Spec.Containers[0].Args = append(p.Spec.Containers[0].Args, "--quiet") },
+		"removed pointer": func(p *Pod) { p.Spec.RestartPolicy = nil },
+		"enum":            func(p *Pod) { p.Spec.Containers[0].ImagePullPolicy = PullNever },
+		"bytes":           func(p *Pod) { p.Spec.Containers[0].Data = []byte("other") },
+		"raw message":     func(p *Pod) { p.Spec.Raw = easyjson.RawMessage(`{"kind":"Other"}`) },
+		"added raw":       func(p *Pod) { p.Spec.Opaque = easyjson.RawMessage(`{"a":[1,2]}`) },
+		"IntOrString":     func(p *Pod) { *p.Spec.Containers[0].Port = intstr.IntOrStringFromString("http") },
+		"Quantity":        func(p *Pod) { p.Spec.Overhead["cpu"] = resource.NewMilliQuantity(500, resource.DecimalSI) },
+		"struct override": func(p *Pod) { *p.Spec.Containers[0].StartedAt = types.NewTime("2023-01-01T00:00:00Z") },
+		"opaque override": func(p *Pod) { p.Spec.Selector = &types.LabelSelector{MatchLabels: map[string]string{"app": "changed"}} },
+		"added item": func(p *Pod) {
+			p.Spec.Containers = append(p.Spec.Containers, &Container{Name: stringPointer("sidecar")})
+		},
+		"removed item": func(p *Pod) { p.Spec.Containers = nil },
+		"reordered items": func(p *Pod) {
+			p.Spec.Containers = append([]*Container{{Name: stringPointer("init")}}, p.Spec.Containers...)
+		},
+	}
+
+	for name, mutate := range mutations {
+		from, to := newTestPod(), newTestPod()
+		mutate(to)
+
+		ops := DiffPod(from, to)
+		if len(ops) == 0 {
+			t.Errorf("%s: the change must be detected", name)
+			continue
+		}
+		patched := NewPod()
+		if err := easyjson.Unmarshal([]byte(applyPatch(t, encode(t, from), ops)), patched); err != nil {
+			t.Fatalf("%s: cannot decode the patched document: %v", name, err)
+		}
+		if !patched.Equal(to) {
+			t.Errorf("%s: the operations %v turn %s into %s instead of %s", name, ops, encode(t, from), encode(t, patched), encode(t, to))
+		}
+	}
+
+	if ops := DiffPod(newTestPod(), newTestPod()); len(ops) != 0 {
+		t.Errorf("no operation expected, got %v", ops)
+	}
+}
+
+func TestDiffOperations(t *testing.T) {
+	from := newTestPod().Spec
+	to := newTestPod().Spec
+	to.Containers[0].Args[0] = "--quiet"
+	to.Containers = append(to.Containers, &Container{Name: stringPointer("sidecar")})
+	to.Hostname = "b"
+	to.Selector = &types.LabelSelector{MatchLabels: map[string]string{"app": "changed"}}
+
+	data, err := jsonpatch.Marshal(DiffPodSpec(from, to))
+	if err != nil {
+		t.Fatalf("cannot marshal the operations: %v", err)
+	}
+	// the list map is patched item by item, the opaque override as a whole
+	expected := `[{"op":"replace","path":"/containers/0/args/0","value":"--quiet"},` +
+		`{"op":"add","path":"/containers/1","value":{"name":"sidecar"}},` +
+		`{"op":"replace","path":"/hostname","value":"b"},` +
+		`{"op":"add","path":"/selector","value":{"matchLabels":{"app":"changed"}}}]`
+	if string(data) != expected {
+		t.Errorf("wrong operations:\n%s\nexpected:\n%s", data, expected)
+	}
+}
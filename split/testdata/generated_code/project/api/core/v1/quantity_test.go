@@ -0,0 +1,47 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/kubewarden/k8s-objects/apimachinery/pkg/api/resource"
+	"github.com/mailru/easyjson"
+)
+
+func TestQuantityFields(t *testing.T) {
+	spec := &PodSpec{}
+	document := `{"overhead":{"cpu":"0.5","memory":"1024Mi","pods":10}}`
+	if err := easyjson.Unmarshal([]byte(document), spec); err != nil {
+		t.Fatalf("cannot decode %s: %v", document, err)
+	}
+	if spec.Overhead["cpu"].MilliValue() != 500 || spec.Overhead["memory"].Value() != 1<<30 || spec.Overhead["pods"].Value() != 10 {
+		t.Errorf("wrong quantities: %v", spec.Overhead)
+	}
+
+	spec.Overhead["memory"].Add(resource.MustParse("1Gi"))
+	expected := `{"overhead":{"cpu":"500m","memory":"2Gi","pods":"10"}}`
+	if encoded := encode(t, spec); encoded != expected {
+		t.Errorf("wrong encoding of the quantities: %s, expected %s", encoded, expected)
+	}
+
+	if err := easyjson.Unmarshal([]byte(`{"overhead":{"cpu":"1.1.1"}}`), &PodSpec{}); err == nil {
+		t.Error("invalid quantities must be rejected")
+	}
+}
+
+func TestQuantityAmounts(t *testing.T) {
+	binary, decimal := resource.MustParse("1Gi"), resource.MustParse("1073741824")
+	from := &PodSpec{Overhead: map[string]*resource.Quantity{"memory": &binary}}
+	to := &PodSpec{Overhead: map[string]*resource.Quantity{"memory": &decimal}}
+	if binary.Cmp(decimal) != 0 || !from.Equal(to) {
+		t.Error("quantities must be compared by amount")
+	}
+	if ops := DiffPodSpec(from, to); len(ops) != 0 {
+		t.Errorf("quantities with the same amount must not be patched: %v", ops)
+	}
+
+	copied := to.DeepCopy()
+	copied.Overhead["memory"].Sub(resource.MustParse("1Mi"))
+	if !from.Equal(to) || from.Equal(copied) || copied.Overhead["memory"].Value() != 1023<<20 {
+		t.Errorf("wrong quantities after the subtraction: %v", copied.Overhead)
+	}
+}
@@ -0,0 +1,48 @@
+package registry
+
+import (
+	"testing"
+
+	api_core_v1 "github.com/kubewarden/k8s-objects/api/core/v1"
+)
+
+func TestDecode(t *testing.T) {
+	obj, gvk, err := Decode([]byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"pod","deletionTimestamp":"now"}}`))
+	if err != nil {
+		t.Fatalf("cannot decode pod: %v", err)
+	}
+	pod, ok := obj.(*api_core_v1.Pod)
+	if !ok {
+		t.Fatalf("wrong type %T for %s", obj, gvk)
+	}
+	if pod.GetObjectMeta().Name != "pod" || *pod.Metadata.DeletionTimestamp != "now" {
+		t.Errorf("wrong metadata: %+v", pod.Metadata)
+	}
+
+	if _, _, err := Decode([]byte(`{"apiVersion":"v1","kind":"Unknown"}`)); err == nil {
+		t.Error("unknown kinds must be rejected")
+	}
+}
+
+func TestEmbeddedObjects(t *testing.T) {
+	spec := api_core_v1.PodSpec{}
+	if err := EncodeAPICoreV1PodSpecTemplate(&spec, api_core_v1.NewPod()); err != nil {
+		t.Fatalf("cannot encode the embedded pod: %v", err)
+	}
+	obj, err := DecodeAPICoreV1PodSpecTemplate(&spec)
+	if err != nil {
+		t.Fatalf("cannot decode the embedded pod: %v", err)
+	}
+	if _, ok := obj.(*api_core_v1.Pod); !ok {
+		t.Errorf("wrong type %T of the embedded object %s", obj, spec.Template)
+	}
+
+	spec.Raw = []byte(`{"apiVersion":"example.com/v1","kind":"Widget","size":3}`)
+	obj, err = DecodeAPICoreV1PodSpecRaw(&spec)
+	if err != nil {
+		t.Fatalf("cannot decode the raw extension: %v", err)
+	}
+	if unknown, ok := obj.(*Unknown); !ok || unknown.GroupVersionKind().Kind != "Widget" {
+		t.Errorf("objects of unknown kinds must be preserved, got %#v", obj)
+	}
+}
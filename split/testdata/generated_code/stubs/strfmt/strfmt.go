@@ -0,0 +1,31 @@
+// Package strfmt stands in for github.com/go-openapi/strfmt, it declares the
+// types used by the generated code. Dates are kept as strings
+package strfmt
+
+import "encoding/json"
+
+type Date struct {
+	value string
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.value)
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &d.value)
+}
+
+type DateTime struct {
+	value string
+}
+
+func (d DateTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.value)
+}
+
+func (d *DateTime) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &d.value)
+}
+
+type Base64 []byte
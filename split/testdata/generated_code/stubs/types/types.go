@@ -0,0 +1,130 @@
+// Package types declares the types replacing some definitions of the
+// generated code
+package types
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mailru/easyjson"
+	"github.com/mailru/easyjson/jlexer"
+	"github.com/mailru/easyjson/jwriter"
+)
+
+// Time is not comparable, it declares its own DeepCopyInto and Equal methods
+type Time struct {
+	parts []string
+}
+
+func NewTime(value string) Time {
+	return Time{parts: strings.Split(value, "T")}
+}
+
+func (t Time) String() string {
+	return strings.Join(t.parts, "T")
+}
+
+func (t Time) MarshalEasyJSON(w *jwriter.Writer) {
+	w.String(t.String())
+}
+
+func (t Time) MarshalJSON() ([]byte, error) {
+	return easyjson.Marshal(t)
+}
+
+func (t *Time) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	*t = NewTime(l.String())
+}
+
+func (t *Time) UnmarshalJSON(data []byte) error {
+	return easyjson.Unmarshal(data, t)
+}
+
+func (t *Time) DeepCopyInto(out *Time) {
+	out.parts = append([]string(nil), t.parts...)
+}
+
+func (t *Time) Equal(other *Time) bool {
+	if t == nil || other == nil {
+		return t == other
+	}
+	return t.String() == other.String()
+}
+
+// MicroTime is comparable and copied by assignment
+type MicroTime string
+
+func (t MicroTime) MarshalText() ([]byte, error) {
+	return []byte(t), nil
+}
+
+func (t *MicroTime) UnmarshalText(data []byte) error {
+	*t = MicroTime(data)
+	return nil
+}
+
+// LabelSelector is opaque: it shares memory with its copies and it's not
+// comparable, the generated code handles it through its JSON representation
+type LabelSelector struct {
+	MatchLabels map[string]string
+}
+
+func (s LabelSelector) MarshalEasyJSON(w *jwriter.Writer) {
+	w.RawString(`{"matchLabels":{`)
+	first := true
+	for _, k := range sortedKeys(s.MatchLabels) {
+		if !first {
+			w.RawByte(',')
+		}
+		first = false
+		w.String(k)
+		w.RawByte(':')
+		w.String(s.MatchLabels[k])
+	}
+	w.RawString("}}")
+}
+
+func (s LabelSelector) MarshalJSON() ([]byte, error) {
+	return easyjson.Marshal(s)
+}
+
+func (s *LabelSelector) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	if l.IsNull() {
+		l.Skip()
+		return
+	}
+	s.MatchLabels = make(map[string]string)
+	l.Delim('{')
+	for !l.IsDelim('}') {
+		field := l.String()
+		l.WantColon()
+		if field != "matchLabels" {
+			l.SkipRecursive()
+			l.WantComma()
+			continue
+		}
+		l.Delim('{')
+		for !l.IsDelim('}') {
+			key := l.String()
+			l.WantColon()
+			s.MatchLabels[key] = l.String()
+			l.WantComma()
+		}
+		l.Delim('}')
+		l.WantComma()
+	}
+	l.Delim('}')
+}
+
+func (s *LabelSelector) UnmarshalJSON(data []byte) error {
+	return easyjson.Unmarshal(data, s)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
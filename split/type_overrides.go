@@ -0,0 +1,200 @@
+package split
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/go-openapi/swag"
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
+	"github.com/pkg/errors"
+)
+
+const TYPE_OVERRIDES_FILE_NAME = "type_overrides.go"
+
+const TYPE_OVERRIDES_TEMPLATE = `// Code generated by k8s-objects-generator. DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+{{- range $path, $alias := .Imports }}
+	{{ $alias }} "{{ $path }}"
+{{- end }}
+)
+{{ range .Overrides }}
+// {{ .TypeName }} is replaced by {{ .Replacement }}, which handles JSON on its own
+var (
+	_ {{ .Marshaler }}   = (*{{ .Replacement }})(nil)
+	_ {{ .Unmarshaler }} = (*{{ .Replacement }})(nil)
+{{- if .DeepCopy }}
+	_ interface{ DeepCopyInto(*{{ .Replacement }}) } = (*{{ .Replacement }})(nil)
+{{- end }}
+{{- if .Equal }}
+	_ interface{ Equal(*{{ .Replacement }}) bool } = (*{{ .Replacement }})(nil)
+{{- else if .Comparable }}
+	// the values are compared with ==
+	_ = func(a, b {{ .Replacement }}) bool { return a == b }
+{{- end }}
+)
+{{ end }}
+`
+
+// Interfaces checked for each kind of marshaler, with the package defining
+// them
+var overrideMarshalerInterfaces = map[string]struct {
+	Import      string
+	Marshaler   string
+	Unmarshaler string
+}{
+	swagger_helpers.EASYJSON_MARSHALER: {"github.com/mailru/easyjson", "easyjson.Marshaler", "easyjson.Unmarshaler"},
+	swagger_helpers.JSON_MARSHALER:     {"encoding/json", "json.Marshaler", "json.Unmarshaler"},
+	swagger_helpers.TEXT_MARSHALER:     {"encoding", "encoding.TextMarshaler", "encoding.TextUnmarshaler"},
+}
+
+type typeOverrideCheck struct {
+	TypeName    string
+	Replacement string
+	Marshaler   string
+	Unmarshaler string
+	DeepCopy    bool
+	Equal       bool
+	// set when the values are compared with ==
+	Comparable bool
+}
+
+// Writes, for each package, the compile-time checks ensuring the types that
+// replace its definitions implement the declared marshalers and methods
+func GenerateTypeOverrideChecks(project Project, plan *RefactoringPlan) error {
+	for pkgName, pkg := range plan.Packages {
+		contents, err := renderTypeOverrideChecks(pkg, &plan.Interfaces, project.GitRepo, plan.Naming)
+		if err != nil {
+			return errors.Wrapf(err, "cannot render type override checks of package %s", pkgName)
+		}
+		if contents == nil {
+			continue
+		}
+
+		pkgDir := filepath.Join(project.Root, pkgName)
+		if err := os.MkdirAll(pkgDir, 0777); err != nil {
+			return errors.Wrapf(err, "cannot create dir %s", pkgDir)
+		}
+		fileName := filepath.Join(pkgDir, TYPE_OVERRIDES_FILE_NAME)
+		if err := os.WriteFile(fileName, contents, 0644); err != nil {
+			return errors.Wrapf(err, "cannot write %s", fileName)
+		}
+	}
+
+	return nil
+}
+
+// Returns the contents of the file holding the checks of the overridden
+// definitions of the package, nil when no definition is replaced by a type
+// of another package
+func renderTypeOverrideChecks(pkg swagger_helpers.Package, interfaces *swagger_helpers.InterfaceRegistry, gitRepo string, naming swagger_helpers.Naming) ([]byte, error) {
+	imports := make(map[string]string)
+	checks := []typeOverrideCheck{}
+
+	for _, def := range pkg.Definitions {
+		override, found := interfaces.Override(gitRepo, def.PackageName, def.TypeName)
+		if !found || override.Import == "" {
+			continue
+		}
+
+		marshaler := override.Marshaler
+		if marshaler == "" {
+			marshaler = swagger_helpers.EASYJSON_MARSHALER
+		}
+		marshalerInterfaces, known := overrideMarshalerInterfaces[marshaler]
+		if !known {
+			return nil, fmt.Errorf("unknown marshaler %s for the override of %s", marshaler, def.ID)
+		}
+
+		alias := override.ImportAlias(naming, def.PackageName)
+		imports[override.ImportPath(gitRepo, def.PackageName)] = alias
+		imports[marshalerInterfaces.Import] = filepath.Base(marshalerInterfaces.Import)
+
+		checks = append(checks, typeOverrideCheck{
+			TypeName:    swag.ToGoName(def.TypeName),
+			Replacement: alias + "." + override.Type,
+			Marshaler:   marshalerInterfaces.Marshaler,
+			Unmarshaler: marshalerInterfaces.Unmarshaler,
+			DeepCopy:    override.DeepCopy,
+			Equal:       override.Equal,
+			Comparable:  !override.Equal && override.Kind != swagger_helpers.OPAQUE_OVERRIDE,
+		})
+	}
+
+	if len(checks) == 0 {
+		return nil, nil
+	}
+	sort.Slice(checks, func(i, j int) bool {
+		return checks[i].TypeName < checks[j].TypeName
+	})
+
+	typeOverridesTemplate, err := template.New("type_overrides").Parse(TYPE_OVERRIDES_TEMPLATE)
+	if err != nil {
+		return nil, err
+	}
+
+	templateData := struct {
+		Package   string
+		Imports   map[string]string
+		Overrides []typeOverrideCheck
+	}{
+		Package:   filepath.Base(pkg.Name),
+		Imports:   imports,
+		Overrides: checks,
+	}
+
+	var buf bytes.Buffer
+	if err := typeOverridesTemplate.Execute(&buf, templateData); err != nil {
+		return nil, err
+	}
+
+	contents, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("cannot format generated code: %v", err)
+	}
+
+	return contents, nil
+}
+
+// Returns the call encoding the value x of an opaque override, which returns
+// the JSON document and an error. x must be addressable
+func opaqueMarshalCall(t swagger_helpers.GoType, x string, imports map[string]string) string {
+	switch t.Marshaler {
+	case swagger_helpers.JSON_MARSHALER:
+		return fmt.Sprintf("(%s).MarshalJSON()", addressOf(x))
+	case swagger_helpers.TEXT_MARSHALER:
+		return fmt.Sprintf("(%s).MarshalText()", addressOf(x))
+	}
+	imports["github.com/mailru/easyjson"] = "easyjson"
+	return fmt.Sprintf("easyjson.Marshal(%s)", addressOf(x))
+}
+
+// Returns the call decoding data into the value x of an opaque override,
+// which returns an error. x must be addressable
+func opaqueUnmarshalCall(t swagger_helpers.GoType, data, x string, imports map[string]string) string {
+	switch t.Marshaler {
+	case swagger_helpers.JSON_MARSHALER:
+		return fmt.Sprintf("(%s).UnmarshalJSON(%s)", addressOf(x), data)
+	case swagger_helpers.TEXT_MARSHALER:
+		return fmt.Sprintf("(%s).UnmarshalText(%s)", addressOf(x), data)
+	}
+	imports["github.com/mailru/easyjson"] = "easyjson"
+	return fmt.Sprintf("easyjson.Unmarshal(%s, %s)", data, addressOf(x))
+}
+
+// Returns the expression of the pointer to the addressable value x, e.g.
+// `m.Field` for `(*m.Field)`
+func addressOf(x string) string {
+	if strings.HasPrefix(x, "(*") && strings.HasSuffix(x, ")") && !strings.ContainsAny(x[2:len(x)-1], "()") {
+		return x[2 : len(x)-1]
+	}
+	return "&" + x
+}
@@ -0,0 +1,166 @@
+package split
+
+import (
+	"strings"
+	"testing"
+
+	openapi_spec "github.com/go-openapi/spec"
+	"github.com/kubewarden/k8s-objects-generator/swagger_helpers"
+)
+
+func TestApplyTypeOverrides(t *testing.T) {
+	gitRepo := "github.com/kubewarden/k8s-objects"
+	plan := newRegistryTestPlan(t, openapi_spec.Definitions{
+		"io.k8s.apimachinery.pkg.apis.meta.v1.Time":      {SchemaProps: openapi_spec.SchemaProps{Type: []string{"object"}}},
+		"io.k8s.apimachinery.pkg.apis.meta.v1.MicroTime": stringProperty("date-time"),
+	})
+	if !plan.Interfaces.IsInterface(gitRepo, "apimachinery/pkg/apis/meta/v1", "Time") {
+		t.Fatal("objects without properties must be interfaces by default")
+	}
+
+	err := plan.ApplyTypeOverrides(swagger_helpers.TypeOverrides{
+		"io.k8s.apimachinery.pkg.apis.meta.v1.Time":      {Import: "github.com/example/types", Type: "Time"},
+		"io.k8s.apimachinery.pkg.apis.meta.v1.MicroTime": {Import: "github.com/example/types", Type: "MicroTime", Marshaler: "text"},
+	})
+	if err != nil {
+		t.Fatalf("cannot apply overrides: %v", err)
+	}
+	if plan.Interfaces.IsInterface(gitRepo, "apimachinery/pkg/apis/meta/v1", "Time") {
+		t.Error("overridden definitions must not be interfaces")
+	}
+
+	contents, err := renderTypeOverrideChecks(plan.Packages["apimachinery/pkg/apis/meta/v1"], &plan.Interfaces, gitRepo, plan.Naming)
+	if err != nil {
+		t.Fatalf("cannot render type override checks: %v", err)
+	}
+
+	code := string(contents)
+	expectedSnippets := []string{
+		"package v1",
+		`github_com_example_types "github.com/example/types"`,
+		`easyjson "github.com/mailru/easyjson"`,
+		`encoding "encoding"`,
+		"_ easyjson.Marshaler   = (*github_com_example_types.Time)(nil)",
+		"_ encoding.TextUnmarshaler = (*github_com_example_types.MicroTime)(nil)",
+	}
+	for _, snippet := range expectedSnippets {
+		if !strings.Contains(code, snippet) {
+			t.Errorf("cannot find %s inside of generated code:\n%s", snippet, code)
+		}
+	}
+}
+
+func TestApplyTypeOverridesErrors(t *testing.T) {
+	plan := newRegistryTestPlan(t, openapi_spec.Definitions{
+		"io.k8s.apimachinery.pkg.apis.meta.v1.Time": stringProperty("date-time"),
+	})
+
+	cases := []swagger_helpers.TypeOverrides{
+		{"io.k8s.apimachinery.pkg.apis.meta.v1.Unknown": {Import: "github.com/example/types", Type: "Time"}},
+		{"io.k8s.apimachinery.pkg.apis.meta.v1.Time": {Import: "github.com/example/types", Type: "Time", Marshaler: "yaml"}},
+		{"io.k8s.apimachinery.pkg.apis.meta.v1.Time": {Import: "github.com/example/types", Type: "Time", Kind: "struct"}},
+	}
+	for _, overrides := range cases {
+		if err := plan.ApplyTypeOverrides(overrides); err == nil {
+			t.Errorf("%+v: expected an error", overrides)
+		}
+	}
+}
+
+func TestForeignOverridesHaveNoMethods(t *testing.T) {
+	gitRepo := "github.com/kubewarden/k8s-objects"
+	plan := newRegistryTestPlan(t, openapi_spec.Definitions{
+		"io.k8s.apimachinery.pkg.apis.meta.v1.Time": stringProperty("date-time"),
+		"io.k8s.apimachinery.pkg.apis.meta.v1.Status": objectSchema(map[string]openapi_spec.Schema{
+			"reason": stringProperty(""),
+			"since":  refProperty("io.k8s.apimachinery.pkg.apis.meta.v1.Time"),
+		}),
+	})
+	err := plan.ApplyTypeOverrides(swagger_helpers.TypeOverrides{
+		"io.k8s.apimachinery.pkg.apis.meta.v1.Time": {Import: "github.com/example/types", Type: "Time"},
+	})
+	if err != nil {
+		t.Fatalf("cannot apply overrides: %v", err)
+	}
+
+	pkg := plan.Packages["apimachinery/pkg/apis/meta/v1"]
+	resolver := swagger_helpers.NewGoTypeResolver(plan.Definitions, &plan.Interfaces, gitRepo)
+	renderers := map[string]func() ([]byte, error){
+		"DeepCopy": func() ([]byte, error) { return renderDeepCopy(pkg, &resolver) },
+		"Equal":    func() ([]byte, error) { return renderEqual(pkg, &resolver) },
+		"getters": func() ([]byte, error) {
+			return renderGetters(pkg, &resolver, map[string][]string{}, gitRepo, &plan.Interfaces)
+		},
+		"JSON patch": func() ([]byte, error) {
+			return renderJSONPatchDiffs(pkg, &resolver, plan.Definitions, gitRepo)
+		},
+	}
+	for name, render := range renderers {
+		contents, err := render()
+		if err != nil {
+			t.Errorf("cannot render %s: %v", name, err)
+			continue
+		}
+		code := string(contents)
+		if !strings.Contains(code, "func (m *Status)") {
+			t.Errorf("%s: the methods of the other types must be generated:\n%s", name, code)
+		}
+		if strings.Contains(code, "func (m *Time)") || strings.Contains(code, "func DiffTime(") {
+			t.Errorf("%s: no method can be declared on the overridden type:\n%s", name, code)
+		}
+	}
+}
+
+func TestOverrideMethods(t *testing.T) {
+	gitRepo := "github.com/kubewarden/k8s-objects"
+	plan := newRegistryTestPlan(t, openapi_spec.Definitions{
+		"io.k8s.apimachinery.pkg.apis.meta.v1.Time": stringProperty("date-time"),
+		"io.k8s.apimachinery.pkg.apis.meta.v1.Status": objectSchema(map[string]openapi_spec.Schema{
+			"since": refProperty("io.k8s.apimachinery.pkg.apis.meta.v1.Time"),
+		}),
+	})
+	err := plan.ApplyTypeOverrides(swagger_helpers.TypeOverrides{
+		"io.k8s.apimachinery.pkg.apis.meta.v1.Time": {Import: "github.com/example/types", Type: "Time", DeepCopy: true, Equal: true},
+	})
+	if err != nil {
+		t.Fatalf("cannot apply overrides: %v", err)
+	}
+
+	pkg := plan.Packages["apimachinery/pkg/apis/meta/v1"]
+	resolver := swagger_helpers.NewGoTypeResolver(plan.Definitions, &plan.Interfaces, gitRepo)
+	renderers := map[string]struct {
+		render   func() ([]byte, error)
+		expected string
+	}{
+		"DeepCopy": {
+			func() ([]byte, error) { return renderDeepCopy(pkg, &resolver) },
+			"(*in).DeepCopyInto(*out)",
+		},
+		"Equal": {
+			func() ([]byte, error) { return renderEqual(pkg, &resolver) },
+			"if m.Since != nil && !m.Since.Equal(other.Since) {",
+		},
+		"JSON patch": {
+			func() ([]byte, error) { return renderJSONPatchDiffs(pkg, &resolver, plan.Definitions, gitRepo) },
+			"if !((&*m.Since).Equal(&*other.Since)) {",
+		},
+		"type override checks": {
+			func() ([]byte, error) { return renderTypeOverrideChecks(pkg, &plan.Interfaces, gitRepo, plan.Naming) },
+			"Equal(*github_com_example_types.Time) bool",
+		},
+	}
+	for name, renderer := range renderers {
+		contents, err := renderer.render()
+		if err != nil {
+			t.Errorf("cannot render %s: %v", name, err)
+			continue
+		}
+		code := string(contents)
+		if !strings.Contains(code, renderer.expected) {
+			t.Errorf("%s: the methods of the override must be used, cannot find %s:\n%s", name, renderer.expected, code)
+		}
+		if strings.Contains(code, "**out = **in") || strings.Contains(code, "*m.Since != *other.Since") {
+			t.Errorf("%s: the override must not be handled like a comparable value:\n%s", name, code)
+		}
+	}
+}
@@ -11,6 +11,10 @@ import (
 
 // Wrapper around a Swagger Definition
 type Definition struct {
+	// ID of the definition inside of the original swagger file, e.g.
+	// `io.k8s.api.core.v1.Pod`
+	ID string
+
 	// Original definition
 	SwaggerDefinition openapi_spec.Schema
 	// Name of the package where the object declared by this Definition is going
@@ -26,23 +30,24 @@ type Definition struct {
 	// `apimachinery/pkg/apis/meta/v1/ObjectMeta`, then this definition depends
 	// on `apimachinery/pkg/apis/meta/v1/`
 	dependencies mapset.Set
+
+	// names the packages and the types referenced by the definition
+	naming Naming
 }
 
-func NewDefinition(definition openapi_spec.Schema, id string) (*Definition, error) {
-	path := strings.TrimPrefix(id, "io.k8s.")
-	chunks := strings.Split(path, ".")
-	if len(chunks) < 2 {
-		return nil,
-			fmt.Errorf("cannot build definition refactoring plan: wrong number of chunks: %v", chunks)
+func NewDefinition(definition openapi_spec.Schema, id string, naming Naming) (*Definition, error) {
+	packageName, typeName, err := naming.SplitDefinitionID(id)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot build definition refactoring plan")
 	}
 
-	packageName := strings.Join(chunks[0:len(chunks)-1], "/")
-	typeName := chunks[len(chunks)-1]
 	plan := Definition{
+		ID:                id,
 		SwaggerDefinition: definition,
 		PackageName:       packageName,
 		TypeName:          typeName,
 		dependencies:      mapset.NewSet(),
+		naming:            naming,
 	}
 
 	if err := plan.computeDependencies(); err != nil {
@@ -53,96 +58,127 @@ func NewDefinition(definition openapi_spec.Schema, id string) (*Definition, erro
 }
 
 func (d *Definition) computeDependencies() error {
-	propImports := []PropertyImport{}
-
-	for name, property := range d.SwaggerDefinition.Properties {
-		propImport, err := NewPropertyImportFromRef(&property.SchemaProps.Ref)
-		if err != nil {
-			return errors.Wrapf(err,
-				"cannot parse ref pointer for property %s inside of %s/%s",
-				name, d.PackageName, d.TypeName)
-		}
-		if !propImport.IsEmpty() {
-			propImports = append(propImports, propImport)
-		}
-
-		if property.Items != nil && property.Items.Schema != nil {
-			propImport, err := NewPropertyImportFromRef(&property.Items.Schema.SchemaProps.Ref)
+	// refs can be nested at any depth: inside of arrays, maps, inline
+	// objects and compositions
+	return WalkSchema(&d.SwaggerDefinition, SchemaLocation{Required: true},
+		func(schema *openapi_spec.Schema, location SchemaLocation) error {
+			propImport, err := NewPropertyImportFromRef(&schema.SchemaProps.Ref, d.naming)
 			if err != nil {
 				return errors.Wrapf(err,
-					"cannot parse ref pointer for property item %s inside of %s/%s",
-					name, d.PackageName, d.TypeName)
-			}
-			if !propImport.IsEmpty() {
-				propImports = append(propImports, propImport)
+					"cannot parse ref pointer of %s inside of %s/%s",
+					location.Path, d.PackageName, d.TypeName)
 			}
-		}
 
-		if property.AdditionalProperties != nil {
-			propImport, err := NewPropertyImportFromRef(&property.AdditionalProperties.Schema.SchemaProps.Ref)
-			if err != nil {
-				return errors.Wrapf(err,
-					"cannot parse ref pointer for additional property %s inside of %s/%s",
-					name, d.PackageName, d.TypeName)
-			}
-			if !propImport.IsEmpty() {
-				propImports = append(propImports, propImport)
+			if !propImport.IsEmpty() && propImport.PackageName != d.PackageName {
+				d.dependencies.Add(propImport.PackageName)
 			}
-		}
-	}
+			return nil
+		})
+}
 
-	for _, propImport := range propImports {
-		if propImport.PackageName != d.PackageName {
-			d.dependencies.Add(propImport.PackageName)
+// Returns the IDs of the definitions referenced by the object properties,
+// indexed by property name. Arrays and maps are not taken into account
+func (d *Definition) ObjectReferences() map[string]string {
+	references := make(map[string]string)
+
+	for name, property := range d.SwaggerDefinition.Properties {
+		pointer := property.SchemaProps.Ref.GetPointer()
+		if pointer == nil || pointer.IsEmpty() {
+			continue
 		}
+		references[name] = strings.TrimPrefix(pointer.String(), "/definitions/")
 	}
 
-	return nil
+	return references
 }
 
-func (d *Definition) GeneratePatchedOpenAPIDef(gitRepo string, interfaces *InterfaceRegistry) (openapi_spec.Schema, error) {
-	definition := d.SwaggerDefinition
+func (d *Definition) GeneratePatchedOpenAPIDef(gitRepo string, interfaces *InterfaceRegistry, kubernetesVersion string) (openapi_spec.Schema, error) {
+	// The original definition must not be altered, it's still needed after
+	// the swagger files are rendered
+	definition, err := cloneSchema(&d.SwaggerDefinition)
+	if err != nil {
+		return openapi_spec.Schema{}, errors.Wrapf(err, "cannot copy definition %s", d.ID)
+	}
+
+	if override, found := interfaces.Override(gitRepo, d.PackageName, d.TypeName); found {
+		// The type is not generated by swagger, it's either defined by
+		// another package or written by the generator later on. Refs to it
+		// are turned into imports by `patchSchemaRef`
+		definition.VendorExtensible.AddExtension("x-go-type", override.ToMap(d.naming, gitRepo, d.PackageName))
+		return definition, nil
+	}
 
 	if interfaces.IsInterface(gitRepo, d.PackageName, d.TypeName) {
 		// This is an interface, we have to generate not an `{}interface` but
 		// a `easyjson.RawMessage`. Interfaces cannot be handled neither by TinyGo,
 		// nor by easyjson. We can use instead a `easyjson.RawMessage` which doesn't
 		// cause panics at runtime.
-		outerObj := make(map[string]interface{})
-
-		importObj := make(map[string]string)
-		importObj["package"] = "github.com/mailru/easyjson"
-
-		outerObj["import"] = importObj
-		outerObj["type"] = "RawMessage"
-
-		definition.VendorExtensible.AddExtension("x-go-type", outerObj)
+		definition.VendorExtensible.AddExtension("x-go-type", rawMessageExtension())
 		return definition, nil
 	}
 
-	required := mapset.NewSet()
-	for _, r := range d.SwaggerDefinition.Required {
-		required.Add(r)
+	// emit `Deprecated:` paragraphs, these are recognized by linters
+	definition.Description = addDeprecationParagraph(definition.Description, d.DeprecationNotice(kubernetesVersion))
+
+	// Replace the refs found at any depth: properties, array items, map
+	// values, inline objects and compositions
+	err = WalkSchema(&definition, SchemaLocation{Required: true},
+		func(schema *openapi_spec.Schema, location SchemaLocation) error {
+			if location.Kind == RootLocation {
+				return nil
+			}
+
+			// patched refs look like opaque schemas
+			opaque := location.Kind != CompositionLocation && IsOpaqueSchema(schema)
+			if err := patchSchemaRef(schema, d.PackageName, interfaces, location.Required, gitRepo, d.naming); err != nil {
+				return errors.Wrapf(err, "cannot patch %s of %s", location.Path, d.ID)
+			}
+			if isBrokenReference(schema) || opaque {
+				// either the reference has been removed to break a
+				// dependency cycle or the schema can only be mapped to
+				// `interface{}`, the object is kept as a raw JSON
+				// document. Like interfaces it must not be referenced by
+				// pointer
+				schema.VendorExtensible.AddExtension("x-go-type", rawMessageExtension())
+				schema.VendorExtensible.AddExtension("x-nullable", false)
+			}
+			return nil
+		})
+	if err != nil {
+		return openapi_spec.Schema{}, err
 	}
 
 	for name := range definition.Properties {
 		property := definition.Properties[name]
-		isRequired := required.Contains(name)
 
-		if err := patchSchemaRef(&property, d.PackageName, interfaces, isRequired, gitRepo); err != nil {
-			return openapi_spec.Schema{}, err
+		property.Description = addDeprecationParagraph(
+			property.Description,
+			deprecationNoticeFromDescription(property.Description))
+
+		if isEmbeddedResource(&property) {
+			// the object can be of any kind, it's kept as a raw JSON
+			// document like RawExtension, which is never referenced by
+			// pointer
+			property.VendorExtensible.AddExtension("x-go-type", rawMessageExtension())
+			property.VendorExtensible.AddExtension("x-nullable", false)
 		}
 
-		if property.Items != nil && property.Items.Schema != nil {
-			if err := patchSchemaRef(property.Items.Schema, d.PackageName, interfaces, isRequired, gitRepo); err != nil {
-				return openapi_spec.Schema{}, err
-			}
+		if d.isTypeMetaProperty(name) {
+			// these fields are handled by the generated GroupVersionKind
+			// helpers, they must never be referenced by pointer
+			property.VendorExtensible.AddExtension("x-nullable", false)
 		}
 
-		if property.AdditionalProperties != nil {
-			if err := patchSchemaRef(property.AdditionalProperties.Schema, d.PackageName, interfaces, isRequired, gitRepo); err != nil {
-				return openapi_spec.Schema{}, err
-			}
+		if d.isObjectMetaProperty(name) {
+			// the generated `Object` interface deals with a pointer
+			property.VendorExtensible.AddExtension("x-nullable", true)
+		}
+
+		// enums are turned into named string types, this must be done
+		// after the refs have been patched: these types must not be
+		// referenced by pointer, unless they are required
+		if err := d.patchEnumProperty(name, &property); err != nil {
+			return openapi_spec.Schema{}, err
 		}
 
 		definition.Properties[name] = property
@@ -158,8 +194,9 @@ func patchSchemaRef(schema *openapi_spec.Schema,
 	interfaces *InterfaceRegistry,
 	isRequired bool,
 	gitRepo string,
+	naming Naming,
 ) error {
-	propImport, err := NewPropertyImportFromRef(&schema.SchemaProps.Ref)
+	propImport, err := NewPropertyImportFromRef(&schema.SchemaProps.Ref, naming)
 	if err != nil {
 		return err
 	}
@@ -190,6 +227,15 @@ func patchSchemaRef(schema *openapi_spec.Schema,
 		return nil
 	}
 
+	override, overridden := interfaces.Override(gitRepo, propImport.PackageName, propImport.TypeName)
+	if overridden && (override.Import != "" || propImport.PackageName != definitionPackage) {
+		// The type is not generated by swagger, the overrides are consulted
+		// before any other rule
+		schema.SchemaProps.Ref = openapi_spec.Ref{}
+		schema.VendorExtensible.AddExtension("x-go-type", override.ToMap(naming, gitRepo, propImport.PackageName))
+		return nil
+	}
+
 	if propImport.PackageName == definitionPackage {
 		// A definition from the same namespace is being referenced, we have to update
 		// the ref to link to the new ID of the resource
@@ -214,16 +260,7 @@ func patchSchemaRef(schema *openapi_spec.Schema,
 			// is defined inside of another package, hence we cannot rely on swagger
 			// to automatically change the object type to be `easyjson.RawMessage`,
 			// we have to handle that on our own.
-
-			outerObj := make(map[string]interface{})
-
-			importObj := make(map[string]string)
-			importObj["package"] = "github.com/mailru/easyjson"
-
-			outerObj["import"] = importObj
-			outerObj["type"] = "RawMessage"
-
-			schema.VendorExtensible.AddExtension("x-go-type", outerObj)
+			schema.VendorExtensible.AddExtension("x-go-type", rawMessageExtension())
 		} else {
 			schema.VendorExtensible.AddExtension("x-go-type", propImport.ToMap(gitRepo))
 		}
@@ -231,3 +268,30 @@ func patchSchemaRef(schema *openapi_spec.Schema,
 
 	return nil
 }
+
+// Returns the value of the `x-go-type` extension that maps a schema to
+// `easyjson.RawMessage`
+func rawMessageExtension() map[string]interface{} {
+	outerObj := make(map[string]interface{})
+
+	importObj := make(map[string]string)
+	importObj["package"] = "github.com/mailru/easyjson"
+
+	outerObj["import"] = importObj
+	outerObj["type"] = "RawMessage"
+
+	return outerObj
+}
+
+// Returns a deep copy of the given schema
+func cloneSchema(schema *openapi_spec.Schema) (openapi_spec.Schema, error) {
+	clone := openapi_spec.Schema{}
+
+	data, err := schema.MarshalJSON()
+	if err != nil {
+		return clone, err
+	}
+	err = clone.UnmarshalJSON(data)
+
+	return clone, err
+}
@@ -28,7 +28,7 @@ func TestParseDefinitionID(t *testing.T) {
 	emptySchema := openapi_spec.Schema{}
 
 	for _, testCase := range cases {
-		definition, err := NewDefinition(emptySchema, testCase.id)
+		definition, err := NewDefinition(emptySchema, testCase.id, Naming{})
 		if err != nil {
 			t.Errorf("unexpected error while parsing %s: %v", testCase.id, err)
 		}
@@ -103,7 +103,7 @@ func TestComputeDependenciesSimpleSchema(t *testing.T) {
 		}
 
 		definition, err := NewDefinition(defSchema,
-			"io.k8s.api.admissionregistration.v1.MutatingWebhook",
+			"io.k8s.api.admissionregistration.v1.MutatingWebhook", Naming{},
 		)
 		if err != nil {
 			t.Errorf("cannot generate definition: %v", err)
@@ -169,7 +169,7 @@ func TestComputeDependenciesSchemaWithAdditionalProperties(t *testing.T) {
 		}
 
 		definition, err := NewDefinition(defSchema,
-			"io.k8s.api.admissionregistration.v1.MutatingWebhook",
+			"io.k8s.api.admissionregistration.v1.MutatingWebhook", Naming{},
 		)
 		if err != nil {
 			t.Errorf("cannot generate definition: %v", err)
@@ -235,7 +235,7 @@ func TestComputeDependenciesSchemaWithItems(t *testing.T) {
 		}
 
 		definition, err := NewDefinition(defSchema,
-			"io.k8s.api.admissionregistration.v1.MutatingWebhook",
+			"io.k8s.api.admissionregistration.v1.MutatingWebhook", Naming{},
 		)
 		if err != nil {
 			t.Errorf("cannot generate definition: %v", err)
@@ -327,7 +327,7 @@ func TestPatchSchema(t *testing.T) {
 	}
 
 	definition, err := NewDefinition(defSchema,
-		"io.k8s.api.admissionregistration.v1.MutatingWebhook",
+		"io.k8s.api.admissionregistration.v1.MutatingWebhook", Naming{},
 	)
 	if err != nil {
 		t.Errorf("cannot generate definition: %v", err)
@@ -335,7 +335,8 @@ func TestPatchSchema(t *testing.T) {
 
 	patchedSchema, err := definition.GeneratePatchedOpenAPIDef(
 		"github.com/kubewarden/k8s-objects",
-		&interfaces)
+		&interfaces,
+		"")
 	if err != nil {
 		t.Errorf("cannot generate patched schema: %v", err)
 	}
@@ -0,0 +1,127 @@
+package swagger_helpers
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/blang/semver/v4"
+)
+
+// API versions that are deprecated and are no longer served by recent
+// Kubernetes releases. The definitions are matched by the prefix of their
+// ID, the first matching entry wins. The deprecation release is the one that
+// deprecated the API version, the removal one stopped serving its last kind.
+var removedAPIVersions = []struct {
+	idPrefix     string
+	apiVersion   string
+	deprecatedIn string
+	removedIn    string
+}{
+	{"io.k8s.api.admissionregistration.v1beta1.", "admissionregistration.k8s.io/v1beta1", "1.16", "1.22"},
+	{"io.k8s.api.apps.v1beta1.", "apps/v1beta1", "1.9", "1.16"},
+	{"io.k8s.api.apps.v1beta2.", "apps/v1beta2", "1.9", "1.16"},
+	{"io.k8s.api.authentication.v1beta1.", "authentication.k8s.io/v1beta1", "1.19", "1.22"},
+	{"io.k8s.api.authorization.v1beta1.", "authorization.k8s.io/v1beta1", "1.19", "1.22"},
+	{"io.k8s.api.autoscaling.v2beta1.", "autoscaling/v2beta1", "1.22", "1.25"},
+	{"io.k8s.api.autoscaling.v2beta2.", "autoscaling/v2beta2", "1.23", "1.26"},
+	{"io.k8s.api.batch.v1beta1.", "batch/v1beta1", "1.21", "1.25"},
+	{"io.k8s.api.batch.v2alpha1.", "batch/v2alpha1", "1.8", "1.21"},
+	{"io.k8s.api.certificates.v1beta1.", "certificates.k8s.io/v1beta1", "1.19", "1.22"},
+	{"io.k8s.api.coordination.v1beta1.", "coordination.k8s.io/v1beta1", "1.19", "1.22"},
+	{"io.k8s.api.discovery.v1beta1.", "discovery.k8s.io/v1beta1", "1.21", "1.25"},
+	{"io.k8s.api.events.v1beta1.", "events.k8s.io/v1beta1", "1.19", "1.25"},
+	{"io.k8s.api.extensions.v1beta1.", "extensions/v1beta1", "1.14", "1.22"},
+	{"io.k8s.api.flowcontrol.v1beta1.", "flowcontrol.apiserver.k8s.io/v1beta1", "1.23", "1.26"},
+	{"io.k8s.api.flowcontrol.v1beta2.", "flowcontrol.apiserver.k8s.io/v1beta2", "1.26", "1.29"},
+	{"io.k8s.api.networking.v1beta1.", "networking.k8s.io/v1beta1", "1.19", "1.22"},
+	{"io.k8s.api.node.v1beta1.", "node.k8s.io/v1beta1", "1.22", "1.25"},
+	{"io.k8s.api.policy.v1beta1.", "policy/v1beta1", "1.21", "1.25"},
+	{"io.k8s.api.rbac.v1beta1.", "rbac.authorization.k8s.io/v1beta1", "1.17", "1.22"},
+	{"io.k8s.api.scheduling.v1beta1.", "scheduling.k8s.io/v1beta1", "1.17", "1.22"},
+	{"io.k8s.api.settings.v1alpha1.", "settings.k8s.io/v1alpha1", "1.19", "1.20"},
+	// added to storage.k8s.io/v1beta1 long after the other kinds
+	{"io.k8s.api.storage.v1beta1.CSIStorageCapacity", "storage.k8s.io/v1beta1", "1.24", "1.27"},
+	{"io.k8s.api.storage.v1beta1.", "storage.k8s.io/v1beta1", "1.19", "1.22"},
+	{"io.k8s.apiextensions-apiserver.pkg.apis.apiextensions.v1beta1.", "apiextensions.k8s.io/v1beta1", "1.16", "1.22"},
+	{"io.k8s.kube-aggregator.pkg.apis.apiregistration.v1beta1.", "apiregistration.k8s.io/v1beta1", "1.19", "1.22"},
+}
+
+// Matches the `Deprecated: ` notices used by Kubernetes. They start a
+// paragraph, or a sentence since the lines of a Go comment are joined when
+// the swagger file is generated
+var deprecationNoticeRegexp = regexp.MustCompile(`(^|\n|\. )Deprecated: `)
+
+// Matches the paragraphs recognized as deprecation notices by Go tooling
+var deprecationParagraphRegexp = regexp.MustCompile(`(^|\n)Deprecated: `)
+
+// Returns the deprecation notice of the definition, an empty string when the
+// definition is not deprecated. The API versions removed by later releases
+// are deprecated only when the given Kubernetes version is known and has
+// already deprecated them
+func (d *Definition) DeprecationNotice(kubernetesVersion string) string {
+	if version, err := semver.ParseTolerant(kubernetesVersion); err == nil {
+		for _, removed := range removedAPIVersions {
+			if !strings.HasPrefix(d.ID, removed.idPrefix) {
+				continue
+			}
+			if version.GTE(semver.MustParse(removed.deprecatedIn + ".0")) {
+				return fmt.Sprintf("the %s API version is no longer served as of Kubernetes v%s.",
+					removed.apiVersion, removed.removedIn)
+			}
+			break
+		}
+	}
+
+	return deprecationNoticeFromDescription(d.SwaggerDefinition.Description)
+}
+
+// Looks for a `Deprecated: ` notice inside of the given description. Returns
+// its first sentence, an empty string when nothing is found
+func deprecationNoticeFromDescription(description string) string {
+	loc := deprecationNoticeRegexp.FindStringIndex(description)
+	if loc == nil {
+		return ""
+	}
+
+	paragraph := description[loc[1]:]
+	if end := strings.Index(paragraph, "\n"); end != -1 {
+		paragraph = paragraph[:end]
+	}
+	return firstSentence(paragraph)
+}
+
+func splitSentences(text string) []string {
+	sentences := []string{}
+	for _, chunk := range strings.SplitAfter(text, ". ") {
+		if sentence := strings.TrimSpace(chunk); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+	}
+	return sentences
+}
+
+func firstSentence(text string) string {
+	sentences := splitSentences(text)
+	if len(sentences) == 0 {
+		return ""
+	}
+	return sentences[0]
+}
+
+// Appends the deprecation notice to the description, using the paragraph
+// format recognized by Go tooling (`Deprecated: ...`). The description is
+// returned untouched when the notice is empty or when the description already
+// has such a paragraph
+func addDeprecationParagraph(description, notice string) string {
+	if notice == "" || deprecationParagraphRegexp.MatchString(description) {
+		return description
+	}
+
+	paragraph := fmt.Sprintf("Deprecated: %s", notice)
+	if description == "" {
+		return paragraph
+	}
+
+	return fmt.Sprintf("%s\n\n%s", description, paragraph)
+}
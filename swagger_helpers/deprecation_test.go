@@ -0,0 +1,182 @@
+package swagger_helpers
+
+import (
+	"testing"
+
+	openapi_spec "github.com/go-openapi/spec"
+)
+
+func TestDeprecationNoticeFromDescription(t *testing.T) {
+	cases := []struct {
+		description    string
+		expectedNotice string
+	}{
+		{
+			description:    "DeprecatedServiceAccount is a depreciated alias for ServiceAccountName. Deprecated: Use serviceAccountName instead.",
+			expectedNotice: "Use serviceAccountName instead.",
+		},
+		{
+			description:    "ClusterName is a legacy field.\n\nDeprecated: ClusterName will be removed in 1.25. It is always cleared by the system.",
+			expectedNotice: "ClusterName will be removed in 1.25.",
+		},
+		// prose mentioning deprecation is not a notice
+		{
+			description:    "DEPRECATED - This group version of Deployment is deprecated by apps/v1/Deployment. See the release notes for more information.",
+			expectedNotice: "",
+		},
+		{
+			description:    "PodSecurityPolicy governs the ability to make requests that affect the Security Context. Deprecated in 1.21.",
+			expectedNotice: "",
+		},
+		{
+			description:    "The ttl field is deprecated and ignored. Use expiration instead.",
+			expectedNotice: "",
+		},
+		{
+			description:    "Lists the fields that are not deprecated: name and namespace.",
+			expectedNotice: "",
+		},
+		{
+			description:    "ServiceAccountName is the name of the ServiceAccount to use to run this pod.",
+			expectedNotice: "",
+		},
+		{
+			description:    "",
+			expectedNotice: "",
+		},
+	}
+
+	for _, testCase := range cases {
+		notice := deprecationNoticeFromDescription(testCase.description)
+		if notice != testCase.expectedNotice {
+			t.Errorf("wrong notice for %q: expected %q got %q instead",
+				testCase.description, testCase.expectedNotice, notice)
+		}
+	}
+}
+
+func TestDeprecationNoticeRemovedAPIVersions(t *testing.T) {
+	cases := []struct {
+		id                string
+		kubernetesVersion string
+		expectedNotice    string
+	}{
+		{"io.k8s.api.extensions.v1beta1.Ingress", "1.21", "the extensions/v1beta1 API version is no longer served as of Kubernetes v1.22."},
+		{"io.k8s.api.extensions.v1beta1.Ingress", "v1.14.0", "the extensions/v1beta1 API version is no longer served as of Kubernetes v1.22."},
+		// not deprecated yet by the release of the spec
+		{"io.k8s.api.extensions.v1beta1.Ingress", "1.13", ""},
+		{"io.k8s.api.flowcontrol.v1beta2.FlowSchema", "1.23", ""},
+		{"io.k8s.api.flowcontrol.v1beta2.FlowSchema", "1.26", "the flowcontrol.apiserver.k8s.io/v1beta2 API version is no longer served as of Kubernetes v1.29."},
+		{"io.k8s.api.storage.v1beta1.CSIStorageCapacity", "1.21", ""},
+		{"io.k8s.api.storage.v1beta1.StorageClass", "1.21", "the storage.k8s.io/v1beta1 API version is no longer served as of Kubernetes v1.22."},
+		// the version of the spec is unknown
+		{"io.k8s.api.extensions.v1beta1.Ingress", "", ""},
+		{"io.k8s.api.extensions.v1beta1.Ingress", "undefined", ""},
+		{"io.k8s.api.networking.v1.Ingress", "1.21", ""},
+	}
+
+	for _, testCase := range cases {
+		definition, err := NewDefinition(openapi_spec.Schema{}, testCase.id, Naming{})
+		if err != nil {
+			t.Fatalf("cannot generate definition: %v", err)
+		}
+		notice := definition.DeprecationNotice(testCase.kubernetesVersion)
+		if notice != testCase.expectedNotice {
+			t.Errorf("wrong notice for %s with Kubernetes %q: expected %q got %q instead",
+				testCase.id, testCase.kubernetesVersion, testCase.expectedNotice, notice)
+		}
+	}
+}
+
+func TestAddDeprecationParagraph(t *testing.T) {
+	cases := []struct {
+		description string
+		notice      string
+		expected    string
+	}{
+		{
+			description: "Some field.",
+			notice:      "Use other instead.",
+			expected:    "Some field.\n\nDeprecated: Use other instead.",
+		},
+		{
+			description: "",
+			notice:      "Use other instead.",
+			expected:    "Deprecated: Use other instead.",
+		},
+		{
+			description: "Some field.",
+			notice:      "",
+			expected:    "Some field.",
+		},
+		{
+			description: "Deprecated: ClusterName is a legacy field.",
+			notice:      "ClusterName is a legacy field.",
+			expected:    "Deprecated: ClusterName is a legacy field.",
+		},
+		{
+			description: "Some field.\n\nDeprecated: Use other instead.",
+			notice:      "Use other instead.",
+			expected:    "Some field.\n\nDeprecated: Use other instead.",
+		},
+	}
+
+	for _, testCase := range cases {
+		description := addDeprecationParagraph(testCase.description, testCase.notice)
+		if description != testCase.expected {
+			t.Errorf("expected %q got %q instead", testCase.expected, description)
+		}
+	}
+}
+
+func TestPatchSchemaDeprecation(t *testing.T) {
+	interfaces := NewInterfaceRegistry()
+
+	defSchema := openapi_spec.Schema{
+		SchemaProps: openapi_spec.SchemaProps{
+			Description: "Ingress is a collection of rules.",
+			Properties: map[string]openapi_spec.Schema{
+				"selfLink": {
+					SchemaProps: openapi_spec.SchemaProps{
+						Description: "Deprecated: selfLink is a legacy read-only field.",
+						Type:        []string{"string"},
+					},
+				},
+				"serviceAccount": {
+					SchemaProps: openapi_spec.SchemaProps{
+						Description: "Alias. Deprecated: Use serviceAccountName instead.",
+						Type:        []string{"string"},
+					},
+				},
+			},
+		},
+	}
+
+	definition, err := NewDefinition(defSchema, "io.k8s.api.extensions.v1beta1.Ingress", Naming{})
+	if err != nil {
+		t.Fatalf("cannot generate definition: %v", err)
+	}
+
+	patchedSchema, err := definition.GeneratePatchedOpenAPIDef(
+		"github.com/kubewarden/k8s-objects",
+		&interfaces,
+		"v1.21.0")
+	if err != nil {
+		t.Fatalf("cannot generate patched schema: %v", err)
+	}
+
+	expectedDescription := "Ingress is a collection of rules.\n\nDeprecated: the extensions/v1beta1 API version is no longer served as of Kubernetes v1.22."
+	if patchedSchema.Description != expectedDescription {
+		t.Errorf("wrong definition description: %q", patchedSchema.Description)
+	}
+
+	expectedPropDescriptions := map[string]string{
+		"selfLink":       "Deprecated: selfLink is a legacy read-only field.",
+		"serviceAccount": "Alias. Deprecated: Use serviceAccountName instead.\n\nDeprecated: Use serviceAccountName instead.",
+	}
+	for name, expected := range expectedPropDescriptions {
+		if patchedSchema.Properties[name].Description != expected {
+			t.Errorf("wrong description of property %s: %q", name, patchedSchema.Properties[name].Description)
+		}
+	}
+}
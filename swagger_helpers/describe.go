@@ -0,0 +1,44 @@
+package swagger_helpers
+
+import (
+	"fmt"
+	"strings"
+
+	openapi_spec "github.com/go-openapi/spec"
+)
+
+// Returns a short, human readable, description of the type defined by the
+// given schema. For example:
+// * `string`
+// * `integer(int32)`
+// * `[]api/core/v1.Container`
+// * `map[string]apimachinery/pkg/api/resource.Quantity`
+// This is used to detect and report type changes of the properties.
+func DescribeSchemaType(schema *openapi_spec.Schema, naming Naming) string {
+	refPointer := schema.SchemaProps.Ref.GetPointer()
+	if refPointer != nil && !refPointer.IsEmpty() {
+		propImport, err := NewPropertyImportFromRef(&schema.SchemaProps.Ref, naming)
+		if err != nil {
+			return strings.TrimPrefix(refPointer.String(), "/definitions/")
+		}
+		return fmt.Sprintf("%s.%s", propImport.PackageName, propImport.TypeName)
+	}
+
+	if schema.Items != nil && schema.Items.Schema != nil {
+		return "[]" + DescribeSchemaType(schema.Items.Schema, naming)
+	}
+
+	if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+		return "map[string]" + DescribeSchemaType(schema.AdditionalProperties.Schema, naming)
+	}
+
+	schemaType := "object"
+	if len(schema.Type) > 0 {
+		schemaType = schema.Type[0]
+	}
+	if schema.Format != "" {
+		return fmt.Sprintf("%s(%s)", schemaType, schema.Format)
+	}
+
+	return schemaType
+}
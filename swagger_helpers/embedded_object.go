@@ -0,0 +1,53 @@
+package swagger_helpers
+
+import (
+	"sort"
+	"strings"
+
+	openapi_spec "github.com/go-openapi/spec"
+)
+
+// ID of the RawExtension definition, which holds a whole object of any kind
+const RAW_EXTENSION_ID = "io.k8s.apimachinery.pkg.runtime.RawExtension"
+
+// Extension flagging the properties that hold a whole object, together with
+// its apiVersion and kind
+const EMBEDDED_RESOURCE_EXTENSION = "x-kubernetes-embedded-resource"
+
+// Returns true when the schema describes an inline object flagged with
+// `x-kubernetes-embedded-resource`. Like RawExtension, these objects are
+// turned into `easyjson.RawMessage`
+func isEmbeddedResource(schema *openapi_spec.Schema) bool {
+	embedded, _ := schema.Extensions.GetBool(EMBEDDED_RESOURCE_EXTENSION)
+	pointer := schema.SchemaProps.Ref.GetPointer()
+	return embedded && (pointer == nil || pointer.IsEmpty())
+}
+
+// Returns true when the schema holds a whole object, either through a
+// RawExtension or because it's an embedded resource
+func isEmbeddedObject(schema *openapi_spec.Schema) bool {
+	if isEmbeddedResource(schema) {
+		return true
+	}
+
+	pointer := schema.SchemaProps.Ref.GetPointer()
+	if pointer == nil || pointer.IsEmpty() {
+		return false
+	}
+	return strings.TrimPrefix(pointer.String(), "/definitions/") == RAW_EXTENSION_ID
+}
+
+// Returns, sorted by name, the properties of the definition that hold a whole
+// object of any kind. These are raw JSON documents, which can be decoded
+// through the kind registry
+func (d *Definition) EmbeddedObjectProperties() []string {
+	names := []string{}
+	for name, property := range d.SwaggerDefinition.Properties {
+		if isEmbeddedObject(&property) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,58 @@
+package swagger_helpers
+
+import (
+	"reflect"
+	"testing"
+
+	openapi_spec "github.com/go-openapi/spec"
+)
+
+func TestEmbeddedObjectProperties(t *testing.T) {
+	gitRepo := "github.com/kubewarden/k8s-objects"
+
+	embeddedResource := openapi_spec.Schema{SchemaProps: openapi_spec.SchemaProps{Type: []string{"object"}}}
+	embeddedResource.AddExtension(EMBEDDED_RESOURCE_EXTENSION, true)
+
+	definitions := newTestDefinitions(t, map[string]openapi_spec.Schema{
+		RAW_EXTENSION_ID: {SchemaProps: openapi_spec.SchemaProps{Type: []string{"object"}}},
+		"io.k8s.api.example.v1.Wrapper": {SchemaProps: openapi_spec.SchemaProps{
+			Properties: map[string]openapi_spec.Schema{
+				"raw":      *openapi_spec.RefProperty("#/definitions/" + RAW_EXTENSION_ID),
+				"embedded": embeddedResource,
+				"name":     *openapi_spec.StringProperty(),
+			},
+		}},
+	})
+	wrapper := definitions["io.k8s.api.example.v1.Wrapper"]
+
+	if names := wrapper.EmbeddedObjectProperties(); !reflect.DeepEqual(names, []string{"embedded", "raw"}) {
+		t.Errorf("wrong embedded object properties: %v", names)
+	}
+
+	interfaces := NewInterfaceRegistry()
+	interfaces.RegisterInterface("apimachinery/pkg/runtime", "RawExtension")
+
+	patchedSchema, err := wrapper.GeneratePatchedOpenAPIDef(gitRepo, &interfaces, "")
+	if err != nil {
+		t.Fatalf("cannot generate patched schema: %v", err)
+	}
+	embedded := patchedSchema.Properties["embedded"]
+	goType, found := embedded.Extensions["x-go-type"].(map[string]interface{})
+	if !found || goType["type"] != "RawMessage" {
+		t.Errorf("embedded resources must be raw messages: %v", embedded.Extensions)
+	}
+	if nullable, _ := embedded.Extensions.GetBool("x-nullable"); nullable {
+		t.Error("embedded resources must not be pointers")
+	}
+
+	resolver := NewGoTypeResolver(definitions, &interfaces, gitRepo)
+	for _, name := range []string{"embedded", "raw"} {
+		propertyType, err := resolver.PropertyType(wrapper, name)
+		if err != nil {
+			t.Fatalf("cannot resolve type of %s: %v", name, err)
+		}
+		if propertyType.Expr() != "easyjson.RawMessage" {
+			t.Errorf("%s: expected easyjson.RawMessage, got %s", name, propertyType.Expr())
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package swagger_helpers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	openapi_spec "github.com/go-openapi/spec"
+	"github.com/go-openapi/swag"
+)
+
+// Returns true when the schema is a string that can assume only a fixed
+// set of values
+func isStringEnum(schema *openapi_spec.Schema) bool {
+	if len(schema.Enum) == 0 || len(schema.Type) != 1 || schema.Type[0] != "string" {
+		return false
+	}
+
+	for _, value := range schema.Enum {
+		if _, ok := value.(string); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Extension holding the prefix of the constants generated for the values of
+// an enum, the name of the type is used when it's not set
+const ENUM_CONSTANT_PREFIX_EXTENSION = "x-enum-constant-prefix"
+
+// Names of the Go type and of the constants generated for the enum of a
+// property
+type enumName struct {
+	// ID of the definition owning the property, empty to match the property
+	// of any definition
+	DefinitionID string
+	Property     string
+
+	TypeName string
+	// The constants are named after the prefix followed by the value
+	ConstantPrefix string
+}
+
+// Enums named like the types of the upstream Kubernetes packages, e.g.
+// `PullPolicy` and `PullAlways`. The properties of different definitions that
+// share a type name are generated once per package
+var upstreamEnumNames = []enumName{
+	{Property: "imagePullPolicy", TypeName: "PullPolicy", ConstantPrefix: "Pull"},
+	{Property: "protocol", TypeName: "Protocol", ConstantPrefix: "Protocol"},
+	{Property: "terminationMessagePolicy", TypeName: "TerminationMessagePolicy", ConstantPrefix: "TerminationMessage"},
+	{DefinitionID: "io.k8s.api.core.v1.PodSpec", Property: "restartPolicy", TypeName: "RestartPolicy", ConstantPrefix: "RestartPolicy"},
+	{DefinitionID: "io.k8s.api.core.v1.PodSpec", Property: "dnsPolicy", TypeName: "DNSPolicy", ConstantPrefix: "DNS"},
+	{DefinitionID: "io.k8s.api.core.v1.PodStatus", Property: "phase", TypeName: "PodPhase", ConstantPrefix: "Pod"},
+	{DefinitionID: "io.k8s.api.core.v1.ServiceSpec", Property: "type", TypeName: "ServiceType", ConstantPrefix: "ServiceType"},
+	{DefinitionID: "io.k8s.api.core.v1.Taint", Property: "effect", TypeName: "TaintEffect", ConstantPrefix: "TaintEffect"},
+	{DefinitionID: "io.k8s.api.core.v1.Toleration", Property: "effect", TypeName: "TaintEffect", ConstantPrefix: "TaintEffect"},
+	{DefinitionID: "io.k8s.api.core.v1.Toleration", Property: "operator", TypeName: "TolerationOperator", ConstantPrefix: "TolerationOp"},
+}
+
+// Returns the names generated for the enum of the given property. Enums
+// without an upstream name are named after the owning type followed by the
+// property, e.g. `ContainerCapabilities`, and so are their constants
+func (d *Definition) enumName(propertyName string) enumName {
+	for _, name := range upstreamEnumNames {
+		if name.Property == propertyName && (name.DefinitionID == "" || name.DefinitionID == d.ID) {
+			return name
+		}
+	}
+
+	typeName := d.TypeName + swag.ToGoName(propertyName)
+	return enumName{
+		DefinitionID:   d.ID,
+		Property:       propertyName,
+		TypeName:       typeName,
+		ConstantPrefix: typeName,
+	}
+}
+
+// Name of the type generated for the enum values of the given property,
+// e.g. `PullPolicy`
+func (d *Definition) enumTypeName(propertyName string) string {
+	return d.enumName(propertyName).TypeName
+}
+
+// Returns the schema of the enum attached either to the property or to its
+// items, nil when the property doesn't have an enum
+func enumSchema(property *openapi_spec.Schema) *openapi_spec.Schema {
+	if isStringEnum(property) {
+		return property
+	}
+	if property.Items != nil && property.Items.Schema != nil && isStringEnum(property.Items.Schema) {
+		return property.Items.Schema
+	}
+	return nil
+}
+
+// Returns the definitions of the named string types that are generated for
+// the enums of the properties. The definitions are indexed by type name.
+// Swagger generates typed constants and an `IsValid` method for each one of
+// them, the constants are prefixed by `x-enum-constant-prefix`.
+func (d *Definition) EnumDefinitions() map[string]openapi_spec.Schema {
+	definitions := make(map[string]openapi_spec.Schema)
+
+	for name := range d.SwaggerDefinition.Properties {
+		property := d.SwaggerDefinition.Properties[name]
+		schema := enumSchema(&property)
+		if schema == nil {
+			continue
+		}
+
+		enum := d.enumName(name)
+		enumDefinition := openapi_spec.Schema{
+			SchemaProps: openapi_spec.SchemaProps{
+				Description: fmt.Sprintf("%s is the type of the %s property of %s",
+					enum.TypeName, name, d.TypeName),
+				Type: []string{"string"},
+				Enum: schema.Enum,
+			},
+		}
+		enumDefinition.AddExtension(ENUM_CONSTANT_PREFIX_EXTENSION, enum.ConstantPrefix)
+		definitions[enum.TypeName] = enumDefinition
+	}
+
+	return definitions
+}
+
+// Replaces the enum of the property, or of its items, with a reference to the
+// type returned by `EnumDefinitions`
+func (d *Definition) patchEnumProperty(name string, property *openapi_spec.Schema) error {
+	schema := enumSchema(property)
+	if schema == nil {
+		return nil
+	}
+
+	ref, err := openapi_spec.NewRef(fmt.Sprintf("#/definitions/%s", d.enumTypeName(name)))
+	if err != nil {
+		return err
+	}
+
+	schema.SchemaProps.Ref = ref
+	schema.Type = nil
+	schema.Format = ""
+	schema.Enum = nil
+
+	return nil
+}
+
+// Adds to the enum the values of the other one that it doesn't hold yet, this
+// happens when properties of different definitions share the same type
+func mergeEnumDefinitions(enum, other openapi_spec.Schema) openapi_spec.Schema {
+	values := append([]interface{}{}, enum.Enum...)
+	for _, value := range other.Enum {
+		found := false
+		for _, known := range values {
+			if known == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			values = append(values, value)
+		}
+	}
+
+	enum.Enum = values
+	return enum
+}
+
+// Returns the name of the constant that swagger generates for the value of
+// the enum, it mirrors the `pascalize` and `cleanupEnumVariant` template
+// functions used by the `enumConstants` template
+func enumConstantName(prefix string, value string) string {
+	var variant strings.Builder
+	for _, r := range value {
+		switch r {
+		case '.':
+			variant.WriteString("-Dot-")
+		case '+':
+			variant.WriteString("-Plus-")
+		case '-':
+			variant.WriteString("-Dash-")
+		case '#':
+			variant.WriteString("-Hashtag-")
+		default:
+			variant.WriteRune(r)
+		}
+	}
+
+	name := variant.String()
+	if name == "" {
+		return prefix + "Empty"
+	}
+	return prefix + swag.ToGoName(swag.ToGoName(name))
+}
+
+// Ensures the constants generated for the values of the enums don't clash
+// with each other, nor with the types of the package, e.g. the `foo_bar` and
+// `FooBar` values of the same enum. The error names the enums involved
+func checkEnumConstants(pkgName string, definitions openapi_spec.Definitions, enums map[string]openapi_spec.Schema) error {
+	typeNames := make([]string, 0, len(enums))
+	for typeName := range enums {
+		typeNames = append(typeNames, typeName)
+	}
+	sort.Strings(typeNames)
+
+	constants := make(map[string]string)
+	for _, typeName := range typeNames {
+		enum := enums[typeName]
+		prefix, found := enum.Extensions.GetString(ENUM_CONSTANT_PREFIX_EXTENSION)
+		if !found {
+			prefix = typeName
+		}
+		for _, value := range enum.Enum {
+			name := enumConstantName(prefix, value.(string))
+			if other, found := constants[name]; found {
+				return fmt.Errorf("cannot generate constant %s/%s for the value %q of enum %s: it's already generated for enum %s",
+					pkgName, name, value, typeName, other)
+			}
+			if _, found := definitions[name]; found {
+				return fmt.Errorf("cannot generate constant %s/%s for the value %q of enum %s: the name is already taken by a type",
+					pkgName, name, value, typeName)
+			}
+			constants[name] = typeName
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,235 @@
+package swagger_helpers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	openapi_spec "github.com/go-openapi/spec"
+)
+
+func newEnumTestDefinition(t *testing.T) *Definition {
+	defSchema := openapi_spec.Schema{
+		SchemaProps: openapi_spec.SchemaProps{
+			Properties: map[string]openapi_spec.Schema{
+				"imagePullPolicy": {
+					SchemaProps: openapi_spec.SchemaProps{
+						Type: []string{"string"},
+						Enum: []interface{}{"Always", "IfNotPresent", "Never"},
+					},
+				},
+				"capabilities": {
+					SchemaProps: openapi_spec.SchemaProps{
+						Type: []string{"array"},
+						Items: &openapi_spec.SchemaOrArray{
+							Schema: &openapi_spec.Schema{
+								SchemaProps: openapi_spec.SchemaProps{
+									Type: []string{"string"},
+									Enum: []interface{}{"NET_ADMIN", "SYS_TIME"},
+								},
+							},
+						},
+					},
+				},
+				"replicas": {
+					SchemaProps: openapi_spec.SchemaProps{
+						Type: []string{"integer"},
+						Enum: []interface{}{1, 2},
+					},
+				},
+				"name": {
+					SchemaProps: openapi_spec.SchemaProps{
+						Type: []string{"string"},
+					},
+				},
+			},
+		},
+	}
+
+	definition, err := NewDefinition(defSchema, "io.k8s.api.core.v1.Container", Naming{})
+	if err != nil {
+		t.Fatalf("cannot generate definition: %v", err)
+	}
+	return definition
+}
+
+func TestEnumDefinitions(t *testing.T) {
+	definition := newEnumTestDefinition(t)
+
+	enums := definition.EnumDefinitions()
+	if len(enums) != 2 {
+		t.Fatalf("expected 2 enum definitions, got %d: %v", len(enums), enums)
+	}
+
+	expected := map[string][]interface{}{
+		"PullPolicy":            {"Always", "IfNotPresent", "Never"},
+		"ContainerCapabilities": {"NET_ADMIN", "SYS_TIME"},
+	}
+	prefixes := map[string]string{
+		// named like the upstream type, e.g. `PullAlways`
+		"PullPolicy":            "Pull",
+		"ContainerCapabilities": "ContainerCapabilities",
+	}
+	for name, values := range expected {
+		enum, found := enums[name]
+		if !found {
+			t.Errorf("cannot find enum definition %s", name)
+			continue
+		}
+		if !enum.Type.Contains("string") {
+			t.Errorf("enum %s is not a string: %v", name, enum.Type)
+		}
+		if !reflect.DeepEqual(enum.Enum, values) {
+			t.Errorf("wrong values for enum %s: %v", name, enum.Enum)
+		}
+		if prefix, _ := enum.Extensions.GetString(ENUM_CONSTANT_PREFIX_EXTENSION); prefix != prefixes[name] {
+			t.Errorf("wrong constant prefix for enum %s: %s", name, prefix)
+		}
+	}
+}
+
+func TestSharedEnumDefinitions(t *testing.T) {
+	protocol := func(values ...interface{}) openapi_spec.Schema {
+		return openapi_spec.Schema{
+			SchemaProps: openapi_spec.SchemaProps{
+				Properties: map[string]openapi_spec.Schema{
+					"protocol": {SchemaProps: openapi_spec.SchemaProps{Type: []string{"string"}, Enum: values}},
+				},
+			},
+		}
+	}
+
+	pkg := NewPackage("api/core/v1")
+	for id, schema := range map[string]openapi_spec.Schema{
+		"io.k8s.api.core.v1.ContainerPort": protocol("TCP", "UDP"),
+		"io.k8s.api.core.v1.ServicePort":   protocol("TCP", "SCTP"),
+	} {
+		definition, err := NewDefinition(schema, id, Naming{})
+		if err != nil {
+			t.Fatalf("cannot generate definition: %v", err)
+		}
+		pkg.AddDefinitionRefactoringPlan(definition)
+	}
+
+	interfaces := NewInterfaceRegistry()
+	swagger, err := pkg.GenerateSwagger("2.0", "1.24", "github.com/kubewarden/k8s-objects", &interfaces)
+	if err != nil {
+		t.Fatalf("cannot generate swagger: %v", err)
+	}
+
+	enum, found := swagger.Definitions["Protocol"]
+	if !found {
+		t.Fatalf("cannot find the shared enum definition: %v", swagger.Definitions)
+	}
+	if len(enum.Enum) != 3 {
+		t.Errorf("the values of all the properties must be merged: %v", enum.Enum)
+	}
+}
+
+func TestPatchSchemaEnum(t *testing.T) {
+	interfaces := NewInterfaceRegistry()
+	definition := newEnumTestDefinition(t)
+
+	patchedSchema, err := definition.GeneratePatchedOpenAPIDef(
+		"github.com/kubewarden/k8s-objects",
+		&interfaces,
+		"")
+	if err != nil {
+		t.Fatalf("cannot generate patched schema: %v", err)
+	}
+
+	policy := patchedSchema.Properties["imagePullPolicy"]
+	if policy.Ref.String() != "#/definitions/PullPolicy" {
+		t.Errorf("wrong ref for imagePullPolicy: %s", policy.Ref.String())
+	}
+	if len(policy.Enum) != 0 || len(policy.Type) != 0 {
+		t.Errorf("enum and type of imagePullPolicy have not been removed: %v", policy)
+	}
+	if nullable, _ := policy.Extensions.GetBool("x-nullable"); nullable {
+		t.Error("enum property must not be nullable")
+	}
+
+	capabilities := patchedSchema.Properties["capabilities"]
+	if capabilities.Items.Schema.Ref.String() != "#/definitions/ContainerCapabilities" {
+		t.Errorf("wrong ref for capabilities items: %s", capabilities.Items.Schema.Ref.String())
+	}
+	if !capabilities.Type.Contains("array") {
+		t.Errorf("capabilities is no longer an array: %v", capabilities.Type)
+	}
+
+	replicas := patchedSchema.Properties["replicas"]
+	if replicas.Ref.String() != "" || len(replicas.Enum) != 2 {
+		t.Errorf("non-string enum must be left untouched: %v", replicas)
+	}
+
+	// the original definition is not changed
+	original := definition.SwaggerDefinition.Properties["imagePullPolicy"]
+	if len(original.Enum) != 3 {
+		t.Errorf("original definition has been altered: %v", original)
+	}
+}
+
+func TestEnumConstantName(t *testing.T) {
+	cases := map[string]string{
+		"IfNotPresent": "PullIfNotPresent",
+		"NET_ADMIN":    "PullNETADMIN",
+		"foo-bar":      "PullFooDashBar",
+		"v1.2":         "PullV1Dot2",
+		"":             "PullEmpty",
+	}
+	for value, expected := range cases {
+		if name := enumConstantName("Pull", value); name != expected {
+			t.Errorf("wrong constant name for %q: %s, expected %s", value, name, expected)
+		}
+	}
+}
+
+func TestEnumConstantsCollisions(t *testing.T) {
+	enumProperty := func(values ...interface{}) openapi_spec.Schema {
+		return openapi_spec.Schema{SchemaProps: openapi_spec.SchemaProps{Type: []string{"string"}, Enum: values}}
+	}
+	cases := []struct {
+		name        string
+		definitions map[string]openapi_spec.Schema
+		expected    string
+	}{
+		{
+			name: "values of the same enum",
+			definitions: map[string]openapi_spec.Schema{
+				"io.k8s.api.core.v1.Container": {SchemaProps: openapi_spec.SchemaProps{
+					Properties: map[string]openapi_spec.Schema{"imagePullPolicy": enumProperty("foo_bar", "FooBar")},
+				}},
+			},
+			expected: "of enum PullPolicy: it's already generated for enum PullPolicy",
+		},
+		{
+			name: "type of the package",
+			definitions: map[string]openapi_spec.Schema{
+				"io.k8s.api.core.v1.Container": {SchemaProps: openapi_spec.SchemaProps{
+					Properties: map[string]openapi_spec.Schema{"imagePullPolicy": enumProperty("Secret")},
+				}},
+				"io.k8s.api.core.v1.PullSecret": {SchemaProps: openapi_spec.SchemaProps{
+					Properties: map[string]openapi_spec.Schema{"name": {SchemaProps: openapi_spec.SchemaProps{Type: []string{"string"}}}},
+				}},
+			},
+			expected: "cannot generate constant api/core/v1/PullSecret for the value \"Secret\" of enum PullPolicy: the name is already taken by a type",
+		},
+	}
+
+	for _, c := range cases {
+		pkg := NewPackage("api/core/v1")
+		for id, schema := range c.definitions {
+			definition, err := NewDefinition(schema, id, Naming{})
+			if err != nil {
+				t.Fatalf("cannot generate definition: %v", err)
+			}
+			pkg.AddDefinitionRefactoringPlan(definition)
+		}
+
+		interfaces := NewInterfaceRegistry()
+		_, err := pkg.GenerateSwagger("2.0", "1.24", "github.com/kubewarden/k8s-objects", &interfaces)
+		if err == nil || !strings.Contains(err.Error(), c.expected) {
+			t.Errorf("%s: expected the clash to be reported, got %v", c.name, err)
+		}
+	}
+}
@@ -0,0 +1,358 @@
+package swagger_helpers
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	openapi_spec "github.com/go-openapi/spec"
+	"github.com/go-openapi/swag"
+)
+
+type GoTypeKind int
+
+const (
+	// types that can be compared with `==`, like strings, numbers and
+	// named string types, or that declare their own Equal method
+	ScalarGoType GoTypeKind = iota
+	// types based on `[]byte`, like `easyjson.RawMessage` and `strfmt.Base64`
+	BytesGoType
+	StructGoType
+	SliceGoType
+	MapGoType
+	// overrides whose shape is not known, they are handled through their
+	// JSON representation
+	OpaqueGoType
+)
+
+// Describes the Go type of a field generated by swagger
+type GoType struct {
+	Kind    GoTypeKind
+	Pointer bool
+
+	// Name of the type, qualified by the package alias when the type is
+	// defined inside of another package. Empty for unnamed slices and maps
+	Name string
+	// Import path and alias of the package defining the type
+	Import string
+	Alias  string
+
+	// Zero value of scalar types, e.g. `""` or `0`
+	Zero string
+	// Underlying basic type of scalar types, e.g. `string` for enums, empty
+	// when the underlying type is not a basic one
+	Underlying string
+
+	// Interface implemented by named types that are not basic ones to
+	// handle JSON, e.g. EASYJSON_MARSHALER for the generated types. Empty
+	// for `strfmt.Base64`, which is encoded like any `[]byte`
+	Marshaler string
+
+	// Element of slices and maps
+	Elem *GoType
+
+	// ID of the definition of struct types
+	DefinitionID string
+
+	// Set when the type is an override declaring the `DeepCopyInto(out *T)`
+	// and the `Equal(other *T) bool` methods
+	HasDeepCopy bool
+	HasEqual    bool
+}
+
+// Go expression of the type, e.g. `[]*api_core_v1.Container`
+func (t GoType) Expr() string {
+	prefix := ""
+	if t.Pointer {
+		prefix = "*"
+	}
+
+	if t.Name != "" {
+		return prefix + t.Name
+	}
+
+	switch t.Kind {
+	case SliceGoType:
+		return prefix + "[]" + t.Elem.Expr()
+	case MapGoType:
+		return prefix + "map[string]" + t.Elem.Expr()
+	default:
+		return prefix + "interface{}"
+	}
+}
+
+// Adds the packages required by the type to the given map, indexed by import
+// path
+func (t *GoType) CollectImports(imports map[string]string) {
+	if t.Import != "" {
+		imports[t.Import] = t.Alias
+	}
+	if t.Elem != nil {
+		t.Elem.CollectImports(imports)
+	}
+}
+
+// Returns the same type, without the pointer
+func (t GoType) Deref() GoType {
+	t.Pointer = false
+	return t
+}
+
+var primitiveGoTypes = map[string]GoType{
+	"boolean":       {Kind: ScalarGoType, Name: "bool", Zero: "false", Underlying: "bool"},
+	"integer":       {Kind: ScalarGoType, Name: "int64", Zero: "0", Underlying: "int64"},
+	"integer/int32": {Kind: ScalarGoType, Name: "int32", Zero: "0", Underlying: "int32"},
+	"integer/int64": {Kind: ScalarGoType, Name: "int64", Zero: "0", Underlying: "int64"},
+	"number":        {Kind: ScalarGoType, Name: "float64", Zero: "0", Underlying: "float64"},
+	"number/double": {Kind: ScalarGoType, Name: "float64", Zero: "0", Underlying: "float64"},
+	"number/float":  {Kind: ScalarGoType, Name: "float32", Zero: "0", Underlying: "float32"},
+	"string":        {Kind: ScalarGoType, Name: "string", Zero: `""`, Underlying: "string"},
+	"string/byte":   {Kind: BytesGoType, Name: "strfmt.Base64", Import: "github.com/go-openapi/strfmt", Alias: "strfmt"},
+	"string/date": {
+		Kind: ScalarGoType, Name: "strfmt.Date", Import: "github.com/go-openapi/strfmt", Alias: "strfmt", Zero: "strfmt.Date{}",
+		Marshaler: JSON_MARSHALER,
+	},
+	"string/date-time": {
+		Kind: ScalarGoType, Name: "strfmt.DateTime", Import: "github.com/go-openapi/strfmt", Alias: "strfmt", Zero: "strfmt.DateTime{}",
+		Marshaler: JSON_MARSHALER,
+	},
+}
+
+var rawMessageGoType = GoType{
+	Kind:      BytesGoType,
+	Name:      "easyjson.RawMessage",
+	Import:    "github.com/mailru/easyjson",
+	Alias:     "easyjson",
+	Marshaler: EASYJSON_MARSHALER,
+}
+
+// Computes the Go types of the fields generated by swagger
+type GoTypeResolver struct {
+	definitions map[string]*Definition
+	interfaces  *InterfaceRegistry
+	gitRepo     string
+}
+
+func NewGoTypeResolver(definitions map[string]*Definition, interfaces *InterfaceRegistry, gitRepo string) GoTypeResolver {
+	return GoTypeResolver{
+		definitions: definitions,
+		interfaces:  interfaces,
+		gitRepo:     gitRepo,
+	}
+}
+
+// Returns the Go type generated for the definition, as seen from the given
+// package. The kind is not `StructGoType` when the definition is mapped to
+// something else, like a named string type or an `easyjson.RawMessage`
+func (r *GoTypeResolver) DefinitionType(def *Definition, packageName string) (GoType, error) {
+	if override, found := r.interfaces.Override(r.gitRepo, def.PackageName, def.TypeName); found {
+		return r.overrideType(def, override, packageName), nil
+	}
+	if r.interfaces.IsInterface(r.gitRepo, def.PackageName, def.TypeName) {
+		return rawMessageGoType, nil
+	}
+
+	goType := GoType{Kind: StructGoType, DefinitionID: def.ID}
+	switch {
+	case len(def.SwaggerDefinition.Properties) == 0:
+		underlying, err := r.schemaType(def, "", &def.SwaggerDefinition, def.PackageName)
+		if err != nil {
+			return GoType{}, err
+		}
+		goType = GoType{
+			Kind:       underlying.Kind,
+			Underlying: underlying.Underlying,
+			Zero:       underlying.Zero,
+			Elem:       underlying.Elem,
+		}
+	}
+
+	// easyjson writes the JSON methods of all the generated types
+	goType.Name = swag.ToGoName(def.TypeName)
+	goType.Marshaler = EASYJSON_MARSHALER
+	if def.PackageName != packageName {
+		goType.Alias = def.naming.PackageAlias(def.PackageName)
+		goType.Import = filepath.Join(r.gitRepo, def.PackageName)
+		goType.Name = goType.Alias + "." + goType.Name
+	}
+	if goType.Kind == ScalarGoType && goType.Underlying == "" {
+		// named types based on a struct, like `strfmt.DateTime`
+		goType.Zero = goType.Name + "{}"
+	}
+
+	return goType, nil
+}
+
+// Returns the override of the definition, the boolean is false when the
+// definition is not overridden
+func (r *GoTypeResolver) Override(def *Definition) (TypeOverride, bool) {
+	return r.interfaces.Override(r.gitRepo, def.PackageName, def.TypeName)
+}
+
+// Returns the Go type replacing the definition. Overrides are handled like
+// comparable values, e.g. IntOrString is a struct made of scalars, unless
+// they declare their own DeepCopyInto and Equal methods or they are opaque
+func (r *GoTypeResolver) overrideType(def *Definition, override TypeOverride, packageName string) GoType {
+	goType := GoType{
+		Kind:        ScalarGoType,
+		Name:        override.Type,
+		Marshaler:   override.Marshaler,
+		HasDeepCopy: override.DeepCopy,
+		HasEqual:    override.Equal,
+	}
+	if goType.Marshaler == "" {
+		goType.Marshaler = EASYJSON_MARSHALER
+	}
+	if override.Import != "" || def.PackageName != packageName {
+		goType.Alias = override.ImportAlias(def.naming, def.PackageName)
+		goType.Import = override.ImportPath(r.gitRepo, def.PackageName)
+		goType.Name = goType.Alias + "." + goType.Name
+	}
+
+	goType.Zero = goType.Name + "{}"
+	switch {
+	case override.Kind == OPAQUE_OVERRIDE:
+		goType.Kind = OpaqueGoType
+		goType.Zero = "*new(" + goType.Name + ")"
+	case override.Import != "" && override.Kind != STRUCT_OVERRIDE:
+		// the underlying type of foreign scalars is not known
+		goType.Zero = "*new(" + goType.Name + ")"
+	}
+
+	return goType
+}
+
+// Returns the Go type of the field generated for the given property of the
+// definition
+func (r *GoTypeResolver) PropertyType(def *Definition, name string) (GoType, error) {
+	property, found := def.SwaggerDefinition.Properties[name]
+	if !found {
+		return GoType{}, fmt.Errorf("cannot find property %s of %s", name, def.ID)
+	}
+
+	goType, err := r.schemaType(def, name, &property, def.PackageName)
+	if err != nil {
+		return GoType{}, err
+	}
+	goType.Pointer = def.propertyIsPointer(name, &property, r.interfaces, r.gitRepo)
+
+	return goType, nil
+}
+
+// Returns the Go type of the schema, without taking into account whether
+// it's referenced by pointer
+func (r *GoTypeResolver) schemaType(def *Definition, propertyName string, schema *openapi_spec.Schema, packageName string) (GoType, error) {
+	if propertyName != "" && isStringEnum(schema) {
+		return GoType{
+			Kind:       ScalarGoType,
+			Name:       def.enumTypeName(propertyName),
+			Zero:       `""`,
+			Underlying: "string",
+		}, nil
+	}
+
+	if pointer := schema.SchemaProps.Ref.GetPointer(); pointer != nil && !pointer.IsEmpty() {
+		id := strings.TrimPrefix(pointer.String(), "/definitions/")
+		target, found := r.definitions[id]
+		if !found {
+			return GoType{}, fmt.Errorf("cannot find definition %s", id)
+		}
+		return r.DefinitionType(target, packageName)
+	}
+
+	switch {
+	case isEmbeddedResource(schema), isBrokenReference(schema), IsOpaqueSchema(schema):
+		return rawMessageGoType, nil
+	case schema.Type.Contains("array"):
+		elem, err := r.schemaType(def, propertyName, schema.Items.Schema, packageName)
+		if err != nil {
+			return GoType{}, err
+		}
+		elem.Pointer = elementIsPointer(schema.Items.Schema, r.interfaces, r.gitRepo, def.naming)
+		return GoType{Kind: SliceGoType, Elem: &elem}, nil
+	case schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil:
+		elem, err := r.schemaType(def, "", schema.AdditionalProperties.Schema, packageName)
+		if err != nil {
+			return GoType{}, err
+		}
+		elem.Pointer = elementIsPointer(schema.AdditionalProperties.Schema, r.interfaces, r.gitRepo, def.naming)
+		return GoType{Kind: MapGoType, Elem: &elem}, nil
+	}
+
+	// the schemas that are not opaque have a known primitive type
+	key := schema.Type[0]
+	if schema.Format != "" {
+		if _, known := primitiveGoTypes[key+"/"+schema.Format]; known {
+			key = key + "/" + schema.Format
+		}
+	}
+	return primitiveGoTypes[key], nil
+}
+
+// Returns true when the only Go type matching the schema is `interface{}`,
+// e.g. objects without properties, arrays without items and inline objects.
+// Like interfaces, these are kept as raw JSON documents: easyjson cannot
+// handle `interface{}` values without reflection
+func IsOpaqueSchema(schema *openapi_spec.Schema) bool {
+	if pointer := schema.SchemaProps.Ref.GetPointer(); pointer != nil && !pointer.IsEmpty() {
+		return false
+	}
+
+	switch {
+	case isEmbeddedResource(schema), isBrokenReference(schema):
+		return false
+	case schema.Type.Contains("array"):
+		return schema.Items == nil || schema.Items.Schema == nil
+	case schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil:
+		return false
+	case len(schema.Type) == 1:
+		_, known := primitiveGoTypes[schema.Type[0]]
+		return !known
+	}
+	return true
+}
+
+// Returns true when the field generated for the property is a pointer. The
+// result matches the `x-nullable` extensions set by `GeneratePatchedOpenAPIDef`
+// and the defaults of swagger, which turns required fields into pointers
+func (d *Definition) propertyIsPointer(name string, property *openapi_spec.Schema, interfaces *InterfaceRegistry, gitRepo string) bool {
+	switch {
+	case d.isTypeMetaProperty(name):
+		return false
+	case d.isObjectMetaProperty(name):
+		return true
+	case isEmbeddedResource(property), isBrokenReference(property), IsOpaqueSchema(property):
+		return false
+	case property.Type.Contains("array") || property.AdditionalProperties != nil:
+		return false
+	}
+
+	propImport, err := NewPropertyImportFromRef(&property.SchemaProps.Ref, d.naming)
+	if err == nil && !propImport.IsEmpty() {
+		return !interfaces.IsInterface(gitRepo, propImport.PackageName, propImport.TypeName)
+	}
+
+	// required values, enums included, are pointers: this allows to tell
+	// whether they have been set
+	for _, r := range d.SwaggerDefinition.Required {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns true when the elements of the array or of the map described by the
+// given schema are pointers
+func elementIsPointer(schema *openapi_spec.Schema, interfaces *InterfaceRegistry, gitRepo string, naming Naming) bool {
+	if isStringEnum(schema) {
+		return false
+	}
+
+	propImport, err := NewPropertyImportFromRef(&schema.SchemaProps.Ref, naming)
+	if err != nil || propImport.IsEmpty() {
+		return false
+	}
+
+	return !interfaces.IsInterface(gitRepo, propImport.PackageName, propImport.TypeName)
+}
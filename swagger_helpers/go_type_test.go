@@ -0,0 +1,144 @@
+package swagger_helpers
+
+import (
+	"encoding/json"
+	"testing"
+
+	openapi_spec "github.com/go-openapi/spec"
+)
+
+func TestGoTypeResolver(t *testing.T) {
+	data := []byte(`{
+		"io.k8s.api.core.v1.Container": {
+			"required": ["name", "restartPolicy"],
+			"properties": {
+				"name": {"type": "string"},
+				"image": {"type": "string"},
+				"imagePullPolicy": {"type": "string", "enum": ["Always", "Never"]},
+				"restartPolicy": {"type": "string", "enum": ["Always", "Never"]},
+				"ports": {"type": "array", "items": {"$ref": "#/definitions/io.k8s.api.core.v1.ContainerPort"}},
+				"args": {"type": "array", "items": {"type": "string"}},
+				"resources": {"$ref": "#/definitions/io.k8s.api.core.v1.ResourceRequirements"},
+				"limits": {"type": "object", "additionalProperties": {"$ref": "#/definitions/io.k8s.apimachinery.pkg.api.resource.Quantity"}},
+				"labels": {"type": "object", "additionalProperties": {"type": "string"}},
+				"raw": {"$ref": "#/definitions/io.k8s.apimachinery.pkg.runtime.RawExtension"},
+				"startedAt": {"$ref": "#/definitions/io.k8s.apimachinery.pkg.apis.meta.v1.Time"},
+				"data": {"type": "string", "format": "byte"},
+				"replicas": {"type": "integer", "format": "int32"},
+				"opaque": {"type": "object"},
+				"anything": {"type": "array"},
+				"inline": {"type": "object", "properties": {"name": {"type": "string"}}}
+			}
+		},
+		"io.k8s.api.core.v1.ContainerPort": {
+			"properties": {"containerPort": {"type": "integer", "format": "int32"}}
+		},
+		"io.k8s.api.core.v1.ResourceRequirements": {
+			"properties": {"claims": {"type": "array", "items": {"type": "string"}}}
+		},
+		"io.k8s.apimachinery.pkg.api.resource.Quantity": {"type": "string"},
+		"io.k8s.apimachinery.pkg.apis.meta.v1.Time": {"type": "string", "format": "date-time"},
+		"io.k8s.apimachinery.pkg.runtime.RawExtension": {"type": "object"}
+	}`)
+
+	swaggerDefinitions := openapi_spec.Definitions{}
+	if err := json.Unmarshal(data, &swaggerDefinitions); err != nil {
+		t.Fatalf("cannot parse definitions: %v", err)
+	}
+
+	definitions := make(map[string]*Definition)
+	for id, schema := range swaggerDefinitions {
+		definition, err := NewDefinition(schema, id, Naming{})
+		if err != nil {
+			t.Fatalf("cannot generate definition: %v", err)
+		}
+		definitions[id] = definition
+	}
+
+	interfaces := NewInterfaceRegistry()
+	interfaces.RegisterInterface("apimachinery/pkg/runtime", "RawExtension")
+	resolver := NewGoTypeResolver(definitions, &interfaces, "github.com/kubewarden/k8s-objects")
+
+	cases := []struct {
+		property string
+		expr     string
+		kind     GoTypeKind
+		zero     string
+	}{
+		{"name", "*string", ScalarGoType, `""`},
+		{"image", "string", ScalarGoType, `""`},
+		{"imagePullPolicy", "PullPolicy", ScalarGoType, `""`},
+		{"restartPolicy", "*ContainerRestartPolicy", ScalarGoType, `""`},
+		{"ports", "[]*ContainerPort", SliceGoType, ""},
+		{"args", "[]string", SliceGoType, ""},
+		{"resources", "*ResourceRequirements", StructGoType, ""},
+		{"limits", "map[string]*apimachinery_pkg_api_resource.Quantity", MapGoType, ""},
+		{"labels", "map[string]string", MapGoType, ""},
+		{"raw", "easyjson.RawMessage", BytesGoType, ""},
+		{"startedAt", "*apimachinery_pkg_apis_meta_v1.Time", ScalarGoType, "apimachinery_pkg_apis_meta_v1.Time{}"},
+		{"data", "strfmt.Base64", BytesGoType, ""},
+		{"replicas", "int32", ScalarGoType, "0"},
+		{"opaque", "easyjson.RawMessage", BytesGoType, ""},
+		{"anything", "easyjson.RawMessage", BytesGoType, ""},
+		{"inline", "easyjson.RawMessage", BytesGoType, ""},
+	}
+
+	container := definitions["io.k8s.api.core.v1.Container"]
+	for _, testCase := range cases {
+		goType, err := resolver.PropertyType(container, testCase.property)
+		if err != nil {
+			t.Errorf("%s: cannot resolve type: %v", testCase.property, err)
+			continue
+		}
+		if goType.Expr() != testCase.expr {
+			t.Errorf("%s: expected type %s got %s instead", testCase.property, testCase.expr, goType.Expr())
+		}
+		if goType.Kind != testCase.kind {
+			t.Errorf("%s: expected kind %v got %v instead", testCase.property, testCase.kind, goType.Kind)
+		}
+		if goType.Zero != testCase.zero {
+			t.Errorf("%s: expected zero value %s got %s instead", testCase.property, testCase.zero, goType.Zero)
+		}
+	}
+
+	limits, _ := resolver.PropertyType(container, "limits")
+	imports := make(map[string]string)
+	limits.CollectImports(imports)
+	if imports["github.com/kubewarden/k8s-objects/apimachinery/pkg/api/resource"] != "apimachinery_pkg_api_resource" {
+		t.Errorf("wrong imports: %v", imports)
+	}
+}
+
+func TestPatchSchemaOpaqueProperties(t *testing.T) {
+	schema := openapi_spec.Schema{
+		SchemaProps: openapi_spec.SchemaProps{
+			Properties: map[string]openapi_spec.Schema{
+				"opaque":   {SchemaProps: openapi_spec.SchemaProps{Type: []string{"object"}}},
+				"anything": {SchemaProps: openapi_spec.SchemaProps{Type: []string{"array"}}},
+				"labels":   *openapi_spec.MapProperty(openapi_spec.StringProperty()),
+				"spec":     *openapi_spec.RefProperty("#/definitions/io.k8s.api.core.v1.PodSpec"),
+			},
+		},
+	}
+	definition, err := NewDefinition(schema, "io.k8s.api.core.v1.Pod", Naming{})
+	if err != nil {
+		t.Fatalf("cannot generate definition: %v", err)
+	}
+
+	interfaces := NewInterfaceRegistry()
+	patchedSchema, err := definition.GeneratePatchedOpenAPIDef("github.com/kubewarden/k8s-objects", &interfaces, "")
+	if err != nil {
+		t.Fatalf("cannot generate patched schema: %v", err)
+	}
+
+	for name, opaque := range map[string]bool{"opaque": true, "anything": true, "labels": false, "spec": false} {
+		extensions := patchedSchema.Properties[name].Extensions
+		goType, _ := extensions["x-go-type"].(map[string]interface{})
+		if isRawMessage := goType["type"] == "RawMessage"; isRawMessage != opaque {
+			t.Errorf("%s: expected the property to be a raw JSON document to be %v, got %v", name, opaque, extensions)
+		}
+		if opaque {
+			checkBoolExtension(t, name, extensions, "x-nullable", false)
+		}
+	}
+}
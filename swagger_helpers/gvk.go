@@ -0,0 +1,128 @@
+package swagger_helpers
+
+import (
+	"fmt"
+)
+
+const GVK_EXTENSION = "x-kubernetes-group-version-kind"
+
+// Identifies a Kubernetes resource, as described by the
+// `x-kubernetes-group-version-kind` extension
+type GroupVersionKind struct {
+	Group   string
+	Version string
+	Kind    string
+}
+
+// Returns the value of the `apiVersion` field of the resource, e.g.
+// `apps/v1` or `v1` for the resources of the core group
+func (gvk GroupVersionKind) APIVersion() string {
+	if gvk.Group == "" {
+		return gvk.Version
+	}
+	return fmt.Sprintf("%s/%s", gvk.Group, gvk.Version)
+}
+
+func (gvk GroupVersionKind) String() string {
+	return fmt.Sprintf("%s, Kind=%s", gvk.APIVersion(), gvk.Kind)
+}
+
+// Returns the list of GroupVersionKind the definition is associated with.
+// The list is empty when the definition is not a top-level kind.
+func (d *Definition) GroupVersionKinds() []GroupVersionKind {
+	gvks := []GroupVersionKind{}
+
+	value, found := d.SwaggerDefinition.VendorExtensible.Extensions[GVK_EXTENSION]
+	if !found {
+		return gvks
+	}
+
+	entries, ok := value.([]interface{})
+	if !ok {
+		return gvks
+	}
+
+	for _, entry := range entries {
+		obj, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		gvk := GroupVersionKind{}
+		gvk.Group, _ = obj["group"].(string)
+		gvk.Version, _ = obj["version"].(string)
+		gvk.Kind, _ = obj["kind"].(string)
+		if gvk.Version == "" || gvk.Kind == "" {
+			continue
+		}
+		gvks = append(gvks, gvk)
+	}
+
+	return gvks
+}
+
+// Returns true when the definition has the `apiVersion` and `kind` string
+// properties used to identify the kind of the resource
+func (d *Definition) HasTypeMeta() bool {
+	for _, name := range []string{"apiVersion", "kind"} {
+		property, found := d.SwaggerDefinition.Properties[name]
+		if !found || !property.Type.Contains("string") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Returns true when the given property holds the `apiVersion` or the `kind`
+// of a resource
+func (d *Definition) isTypeMetaProperty(name string) bool {
+	return (name == "apiVersion" || name == "kind") &&
+		len(d.GroupVersionKinds()) > 0 &&
+		d.HasTypeMeta()
+}
+
+const OBJECT_META_ID = "io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"
+
+// Returns the package that defines the `ObjectMeta` type, according to the
+// package mapping rules
+func (n Naming) ObjectMetaPackage() string {
+	packageName, _, err := n.SplitDefinitionID(OBJECT_META_ID)
+	if err != nil {
+		return ""
+	}
+	return packageName
+}
+
+// ID the GroupVersionKind type would have if it was a swagger definition,
+// the generator writes it without one
+const GROUP_VERSION_KIND_ID = "io.k8s.apimachinery.pkg.runtime.schema.GroupVersionKind"
+
+// Returns the package that defines the `GroupVersionKind` type, according to
+// the package mapping rules
+func (n Naming) SchemaPackage() (string, error) {
+	packageName, _, err := n.SplitDefinitionID(GROUP_VERSION_KIND_ID)
+	return packageName, err
+}
+
+// Returns true when the definition has a `metadata` property of type
+// `ObjectMeta`
+func (d *Definition) HasObjectMeta() bool {
+	property, found := d.SwaggerDefinition.Properties["metadata"]
+	if !found {
+		return false
+	}
+
+	propImport, err := NewPropertyImportFromRef(&property.SchemaProps.Ref, d.naming)
+	if err != nil {
+		return false
+	}
+
+	return propImport.PackageName == d.naming.ObjectMetaPackage() && propImport.TypeName == "ObjectMeta"
+}
+
+// Returns true when the given property holds the `ObjectMeta` of a resource
+func (d *Definition) isObjectMetaProperty(name string) bool {
+	return name == "metadata" &&
+		len(d.GroupVersionKinds()) > 0 &&
+		d.HasObjectMeta()
+}
@@ -0,0 +1,100 @@
+package swagger_helpers
+
+import (
+	"testing"
+
+	openapi_spec "github.com/go-openapi/spec"
+)
+
+func TestGroupVersionKinds(t *testing.T) {
+	defSchema := openapi_spec.Schema{
+		SchemaProps: openapi_spec.SchemaProps{
+			Properties: map[string]openapi_spec.Schema{
+				"apiVersion": {SchemaProps: openapi_spec.SchemaProps{Type: []string{"string"}}},
+				"kind":       {SchemaProps: openapi_spec.SchemaProps{Type: []string{"string"}}},
+			},
+		},
+		VendorExtensible: openapi_spec.VendorExtensible{
+			Extensions: openapi_spec.Extensions{
+				GVK_EXTENSION: []interface{}{
+					map[string]interface{}{"group": "", "version": "v1", "kind": "Pod"},
+				},
+			},
+		},
+	}
+
+	definition, err := NewDefinition(defSchema, "io.k8s.api.core.v1.Pod", Naming{})
+	if err != nil {
+		t.Fatalf("cannot generate definition: %v", err)
+	}
+
+	gvks := definition.GroupVersionKinds()
+	if len(gvks) != 1 || gvks[0].APIVersion() != "v1" || gvks[0].Kind != "Pod" {
+		t.Fatalf("wrong GroupVersionKinds: %v", gvks)
+	}
+	if !definition.HasTypeMeta() {
+		t.Error("apiVersion and kind properties not detected")
+	}
+
+	interfaces := NewInterfaceRegistry()
+	patchedSchema, err := definition.GeneratePatchedOpenAPIDef(
+		"github.com/kubewarden/k8s-objects",
+		&interfaces,
+		"")
+	if err != nil {
+		t.Fatalf("cannot generate patched schema: %v", err)
+	}
+
+	for _, name := range []string{"apiVersion", "kind"} {
+		property := patchedSchema.Properties[name]
+		nullable, found := property.Extensions.GetBool("x-nullable")
+		if !found || nullable {
+			t.Errorf("property %s must not be nullable", name)
+		}
+	}
+}
+
+func TestHasObjectMeta(t *testing.T) {
+	defSchema := openapi_spec.Schema{
+		SchemaProps: openapi_spec.SchemaProps{
+			Required: []string{"metadata"},
+			Properties: map[string]openapi_spec.Schema{
+				"metadata": {
+					SchemaProps: openapi_spec.SchemaProps{
+						Ref: openapi_spec.MustCreateRef("#/definitions/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"),
+					},
+				},
+			},
+		},
+		VendorExtensible: openapi_spec.VendorExtensible{
+			Extensions: openapi_spec.Extensions{
+				GVK_EXTENSION: []interface{}{
+					map[string]interface{}{"group": "apps", "version": "v1", "kind": "Deployment"},
+				},
+			},
+		},
+	}
+
+	definition, err := NewDefinition(defSchema, "io.k8s.api.apps.v1.Deployment", Naming{})
+	if err != nil {
+		t.Fatalf("cannot generate definition: %v", err)
+	}
+	if !definition.HasObjectMeta() {
+		t.Fatal("ObjectMeta not detected")
+	}
+
+	interfaces := NewInterfaceRegistry()
+	patchedSchema, err := definition.GeneratePatchedOpenAPIDef(
+		"github.com/kubewarden/k8s-objects",
+		&interfaces,
+		"")
+	if err != nil {
+		t.Fatalf("cannot generate patched schema: %v", err)
+	}
+
+	// required, but still referenced by pointer
+	nullable, found := patchedSchema.Properties["metadata"].Extensions.GetBool("x-nullable")
+	if !found || !nullable {
+		t.Error("metadata must be nullable")
+	}
+}
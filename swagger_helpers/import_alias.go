@@ -0,0 +1,163 @@
+package swagger_helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Strategies used to compute the alias of the imported packages
+const (
+	// Join all the chunks of the package, e.g. `apimachinery_pkg_apis_meta_v1`
+	FULL_ALIAS_STRATEGY = "full"
+
+	// Use the last chunk of the package, prefixed by the group when it is a
+	// version, like client-go does, e.g. `metav1`
+	SHORT_ALIAS_STRATEGY = "short"
+)
+
+// Names of the packages imported, without alias, by the generated code. The
+// packages of the project cannot use them
+var reservedImportAliases = []string{
+	"bytes", "context", "easyjson", "encoding", "errors", "fmt", "jlexer",
+	"jwriter", "json", "reflect", "schema", "sort", "strconv", "strfmt",
+	"strings", "swag", "validate",
+}
+
+var versionChunkRegexp = regexp.MustCompile(`^v[0-9]+((alpha|beta)[0-9]+)?$`)
+
+var nonIdentifierRegexp = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// Defines how the generated code names the packages it imports
+type ImportAliasStrategy struct {
+	// Either FULL_ALIAS_STRATEGY or SHORT_ALIAS_STRATEGY, the first one is
+	// used when empty
+	Name string
+
+	// Aliases that take precedence over the strategy, indexed either by the
+	// name of a package of the project, e.g. `api/core/v1`, or by the import
+	// path of an external package
+	Custom map[string]string
+}
+
+// Reads the custom aliases from a JSON file like:
+//
+//	{
+//	  "api/core/v1": "corev1",
+//	  "github.com/example/types": "exampletypes"
+//	}
+func LoadCustomImportAliases(fileName string) (map[string]string, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read import aliases file %s", fileName)
+	}
+
+	aliases := make(map[string]string)
+	if err := json.Unmarshal(data, &aliases); err != nil {
+		return nil, errors.Wrapf(err, "cannot decode import aliases file %s", fileName)
+	}
+
+	return aliases, nil
+}
+
+func (s ImportAliasStrategy) Validate() error {
+	switch s.Name {
+	case "", FULL_ALIAS_STRATEGY, SHORT_ALIAS_STRATEGY:
+	default:
+		return fmt.Errorf("unknown import alias strategy %q, must be either %s or %s",
+			s.Name, FULL_ALIAS_STRATEGY, SHORT_ALIAS_STRATEGY)
+	}
+
+	packages := []string{}
+	for pkg := range s.Custom {
+		packages = append(packages, pkg)
+	}
+	sort.Strings(packages)
+
+	for _, pkg := range packages {
+		alias := s.Custom[pkg]
+		if !token.IsIdentifier(alias) || alias == "_" {
+			return fmt.Errorf("alias %q of %s is not a valid Go identifier", alias, pkg)
+		}
+	}
+
+	return nil
+}
+
+// Returns the alias of the package, which is either the name of a package of
+// the project, e.g. `api/core/v1`, or an import path
+func (s ImportAliasStrategy) Alias(packageName string) string {
+	if alias, found := s.Custom[packageName]; found {
+		return alias
+	}
+
+	if s.Name == SHORT_ALIAS_STRATEGY {
+		return shortAlias(packageName)
+	}
+	return fullAlias(packageName)
+}
+
+func fullAlias(packageName string) string {
+	alias := strings.ReplaceAll(packageName, "-", "")
+	return nonIdentifierRegexp.ReplaceAllString(alias, "_")
+}
+
+// `apimachinery/pkg/apis/meta/v1` becomes `metav1`,
+// `apimachinery/pkg/util/intstr` becomes `intstr`
+func shortAlias(packageName string) string {
+	chunks := strings.Split(packageName, "/")
+	alias := chunks[len(chunks)-1]
+	if versionChunkRegexp.MatchString(alias) && len(chunks) > 1 {
+		alias = chunks[len(chunks)-2] + alias
+	}
+
+	return nonIdentifierRegexp.ReplaceAllString(alias, "")
+}
+
+// Returns the alias used to import the given package from the generated
+// code, e.g. `api_apps_v1` or `appsv1`, according to the ImportAliasStrategy
+func (n Naming) PackageAlias(packageName string) string {
+	return n.importAliases.Alias(packageName)
+}
+
+// Reports the packages that would be imported using the same alias, or
+// using the name of a package imported by the generated code
+// * `packages`: names of the project packages and external import paths
+func (n Naming) CheckImportAliases(packages []string) error {
+	packagesByAlias := make(map[string][]string)
+	for _, alias := range reservedImportAliases {
+		packagesByAlias[alias] = []string{"(reserved)"}
+	}
+
+	sortedPackages := append([]string{}, packages...)
+	sort.Strings(sortedPackages)
+	for _, pkg := range sortedPackages {
+		alias := n.PackageAlias(pkg)
+		packagesByAlias[alias] = append(packagesByAlias[alias], pkg)
+	}
+
+	aliases := []string{}
+	for alias, pkgs := range packagesByAlias {
+		if len(pkgs) > 1 {
+			aliases = append(aliases, alias)
+		}
+	}
+	if len(aliases) == 0 {
+		return nil
+	}
+	sort.Strings(aliases)
+
+	var report strings.Builder
+	report.WriteString("import alias collisions found, use custom aliases to solve them:")
+	for _, alias := range aliases {
+		fmt.Fprintf(&report, "\n  %s: %s", alias, strings.Join(packagesByAlias[alias], ", "))
+	}
+
+	return errors.New(report.String())
+}
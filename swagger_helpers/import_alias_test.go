@@ -0,0 +1,138 @@
+package swagger_helpers
+
+import (
+	"strings"
+	"testing"
+
+	openapi_spec "github.com/go-openapi/spec"
+)
+
+// Returns the naming rules using the given import alias strategy
+func newTestAliasNaming(t *testing.T, strategy ImportAliasStrategy) Naming {
+	naming, err := NewNaming(NamingOptions{ImportAliases: strategy}, nil)
+	if err != nil {
+		t.Fatalf("cannot set import alias strategy: %v", err)
+	}
+	return naming
+}
+
+func TestImportAliasStrategy(t *testing.T) {
+	custom := map[string]string{
+		"api/core/v1":              "k8score",
+		"github.com/example/types": "exampletypes",
+	}
+
+	cases := []struct {
+		strategy ImportAliasStrategy
+		pkg      string
+		expected string
+	}{
+		{ImportAliasStrategy{Name: FULL_ALIAS_STRATEGY}, "apimachinery/pkg/apis/meta/v1", "apimachinery_pkg_apis_meta_v1"},
+		{ImportAliasStrategy{Name: FULL_ALIAS_STRATEGY}, "apiextensions-apiserver/pkg/apis/apiextensions/v1", "apiextensionsapiserver_pkg_apis_apiextensions_v1"},
+		{ImportAliasStrategy{Name: FULL_ALIAS_STRATEGY}, "github.com/example/types", "github_com_example_types"},
+		{ImportAliasStrategy{Name: SHORT_ALIAS_STRATEGY}, "apimachinery/pkg/apis/meta/v1", "metav1"},
+		{ImportAliasStrategy{Name: SHORT_ALIAS_STRATEGY}, "api/flowcontrol/v1beta2", "flowcontrolv1beta2"},
+		{ImportAliasStrategy{Name: SHORT_ALIAS_STRATEGY}, "apimachinery/pkg/util/intstr", "intstr"},
+		{ImportAliasStrategy{Name: SHORT_ALIAS_STRATEGY}, "kube-aggregator/pkg/apis/apiregistration/v1", "apiregistrationv1"},
+		{ImportAliasStrategy{Name: SHORT_ALIAS_STRATEGY}, "github.com/example/go-types", "gotypes"},
+		{ImportAliasStrategy{Name: SHORT_ALIAS_STRATEGY, Custom: custom}, "api/core/v1", "k8score"},
+		{ImportAliasStrategy{Name: FULL_ALIAS_STRATEGY, Custom: custom}, "github.com/example/types", "exampletypes"},
+		{ImportAliasStrategy{Name: FULL_ALIAS_STRATEGY, Custom: custom}, "api/apps/v1", "api_apps_v1"},
+		{ImportAliasStrategy{}, "api/apps/v1", "api_apps_v1"},
+	}
+
+	for _, c := range cases {
+		if alias := c.strategy.Alias(c.pkg); alias != c.expected {
+			t.Errorf("%s alias of %s: expected %s, got %s", c.strategy.Name, c.pkg, c.expected, alias)
+		}
+	}
+}
+
+func TestNewNamingImportAliasErrors(t *testing.T) {
+	cases := []struct {
+		strategy      ImportAliasStrategy
+		expectedError string
+	}{
+		{ImportAliasStrategy{Name: "tiny"}, `unknown import alias strategy "tiny"`},
+		{ImportAliasStrategy{Name: SHORT_ALIAS_STRATEGY, Custom: map[string]string{"api/core/v1": "core-v1"}}, `alias "core-v1" of api/core/v1`},
+		{ImportAliasStrategy{Name: SHORT_ALIAS_STRATEGY, Custom: map[string]string{"api/core/v1": "_"}}, `alias "_" of api/core/v1`},
+	}
+
+	for _, c := range cases {
+		_, err := NewNaming(NamingOptions{ImportAliases: c.strategy}, nil)
+		if err == nil || !strings.Contains(err.Error(), c.expectedError) {
+			t.Errorf("expected error containing %q, got %v", c.expectedError, err)
+		}
+	}
+}
+
+func TestCheckImportAliases(t *testing.T) {
+	packages := []string{
+		"api/core/v1",
+		"api/storage/v1",
+		"apimachinery/pkg/apis/meta/v1",
+		"example/storage/v1",
+		"github.com/example/validate",
+	}
+
+	naming := newTestAliasNaming(t, ImportAliasStrategy{Name: FULL_ALIAS_STRATEGY})
+	if err := naming.CheckImportAliases(packages); err != nil {
+		t.Errorf("full aliases must not collide: %v", err)
+	}
+
+	naming = newTestAliasNaming(t, ImportAliasStrategy{Name: SHORT_ALIAS_STRATEGY})
+	err := naming.CheckImportAliases(packages)
+	if err == nil {
+		t.Fatal("expected short aliases to collide")
+	}
+	expectedLines := []string{
+		"  storagev1: api/storage/v1, example/storage/v1",
+		"  validate: (reserved), github.com/example/validate",
+	}
+	for _, line := range expectedLines {
+		if !strings.Contains(err.Error(), line) {
+			t.Errorf("cannot find %q inside of the report:\n%v", line, err)
+		}
+	}
+	if strings.Contains(err.Error(), "corev1") {
+		t.Errorf("only the collisions must be reported:\n%v", err)
+	}
+
+	naming = newTestAliasNaming(t, ImportAliasStrategy{
+		Name: SHORT_ALIAS_STRATEGY,
+		Custom: map[string]string{
+			"example/storage/v1":          "examplestoragev1",
+			"github.com/example/validate": "examplevalidate",
+		},
+	})
+	if err := naming.CheckImportAliases(packages); err != nil {
+		t.Errorf("custom aliases must solve the collisions: %v", err)
+	}
+}
+
+func TestNewPropertyImportFromRefShortAlias(t *testing.T) {
+	naming := newTestAliasNaming(t, ImportAliasStrategy{Name: SHORT_ALIAS_STRATEGY})
+
+	schema := openapi_spec.Schema{
+		SchemaProps: openapi_spec.SchemaProps{
+			Properties: map[string]openapi_spec.Schema{
+				"metadata": *openapi_spec.RefProperty("#/definitions/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"),
+			},
+		},
+	}
+	definition, err := NewDefinition(schema, "io.k8s.api.core.v1.Pod", naming)
+	if err != nil {
+		t.Fatalf("cannot generate definition: %v", err)
+	}
+
+	interfaces := NewInterfaceRegistry()
+	patchedSchema, err := definition.GeneratePatchedOpenAPIDef("github.com/kubewarden/k8s-objects", &interfaces, "")
+	if err != nil {
+		t.Fatalf("cannot generate patched schema: %v", err)
+	}
+
+	goType := patchedSchema.Properties["metadata"].Extensions["x-go-type"].(map[string]interface{})
+	if alias := goType["import"].(map[string]string)["alias"]; alias != "metav1" {
+		t.Errorf("expected metav1 alias, got %s", alias)
+	}
+}
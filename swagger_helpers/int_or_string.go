@@ -0,0 +1,22 @@
+package swagger_helpers
+
+// ID of the IntOrString definition
+const INT_OR_STRING_ID = "io.k8s.apimachinery.pkg.util.intstr.IntOrString"
+
+// Format of the schemas that hold either an integer or a string
+const INT_OR_STRING_FORMAT = "int-or-string"
+
+// Returns true when the definition describes a value that can be either an
+// integer or a string. Depending on the version of the OpenAPI spec, the
+// definition is either a string with the `int-or-string` format or an empty
+// object. A real `IntOrString` type is generated instead of a raw message.
+func (d *Definition) IsIntOrString() bool {
+	return d.SwaggerDefinition.Format == INT_OR_STRING_FORMAT || d.ID == INT_OR_STRING_ID
+}
+
+// Returns true when the Go type of the definition is not generated by swagger
+// but written by the generator, like IntOrString and Quantity. These are
+// registered as type overrides by the refactoring plan.
+func (d *Definition) IsCustomType() bool {
+	return d.IsIntOrString() || d.IsQuantity()
+}
@@ -0,0 +1,81 @@
+package swagger_helpers
+
+import (
+	"testing"
+
+	openapi_spec "github.com/go-openapi/spec"
+)
+
+func TestIsIntOrString(t *testing.T) {
+	cases := []struct {
+		id       string
+		schema   openapi_spec.Schema
+		expected bool
+	}{
+		{
+			id:       "io.k8s.apimachinery.pkg.util.intstr.IntOrString",
+			schema:   openapi_spec.Schema{SchemaProps: openapi_spec.SchemaProps{Type: []string{"string"}, Format: "int-or-string"}},
+			expected: true,
+		},
+		{
+			// older specs describe it as an empty object
+			id:       "io.k8s.apimachinery.pkg.util.intstr.IntOrString",
+			schema:   openapi_spec.Schema{},
+			expected: true,
+		},
+		{
+			id:       "io.k8s.apimachinery.pkg.api.resource.Quantity",
+			schema:   openapi_spec.Schema{SchemaProps: openapi_spec.SchemaProps{Type: []string{"string"}}},
+			expected: false,
+		},
+	}
+
+	for _, c := range cases {
+		definition, err := NewDefinition(c.schema, c.id, Naming{})
+		if err != nil {
+			t.Fatalf("cannot generate definition: %v", err)
+		}
+		if definition.IsIntOrString() != c.expected {
+			t.Errorf("%s with format %q: expected %v", c.id, c.schema.Format, c.expected)
+		}
+	}
+}
+
+func TestPatchSchemaIntOrString(t *testing.T) {
+	gitRepo := "github.com/kubewarden/k8s-objects"
+	interfaces := NewInterfaceRegistry()
+	definition, err := NewDefinition(
+		openapi_spec.Schema{SchemaProps: openapi_spec.SchemaProps{Type: []string{"string"}, Format: "int-or-string"}},
+		INT_OR_STRING_ID, Naming{})
+	if err != nil {
+		t.Fatalf("cannot generate definition: %v", err)
+	}
+
+	// the refactoring plan registers the types written by the generator
+	interfaces.RegisterOverride(definition.PackageName, definition.TypeName, TypeOverride{Type: definition.TypeName})
+
+	patchedSchema, err := definition.GeneratePatchedOpenAPIDef(gitRepo, &interfaces, "")
+	if err != nil {
+		t.Fatalf("cannot generate patched schema: %v", err)
+	}
+
+	goType, found := patchedSchema.Extensions["x-go-type"].(map[string]interface{})
+	if !found {
+		t.Fatalf("x-go-type not set: %v", patchedSchema.Extensions)
+	}
+	if goType["type"] != "IntOrString" {
+		t.Errorf("wrong type: %v", goType)
+	}
+	if goType["import"].(map[string]string)["package"] != "github.com/kubewarden/k8s-objects/apimachinery/pkg/util/intstr" {
+		t.Errorf("wrong import: %v", goType)
+	}
+
+	resolver := NewGoTypeResolver(map[string]*Definition{INT_OR_STRING_ID: definition}, &interfaces, gitRepo)
+	intOrString, err := resolver.DefinitionType(definition, "api/apps/v1")
+	if err != nil {
+		t.Fatalf("cannot resolve type: %v", err)
+	}
+	if intOrString.Kind != ScalarGoType || intOrString.Zero != "apimachinery_pkg_util_intstr.IntOrString{}" {
+		t.Errorf("IntOrString must be a comparable struct: %+v", intOrString)
+	}
+}
@@ -2,20 +2,24 @@ package swagger_helpers
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	mapset "github.com/deckarep/golang-set"
 )
 
 // Keeps track of all the `interface` objects that are defined inside of the
-// project
+// project, together with the definitions whose Go type is overridden. The
+// overrides take precedence over the interfaces
 type InterfaceRegistry struct {
 	interfacesByModule map[string]mapset.Set
+	overridesByModule  map[string]map[string]TypeOverride
 }
 
 func NewInterfaceRegistry() InterfaceRegistry {
 	return InterfaceRegistry{
 		interfacesByModule: make(map[string]mapset.Set),
+		overridesByModule:  make(map[string]map[string]TypeOverride),
 	}
 }
 
@@ -36,6 +40,10 @@ func (r *InterfaceRegistry) RegisterInterface(module, name string) {
 // * `module`: name of the module where the type is defined
 // * `name`: name of the type
 func (r *InterfaceRegistry) IsInterface(gitRepo, module, name string) bool {
+	if _, overridden := r.Override(gitRepo, module, name); overridden {
+		return false
+	}
+
 	module = strings.TrimPrefix(module, fmt.Sprintf("%s/", gitRepo))
 	interfaces, known := r.interfacesByModule[module]
 
@@ -46,6 +54,51 @@ func (r *InterfaceRegistry) IsInterface(gitRepo, module, name string) bool {
 	return interfaces.Contains(name)
 }
 
+// replace the Go type generated for the type called `name`, defined inside of
+// the `module` module
+func (r *InterfaceRegistry) RegisterOverride(module, name string, override TypeOverride) {
+	overrides, known := r.overridesByModule[module]
+	if !known {
+		overrides = make(map[string]TypeOverride)
+	}
+	overrides[name] = override
+
+	r.overridesByModule[module] = overrides
+}
+
+// returns the override of the given type, the boolean is false when the type
+// is not overridden
+// * `gitRepo`: repository that will contain the generated code: i.e. `github.com/kubewarden/k8s-objects`
+// * `module`: name of the module where the type is defined
+// * `name`: name of the type
+func (r *InterfaceRegistry) Override(gitRepo, module, name string) (TypeOverride, bool) {
+	module = strings.TrimPrefix(module, fmt.Sprintf("%s/", gitRepo))
+	override, found := r.overridesByModule[module][name]
+
+	return override, found
+}
+
+// returns the import paths of the packages providing the overriding types,
+// sorted
+func (r *InterfaceRegistry) OverrideImports() []string {
+	imports := mapset.NewSet()
+	for _, overrides := range r.overridesByModule {
+		for _, override := range overrides {
+			if override.Import != "" {
+				imports.Add(override.Import)
+			}
+		}
+	}
+
+	paths := []string{}
+	for path := range imports.Iter() {
+		paths = append(paths, path.(string))
+	}
+	sort.Strings(paths)
+
+	return paths
+}
+
 func (r *InterfaceRegistry) Dump() {
 	for module, interfaces := range r.interfacesByModule {
 		fmt.Printf("interfaces for module %s: %+v\n", module, interfaces)
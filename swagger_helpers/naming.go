@@ -0,0 +1,67 @@
+package swagger_helpers
+
+// Options controlling the names of the packages, types and import aliases of
+// the generated code. The zero value applies the default rules
+type NamingOptions struct {
+	ImportAliases ImportAliasStrategy
+
+	// Rules turning the definition IDs into package names, they take
+	// precedence over the Kubernetes one
+	PackageMappingRules []PackageMappingRule
+
+	// Names of the types generated for the definitions, indexed by
+	// definition ID. They take precedence over the DuplicateTypeStrategy
+	TypeNames map[string]string
+
+	// Either FAIL_DUPLICATE_TYPE_STRATEGY or PREFIX_DUPLICATE_TYPE_STRATEGY,
+	// the first one is used when empty
+	DuplicateTypeStrategy string
+}
+
+// Names the packages, types and import aliases of the generated code. It is
+// shared by all the definitions of a refactoring plan and by all the
+// generators: the references and the imports have to resolve to the names of
+// the definitions they point to, no matter which generator wrote them. The
+// zero value applies the default rules
+type Naming struct {
+	importAliases       ImportAliasStrategy
+	packageMappingRules []PackageMappingRule
+
+	// type names indexed by definition ID, the custom ones take precedence
+	// over the ones computed by the duplicate type strategy
+	customTypeNames        map[string]string
+	disambiguatedTypeNames map[string]string
+}
+
+// Validates the options, the given definitions are turned into distinct Go
+// types according to the duplicate type strategy
+// * `ids`: IDs of all the definitions of the swagger file
+func NewNaming(options NamingOptions, ids []string) (Naming, error) {
+	if err := options.ImportAliases.Validate(); err != nil {
+		return Naming{}, err
+	}
+
+	rules, err := compilePackageMappingRules(options.PackageMappingRules)
+	if err != nil {
+		return Naming{}, err
+	}
+
+	if err := validateTypeNames(options.TypeNames); err != nil {
+		return Naming{}, err
+	}
+
+	naming := Naming{
+		importAliases:       options.ImportAliases,
+		packageMappingRules: rules,
+		customTypeNames:     make(map[string]string),
+	}
+	for id, name := range options.TypeNames {
+		naming.customTypeNames[id] = name
+	}
+
+	if err := naming.resolveDuplicateTypes(ids, options.DuplicateTypeStrategy); err != nil {
+		return Naming{}, err
+	}
+
+	return naming, nil
+}
@@ -1,6 +1,8 @@
 package swagger_helpers
 
 import (
+	"fmt"
+
 	mapset "github.com/deckarep/golang-set"
 	openapi_spec "github.com/go-openapi/spec"
 	"github.com/pkg/errors"
@@ -43,10 +45,18 @@ func (p *Package) GenerateSwagger(swaggerVersion, kubernetesVersion, gitRepo str
 	swagger.SwaggerProps.Info = &info
 	swagger.Definitions = make(openapi_spec.Definitions)
 
+	definitionIDs := make(map[string]string)
 	for _, def := range p.Definitions {
+		if other, found := definitionIDs[def.TypeName]; found {
+			return openapi_spec.Swagger{},
+				fmt.Errorf("cannot generate type %s/%s: both %s and %s map to it", p.Name, def.TypeName, other, def.ID)
+		}
+		definitionIDs[def.TypeName] = def.ID
+
 		patchedDefinition, err := def.GeneratePatchedOpenAPIDef(
 			gitRepo,
 			interfaces,
+			kubernetesVersion,
 		)
 		if err != nil {
 			return openapi_spec.Swagger{},
@@ -55,5 +65,25 @@ func (p *Package) GenerateSwagger(swaggerVersion, kubernetesVersion, gitRepo str
 		swagger.Definitions[def.TypeName] = patchedDefinition
 	}
 
+	enums := make(map[string]openapi_spec.Schema)
+	for _, def := range p.Definitions {
+		for typeName, enumDefinition := range def.EnumDefinitions() {
+			if enum, found := enums[typeName]; found {
+				// the type is shared with the property of another definition
+				enumDefinition = mergeEnumDefinitions(enum, enumDefinition)
+			} else if _, found := swagger.Definitions[typeName]; found {
+				return openapi_spec.Swagger{},
+					fmt.Errorf("cannot generate enum type %s/%s: the name is already taken", p.Name, typeName)
+			}
+			enums[typeName] = enumDefinition
+		}
+	}
+	for typeName, enumDefinition := range enums {
+		swagger.Definitions[typeName] = enumDefinition
+	}
+	if err := checkEnumConstants(p.Name, swagger.Definitions, enums); err != nil {
+		return openapi_spec.Swagger{}, err
+	}
+
 	return swagger, nil
 }
@@ -0,0 +1,127 @@
+package swagger_helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Rewrites the namespace of a definition ID, which is the ID without the
+// type name (e.g. `io.k8s.api.core.v1`), into the name of a package. The dots
+// left inside of the result are turned into slashes
+type PackageMappingRule struct {
+	// Namespaces starting with this prefix are matched, the prefix is replaced
+	// by Package, e.g. `io.cert-manager.` -> `certmanager/`
+	Prefix string `json:"prefix,omitempty"`
+
+	// Namespaces entirely matched by this regular expression are replaced by
+	// Package, which can reference the submatches, e.g. `io.k8s.api.(.*)` ->
+	// `$1`
+	Regexp string `json:"regexp,omitempty"`
+
+	Package string `json:"package"`
+
+	compiledRegexp *regexp.Regexp
+}
+
+// Default rule, used when none of the configured ones matches
+var kubernetesPackageMappingRule = PackageMappingRule{Prefix: "io.k8s.", Package: ""}
+
+// Validates the rules and compiles their regular expressions
+func compilePackageMappingRules(rules []PackageMappingRule) ([]PackageMappingRule, error) {
+	compiledRules := []PackageMappingRule{}
+
+	for i, rule := range rules {
+		if (rule.Prefix == "") == (rule.Regexp == "") {
+			return nil, fmt.Errorf("package mapping rule #%d must have either a prefix or a regexp", i+1)
+		}
+
+		if rule.Regexp != "" {
+			compiled, err := regexp.Compile(fmt.Sprintf("^(?:%s)$", rule.Regexp))
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid regexp of package mapping rule #%d", i+1)
+			}
+			rule.compiledRegexp = compiled
+		}
+
+		compiledRules = append(compiledRules, rule)
+	}
+
+	return compiledRules, nil
+}
+
+// Reads the package mapping rules from a JSON file like:
+//
+//	[
+//	  {"regexp": "io\\.k8s\\.api\\.(.*)", "package": "$1"},
+//	  {"prefix": "io.cert-manager.", "package": "certmanager/"}
+//	]
+func LoadPackageMappingRules(fileName string) ([]PackageMappingRule, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read package mapping file %s", fileName)
+	}
+
+	rules := []PackageMappingRule{}
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, errors.Wrapf(err, "cannot decode package mapping file %s", fileName)
+	}
+
+	return rules, nil
+}
+
+// Returns the package name produced by the rule, the boolean is false when
+// the rule does not match the namespace
+func (r PackageMappingRule) apply(namespace string) (string, bool) {
+	if r.compiledRegexp != nil {
+		match := r.compiledRegexp.FindStringSubmatchIndex(namespace)
+		if match == nil {
+			return "", false
+		}
+		return string(r.compiledRegexp.ExpandString(nil, r.Package, namespace, match)), true
+	}
+
+	if !strings.HasPrefix(namespace, r.Prefix) {
+		return "", false
+	}
+	return r.Package + strings.TrimPrefix(namespace, r.Prefix), true
+}
+
+// Given a definition ID like `io.k8s.api.core.v1.Pod` returns the package
+// that is going to hold the type, `api/core/v1`, and the name of the type,
+// `Pod`. The first package mapping rule matching the ID wins. The type name
+// is changed by the custom type names and by the duplicate type strategy
+func (n Naming) SplitDefinitionID(id string) (string, string, error) {
+	lastDot := strings.LastIndex(id, ".")
+	if lastDot <= 0 || lastDot == len(id)-1 {
+		return "", "", fmt.Errorf("cannot find the package of %s: wrong number of chunks", id)
+	}
+	namespace := id[:lastDot]
+	typeName := id[lastDot+1:]
+	if name, renamed := n.renamedType(id); renamed {
+		typeName = name
+	}
+
+	packageName, matched := "", false
+	for _, rule := range n.packageMappingRules {
+		if packageName, matched = rule.apply(namespace); matched {
+			break
+		}
+	}
+	if !matched {
+		if packageName, matched = kubernetesPackageMappingRule.apply(namespace); !matched {
+			packageName = namespace
+		}
+	}
+
+	packageName = strings.Trim(strings.ReplaceAll(packageName, ".", "/"), "/")
+	if packageName == "" {
+		return "", "", fmt.Errorf("cannot find the package of %s: the package mapping produced an empty name", id)
+	}
+
+	return packageName, typeName, nil
+}
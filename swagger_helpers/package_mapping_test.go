@@ -0,0 +1,139 @@
+package swagger_helpers
+
+import (
+	"strings"
+	"testing"
+
+	openapi_spec "github.com/go-openapi/spec"
+)
+
+// Returns the naming rules using the given package mapping rules
+func newTestMappingNaming(t *testing.T, rules []PackageMappingRule) Naming {
+	naming, err := NewNaming(NamingOptions{PackageMappingRules: rules}, nil)
+	if err != nil {
+		t.Fatalf("cannot set package mapping rules: %v", err)
+	}
+	return naming
+}
+
+func TestSplitDefinitionID(t *testing.T) {
+	cases := []struct {
+		id              string
+		expectedPackage string
+		expectedType    string
+	}{
+		{"io.k8s.api.core.v1.Pod", "api/core/v1", "Pod"},
+		{"io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta", "apimachinery/pkg/apis/meta/v1", "ObjectMeta"},
+		{"io.cert-manager.acme.v1.Challenge", "io/cert-manager/acme/v1", "Challenge"},
+	}
+
+	for _, c := range cases {
+		packageName, typeName, err := (Naming{}).SplitDefinitionID(c.id)
+		if err != nil {
+			t.Errorf("cannot split %s: %v", c.id, err)
+			continue
+		}
+		if packageName != c.expectedPackage || typeName != c.expectedType {
+			t.Errorf("%s: expected %s.%s, got %s.%s", c.id, c.expectedPackage, c.expectedType, packageName, typeName)
+		}
+	}
+
+	for _, id := range []string{"Pod", ".Pod", "io.k8s.api.core.v1."} {
+		if _, _, err := (Naming{}).SplitDefinitionID(id); err == nil {
+			t.Errorf("expected %q to be rejected", id)
+		}
+	}
+}
+
+func TestSplitDefinitionIDWithRules(t *testing.T) {
+	naming := newTestMappingNaming(t, []PackageMappingRule{
+		{Regexp: `io\.k8s\.api\.(.*)`, Package: "$1"},
+		{Prefix: "io.cert-manager.", Package: "certmanager/"},
+		{Regexp: `com\.example\.(\w+)\.(v\w+)`, Package: "example/${2}/${1}"},
+	})
+
+	cases := []struct {
+		id              string
+		expectedPackage string
+	}{
+		{"io.k8s.api.core.v1.Pod", "core/v1"},
+		{"io.cert-manager.acme.v1.Challenge", "certmanager/acme/v1"},
+		{"com.example.widgets.v1alpha1.Widget", "example/v1alpha1/widgets"},
+		// the regexp must match the whole namespace
+		{"com.example.widgets.v1alpha1.internal.Widget", "com/example/widgets/v1alpha1/internal"},
+		// the default rule still applies when nothing matches
+		{"io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta", "apimachinery/pkg/apis/meta/v1"},
+	}
+
+	for _, c := range cases {
+		packageName, _, err := naming.SplitDefinitionID(c.id)
+		if err != nil {
+			t.Errorf("cannot split %s: %v", c.id, err)
+			continue
+		}
+		if packageName != c.expectedPackage {
+			t.Errorf("%s: expected package %s, got %s", c.id, c.expectedPackage, packageName)
+		}
+	}
+}
+
+func TestNewNamingPackageMappingErrors(t *testing.T) {
+	cases := []struct {
+		rules         []PackageMappingRule
+		expectedError string
+	}{
+		{[]PackageMappingRule{{Package: "core"}}, "rule #1 must have either a prefix or a regexp"},
+		{[]PackageMappingRule{{Prefix: "io.", Regexp: "io", Package: "core"}}, "rule #1 must have either a prefix or a regexp"},
+		{[]PackageMappingRule{{Prefix: "io.", Package: "io"}, {Regexp: "io.(", Package: "core"}}, "invalid regexp of package mapping rule #2"},
+	}
+
+	for _, c := range cases {
+		_, err := NewNaming(NamingOptions{PackageMappingRules: c.rules}, nil)
+		if err == nil || !strings.Contains(err.Error(), c.expectedError) {
+			t.Errorf("expected error containing %q, got %v", c.expectedError, err)
+		}
+	}
+}
+
+func TestPackageMappingRulesAppliedToRefs(t *testing.T) {
+	naming := newTestMappingNaming(t, []PackageMappingRule{
+		{Regexp: `io\.k8s\.api\.(.*)`, Package: "$1"},
+		{Prefix: "io.k8s.apimachinery.pkg.apis.", Package: ""},
+	})
+
+	schema := openapi_spec.Schema{
+		SchemaProps: openapi_spec.SchemaProps{
+			Properties: map[string]openapi_spec.Schema{
+				"metadata": *openapi_spec.RefProperty("#/definitions/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"),
+				"spec":     *openapi_spec.RefProperty("#/definitions/io.k8s.api.core.v1.PodSpec"),
+			},
+		},
+	}
+	definition, err := NewDefinition(schema, "io.k8s.api.core.v1.Pod", naming)
+	if err != nil {
+		t.Fatalf("cannot generate definition: %v", err)
+	}
+	if definition.PackageName != "core/v1" {
+		t.Errorf("wrong package name: %s", definition.PackageName)
+	}
+	if !definition.HasObjectMeta() {
+		t.Error("the ObjectMeta reference must be found inside of the mapped package")
+	}
+
+	ref := definition.SwaggerDefinition.Properties["spec"].Ref
+	propImport, err := NewPropertyImportFromRef(&ref, naming)
+	if err != nil {
+		t.Fatalf("cannot parse ref: %v", err)
+	}
+	if propImport.PackageName != definition.PackageName || propImport.Alias != "core_v1" {
+		t.Errorf("refs must use the same mapping as the definitions: %+v", propImport)
+	}
+
+	dependencies := []string{}
+	for dependency := range definition.dependencies.Iter() {
+		dependencies = append(dependencies, dependency.(string))
+	}
+	if len(dependencies) != 1 || dependencies[0] != "meta/v1" {
+		t.Errorf("wrong dependencies: %v", dependencies)
+	}
+}
@@ -0,0 +1,51 @@
+package swagger_helpers
+
+const (
+	PATCH_MERGE_KEY_EXTENSION = "x-kubernetes-patch-merge-key"
+	PATCH_STRATEGY_EXTENSION  = "x-kubernetes-patch-strategy"
+	LIST_TYPE_EXTENSION       = "x-kubernetes-list-type"
+	LIST_MAP_KEYS_EXTENSION   = "x-kubernetes-list-map-keys"
+)
+
+// Describes how a property is handled by strategic merge patches and
+// server-side apply
+type PatchMetadata struct {
+	// e.g. `merge` or `retainKeys,merge`
+	PatchStrategy string
+	PatchMergeKey string
+	// one of `atomic`, `set` or `map`
+	ListType    string
+	ListMapKeys []string
+}
+
+func (p *PatchMetadata) IsEmpty() bool {
+	return p.PatchStrategy == "" && p.PatchMergeKey == "" && p.ListType == "" && len(p.ListMapKeys) == 0
+}
+
+// Returns the patch metadata of the properties of the definition, indexed by
+// property name. Properties without metadata are not part of the result.
+func (d *Definition) PatchMetadata() map[string]PatchMetadata {
+	metadata := make(map[string]PatchMetadata)
+
+	for name, property := range d.SwaggerDefinition.Properties {
+		extensions := property.VendorExtensible.Extensions
+
+		meta := PatchMetadata{}
+		meta.PatchStrategy, _ = extensions[PATCH_STRATEGY_EXTENSION].(string)
+		meta.PatchMergeKey, _ = extensions[PATCH_MERGE_KEY_EXTENSION].(string)
+		meta.ListType, _ = extensions[LIST_TYPE_EXTENSION].(string)
+		if keys, ok := extensions[LIST_MAP_KEYS_EXTENSION].([]interface{}); ok {
+			for _, key := range keys {
+				if k, ok := key.(string); ok {
+					meta.ListMapKeys = append(meta.ListMapKeys, k)
+				}
+			}
+		}
+
+		if !meta.IsEmpty() {
+			metadata[name] = meta
+		}
+	}
+
+	return metadata
+}
@@ -0,0 +1,58 @@
+package swagger_helpers
+
+import (
+	"reflect"
+	"testing"
+
+	openapi_spec "github.com/go-openapi/spec"
+)
+
+func TestPatchMetadata(t *testing.T) {
+	data := []byte(`{
+		"properties": {
+			"containers": {
+				"type": "array",
+				"items": {"$ref": "#/definitions/io.k8s.api.core.v1.Container"},
+				"x-kubernetes-patch-merge-key": "name",
+				"x-kubernetes-patch-strategy": "merge",
+				"x-kubernetes-list-type": "map",
+				"x-kubernetes-list-map-keys": ["name"]
+			},
+			"finalizers": {
+				"type": "array",
+				"items": {"type": "string"},
+				"x-kubernetes-list-type": "set"
+			},
+			"hostname": {
+				"type": "string"
+			}
+		}
+	}`)
+
+	defSchema := openapi_spec.Schema{}
+	if err := defSchema.UnmarshalJSON(data); err != nil {
+		t.Fatalf("cannot parse schema: %v", err)
+	}
+
+	definition, err := NewDefinition(defSchema, "io.k8s.api.core.v1.PodSpec", Naming{})
+	if err != nil {
+		t.Fatalf("cannot generate definition: %v", err)
+	}
+
+	expected := map[string]PatchMetadata{
+		"containers": {
+			PatchStrategy: "merge",
+			PatchMergeKey: "name",
+			ListType:      "map",
+			ListMapKeys:   []string{"name"},
+		},
+		"finalizers": {
+			ListType: "set",
+		},
+	}
+
+	metadata := definition.PatchMetadata()
+	if !reflect.DeepEqual(metadata, expected) {
+		t.Errorf("wrong patch metadata, expected %+v got %+v instead", expected, metadata)
+	}
+}
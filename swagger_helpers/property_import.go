@@ -43,28 +43,21 @@ func (p *PropertyImport) ToMap(gitRepo string) map[string]interface{} {
 //      alias: "apimachinery_pkgs_apis_meta_v1",
 //      type_name: "LabelSelector",
 //  }
-func NewPropertyImportFromRef(ref *openapi_spec.Ref) (PropertyImport, error) {
+func NewPropertyImportFromRef(ref *openapi_spec.Ref, naming Naming) (PropertyImport, error) {
 	refPointer := ref.GetPointer()
 	if refPointer == nil || refPointer.IsEmpty() {
 		return PropertyImport{}, nil
 	}
 
-	namespace := strings.TrimPrefix(refPointer.String(), "/definitions/io.k8s.")
-	chunks := strings.Split(namespace, ".")
-	if len(chunks) < 2 {
-		return PropertyImport{},
-			fmt.Errorf("ref -> chunk: not enough chunks for %s: %+v", ref, chunks)
+	id := strings.TrimPrefix(refPointer.String(), "/definitions/")
+	packageName, typeName, err := naming.SplitDefinitionID(id)
+	if err != nil {
+		return PropertyImport{}, fmt.Errorf("ref -> package: %v", err)
 	}
 
-	namespaceChunks := chunks[0 : len(chunks)-1]
-	typeName := chunks[len(chunks)-1]
-
-	alias := strings.Join(namespaceChunks, "_")
-	alias = strings.ReplaceAll(alias, "-", "")
-
 	return PropertyImport{
 		TypeName:    typeName,
-		Alias:       alias,
-		PackageName: strings.Join(namespaceChunks, "/"),
+		Alias:       naming.PackageAlias(packageName),
+		PackageName: packageName,
 	}, nil
 }
@@ -40,7 +40,7 @@ func TestNewPropertyImportFromRef(t *testing.T) {
 			t.Errorf("cannot create ref from url %s: %v", testCase.ref, err)
 		}
 
-		propImport, err := NewPropertyImportFromRef(&ref)
+		propImport, err := NewPropertyImportFromRef(&ref, Naming{})
 		if err != nil {
 			t.Errorf("unexpected error while parsing %s: %v", testCase.ref, err)
 		}
@@ -94,7 +94,7 @@ func TestPropertyImportToMap(t *testing.T) {
 			t.Errorf("cannot create ref from url %s: %v", testCase.ref, err)
 		}
 
-		propImport, err := NewPropertyImportFromRef(&ref)
+		propImport, err := NewPropertyImportFromRef(&ref, Naming{})
 		if err != nil {
 			t.Errorf("unexpected error while parsing %s: %v", testCase.ref, err)
 		}
@@ -0,0 +1,25 @@
+package swagger_helpers
+
+import "fmt"
+
+// ID of the Quantity definition
+const QUANTITY_ID = "io.k8s.apimachinery.pkg.api.resource.Quantity"
+
+// Returns true when the definition describes a resource quantity, like
+// `100m` or `1Gi`. The definition is a plain string inside of the OpenAPI
+// spec, a real `Quantity` type is generated instead.
+func (d *Definition) IsQuantity() bool {
+	return d.ID == QUANTITY_ID
+}
+
+// Returns a quantity in canonical form, otherwise it would be changed by a
+// round trip
+func (b *SampleBuilder) buildQuantity() string {
+	if b.options.Rand == nil {
+		return "1"
+	}
+
+	value := 1 + b.options.Rand.Intn(999)
+	suffixes := []string{"", "m", "k", "Mi", "Gi"}
+	return fmt.Sprintf("%d%s", value, suffixes[b.options.Rand.Intn(len(suffixes))])
+}
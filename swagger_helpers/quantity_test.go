@@ -0,0 +1,74 @@
+package swagger_helpers
+
+import (
+	"math/rand"
+	"testing"
+
+	openapi_spec "github.com/go-openapi/spec"
+)
+
+func TestPatchSchemaQuantity(t *testing.T) {
+	gitRepo := "github.com/kubewarden/k8s-objects"
+	interfaces := NewInterfaceRegistry()
+	definition, err := NewDefinition(
+		openapi_spec.Schema{SchemaProps: openapi_spec.SchemaProps{Type: []string{"string"}}},
+		QUANTITY_ID, Naming{})
+	if err != nil {
+		t.Fatalf("cannot generate definition: %v", err)
+	}
+	if !definition.IsQuantity() || !definition.IsCustomType() {
+		t.Fatal("Quantity must be a custom type")
+	}
+
+	// the refactoring plan registers the types written by the generator
+	interfaces.RegisterOverride(definition.PackageName, definition.TypeName, TypeOverride{Type: definition.TypeName})
+
+	patchedSchema, err := definition.GeneratePatchedOpenAPIDef(gitRepo, &interfaces, "")
+	if err != nil {
+		t.Fatalf("cannot generate patched schema: %v", err)
+	}
+
+	goType, found := patchedSchema.Extensions["x-go-type"].(map[string]interface{})
+	if !found {
+		t.Fatalf("x-go-type not set: %v", patchedSchema.Extensions)
+	}
+	if goType["type"] != "Quantity" {
+		t.Errorf("wrong type: %v", goType)
+	}
+	if goType["import"].(map[string]string)["package"] != "github.com/kubewarden/k8s-objects/apimachinery/pkg/api/resource" {
+		t.Errorf("wrong import: %v", goType)
+	}
+
+	resolver := NewGoTypeResolver(map[string]*Definition{QUANTITY_ID: definition}, &interfaces, gitRepo)
+	quantity, err := resolver.DefinitionType(definition, "api/core/v1")
+	if err != nil {
+		t.Fatalf("cannot resolve type: %v", err)
+	}
+	if quantity.Kind != ScalarGoType || quantity.Zero != "apimachinery_pkg_api_resource.Quantity{}" {
+		t.Errorf("Quantity must be a comparable struct: %+v", quantity)
+	}
+}
+
+func TestSampleQuantity(t *testing.T) {
+	definitions := newTestDefinitions(t, map[string]openapi_spec.Schema{
+		QUANTITY_ID: {SchemaProps: openapi_spec.SchemaProps{Type: []string{"string"}}},
+		"io.k8s.api.core.v1.ResourceRequirements": {SchemaProps: openapi_spec.SchemaProps{
+			Properties: map[string]openapi_spec.Schema{
+				"limits": *openapi_spec.MapProperty(openapi_spec.RefSchema("#/definitions/" + QUANTITY_ID)),
+			},
+		}},
+	})
+
+	builder := NewSampleBuilder(definitions, SampleOptions{Rand: rand.New(rand.NewSource(1))})
+	for i := 0; i < 10; i++ {
+		value, err := builder.Build("io.k8s.api.core.v1.ResourceRequirements")
+		if err != nil {
+			t.Fatalf("cannot build sample: %v", err)
+		}
+		for _, quantity := range value.(map[string]interface{})["limits"].(map[string]interface{}) {
+			if _, ok := quantity.(string); !ok {
+				t.Errorf("quantities must be strings, got %v", quantity)
+			}
+		}
+	}
+}
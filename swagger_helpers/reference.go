@@ -0,0 +1,85 @@
+package swagger_helpers
+
+import (
+	"fmt"
+	"strings"
+
+	openapi_spec "github.com/go-openapi/spec"
+	"github.com/pkg/errors"
+)
+
+// Extension set on the schemas whose reference has been broken to solve a
+// dependency cycle, it holds the ID of the definition that was referenced
+const BROKEN_REFERENCE_EXTENSION = "x-broken-reference"
+
+// A reference from a schema of a definition to another definition
+type Reference struct {
+	// Position of the schema holding the reference inside of the definition,
+	// e.g. `spec.template`, see SchemaLocation
+	Path string
+
+	// ID of the referenced definition
+	ID string
+}
+
+// Returns true when the schema used to reference another definition, before
+// being replaced by a raw JSON document to break a dependency cycle
+func isBrokenReference(schema *openapi_spec.Schema) bool {
+	_, found := schema.Extensions.GetString(BROKEN_REFERENCE_EXTENSION)
+	return found
+}
+
+// Returns all the references to other definitions, no matter how deep they
+// are nested, in the order they are found by WalkSchema
+func (d *Definition) References() []Reference {
+	references := []Reference{}
+
+	_ = WalkSchema(&d.SwaggerDefinition, SchemaLocation{Required: true},
+		func(schema *openapi_spec.Schema, location SchemaLocation) error {
+			pointer := schema.SchemaProps.Ref.GetPointer()
+			if pointer != nil && !pointer.IsEmpty() {
+				references = append(references, Reference{
+					Path: location.Path,
+					ID:   strings.TrimPrefix(pointer.String(), "/definitions/"),
+				})
+			}
+			return nil
+		})
+
+	return references
+}
+
+// Replaces the reference found at the given path with a raw JSON document,
+// the definition no longer depends on the referenced one. This is used to
+// break dependency cycles between packages
+func (d *Definition) BreakReference(path string) error {
+	found := false
+
+	err := WalkSchema(&d.SwaggerDefinition, SchemaLocation{Required: true},
+		func(schema *openapi_spec.Schema, location SchemaLocation) error {
+			pointer := schema.SchemaProps.Ref.GetPointer()
+			if location.Path != path || pointer == nil || pointer.IsEmpty() {
+				return nil
+			}
+
+			broken := openapi_spec.Schema{}
+			broken.Description = schema.Description
+			broken.AddExtension(BROKEN_REFERENCE_EXTENSION, strings.TrimPrefix(pointer.String(), "/definitions/"))
+			*schema = broken
+			found = true
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("cannot find a reference at %s inside of %s", path, d.ID)
+	}
+
+	d.dependencies.Clear()
+	if err := d.computeDependencies(); err != nil {
+		return errors.Wrapf(err, "cannot compute dependencies of %s", d.ID)
+	}
+
+	return nil
+}
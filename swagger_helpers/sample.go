@@ -0,0 +1,293 @@
+package swagger_helpers
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	mapset "github.com/deckarep/golang-set"
+	openapi_spec "github.com/go-openapi/spec"
+	"github.com/pkg/errors"
+)
+
+// Tunes the documents produced by the SampleBuilder
+type SampleOptions struct {
+	// Populate only the required properties
+	Minimal bool
+
+	// Source of the sample values. Fixed placeholder values are used when
+	// this is nil
+	Rand *rand.Rand
+}
+
+// Builds representative JSON documents starting from the definitions
+// found inside of the original swagger file.
+// Unless the `Rand` option is set, properties are populated with non-zero
+// placeholder values; this ensures the documents are not altered by the
+// `omitempty` rules of the generated code.
+type SampleBuilder struct {
+	// definitions indexed by their original ID, e.g. `io.k8s.api.core.v1.Pod`
+	definitions map[string]*Definition
+	options     SampleOptions
+}
+
+func NewSampleBuilder(definitions map[string]*Definition, options SampleOptions) SampleBuilder {
+	return SampleBuilder{
+		definitions: definitions,
+		options:     options,
+	}
+}
+
+// Returns a sample document for the definition with the given ID. The
+// returned value can be serialized with `encoding/json`
+func (b *SampleBuilder) Build(id string) (interface{}, error) {
+	definition, found := b.definitions[id]
+	if !found {
+		return nil, fmt.Errorf("cannot find definition %s", id)
+	}
+
+	visiting := mapset.NewSet()
+	visiting.Add(id)
+
+	value, _, err := b.buildSchema(&definition.SwaggerDefinition, visiting)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot build sample for definition %s", id)
+	}
+
+	// top-level kinds must always have a meaningful type meta
+	if gvks := definition.GroupVersionKinds(); len(gvks) > 0 {
+		if obj, ok := value.(map[string]interface{}); ok {
+			if _, found := definition.SwaggerDefinition.Properties["apiVersion"]; found {
+				obj["apiVersion"] = gvks[0].APIVersion()
+			}
+			if _, found := definition.SwaggerDefinition.Properties["kind"]; found {
+				obj["kind"] = gvks[0].Kind
+			}
+		}
+	}
+
+	return value, nil
+}
+
+// Returns the sample value of the given schema. The boolean value is false
+// when the schema references a definition that is already being visited,
+// this happens with recursive types like `JSONSchemaProps`.
+// * `visiting`: the IDs of the definitions that are being built
+func (b *SampleBuilder) buildSchema(schema *openapi_spec.Schema, visiting mapset.Set) (interface{}, bool, error) {
+	refPointer := schema.SchemaProps.Ref.GetPointer()
+	if refPointer != nil && !refPointer.IsEmpty() {
+		id := strings.TrimPrefix(refPointer.String(), "/definitions/")
+		if visiting.Contains(id) {
+			return nil, false, nil
+		}
+
+		definition, found := b.definitions[id]
+		if !found {
+			return nil, false, fmt.Errorf("unsolved reference %s", id)
+		}
+
+		if definition.IsQuantity() {
+			return b.buildQuantity(), true, nil
+		}
+		if definition.IsIntOrString() {
+			// older specs describe IntOrString as an empty object
+			intOrString := openapi_spec.Schema{SchemaProps: openapi_spec.SchemaProps{
+				Type:   []string{"string"},
+				Format: INT_OR_STRING_FORMAT,
+			}}
+			return b.buildPrimitive(&intOrString), true, nil
+		}
+
+		visiting.Add(id)
+		defer visiting.Remove(id)
+
+		return b.buildSchema(&definition.SwaggerDefinition, visiting)
+	}
+
+	if len(schema.Enum) > 0 {
+		if b.options.Rand != nil {
+			return schema.Enum[b.options.Rand.Intn(len(schema.Enum))], true, nil
+		}
+		return schema.Enum[0], true, nil
+	}
+
+	switch {
+	case len(schema.Properties) > 0:
+		return b.buildObject(schema, visiting)
+	case schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil:
+		obj := make(map[string]interface{})
+		for i := 0; i < b.collectionSize(); i++ {
+			value, ok, err := b.buildSchema(schema.AdditionalProperties.Schema, visiting)
+			if err != nil || !ok {
+				return obj, true, err
+			}
+			obj[b.mapKey(i)] = value
+		}
+		return obj, true, nil
+	case schema.Items != nil && schema.Items.Schema != nil:
+		items := []interface{}{}
+		for i := 0; i < b.collectionSize(); i++ {
+			value, ok, err := b.buildSchema(schema.Items.Schema, visiting)
+			if err != nil || !ok {
+				return items, true, err
+			}
+			items = append(items, value)
+		}
+		return items, true, nil
+	}
+
+	return b.buildPrimitive(schema), true, nil
+}
+
+func (b *SampleBuilder) buildObject(schema *openapi_spec.Schema, visiting mapset.Set) (interface{}, bool, error) {
+	required := mapset.NewSet()
+	for _, r := range schema.Required {
+		required.Add(r)
+	}
+
+	// iterate over the properties in a stable order, otherwise the values
+	// produced by a seeded random generator would not be reproducible
+	names := []string{}
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	obj := make(map[string]interface{})
+	for _, name := range names {
+		if b.options.Minimal && !required.Contains(name) {
+			continue
+		}
+		property := schema.Properties[name]
+
+		value, ok, err := b.buildSchema(&property, visiting)
+		if err != nil {
+			return nil, false, errors.Wrapf(err, "cannot build sample for property %s", name)
+		}
+		if !ok {
+			if !required.Contains(name) {
+				// break the recursion by leaving the optional property unset
+				continue
+			}
+			value = map[string]interface{}{}
+		}
+		obj[name] = value
+	}
+
+	return obj, true, nil
+}
+
+// Returns the number of entries to put inside of arrays and maps
+func (b *SampleBuilder) collectionSize() int {
+	if b.options.Rand == nil {
+		return 1
+	}
+	return 1 + b.options.Rand.Intn(3)
+}
+
+func (b *SampleBuilder) mapKey(index int) string {
+	if b.options.Rand == nil {
+		return "key"
+	}
+	return fmt.Sprintf("%s-%d", b.randomWord(), index)
+}
+
+// Returns a lowercase word that is a valid Kubernetes name
+func (b *SampleBuilder) randomWord() string {
+	const letters = "abcdefghijklmnopqrstuvwxyz"
+
+	word := make([]byte, 5+b.options.Rand.Intn(6))
+	for i := range word {
+		word[i] = letters[b.options.Rand.Intn(len(letters))]
+	}
+	return string(word)
+}
+
+// Returns a value matching the type and format of the schema
+func (b *SampleBuilder) buildPrimitive(schema *openapi_spec.Schema) interface{} {
+	if b.options.Rand == nil {
+		return samplePrimitive(schema)
+	}
+	random := b.options.Rand
+
+	schemaType := ""
+	if len(schema.Type) > 0 {
+		schemaType = schema.Type[0]
+	}
+
+	switch schemaType {
+	case "boolean":
+		return random.Intn(2) == 1
+	case "integer":
+		minimum, maximum := int64(1), int64(100)
+		if schema.Minimum != nil {
+			minimum = int64(*schema.Minimum)
+		}
+		if schema.Maximum != nil {
+			maximum = int64(*schema.Maximum)
+		}
+		if maximum < minimum {
+			maximum = minimum
+		}
+		return minimum + random.Int63n(maximum-minimum+1)
+	case "number":
+		return float64(random.Intn(10000)) / 100
+	case "string":
+		timestamp := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC).
+			Add(time.Duration(random.Int63n(4*365*24)) * time.Hour)
+
+		switch schema.Format {
+		case "date-time":
+			return timestamp.Format("2006-01-02T15:04:05.000Z07:00")
+		case "date":
+			return timestamp.Format("2006-01-02")
+		case "byte":
+			return base64.StdEncoding.EncodeToString([]byte(b.randomWord()))
+		case "int-or-string":
+			if random.Intn(2) == 1 {
+				return 1 + random.Intn(100)
+			}
+			return fmt.Sprintf("%d%%", random.Intn(101))
+		}
+		return b.randomWord()
+	}
+
+	return map[string]interface{}{}
+}
+
+// Returns a non-zero placeholder value matching the type and format of the
+// schema
+func samplePrimitive(schema *openapi_spec.Schema) interface{} {
+	schemaType := ""
+	if len(schema.Type) > 0 {
+		schemaType = schema.Type[0]
+	}
+
+	switch schemaType {
+	case "boolean":
+		return true
+	case "integer":
+		return 1
+	case "number":
+		return 1.5
+	case "string":
+		switch schema.Format {
+		case "date-time":
+			// this is the format used by strfmt.DateTime when marshaling
+			return "2022-01-01T00:00:00.000Z"
+		case "date":
+			return "2022-01-01"
+		case "byte":
+			return "c2FtcGxl"
+		case "int-or-string":
+			return "1"
+		}
+		return "sample"
+	}
+
+	// objects without properties are handled as raw messages
+	return map[string]interface{}{}
+}
@@ -0,0 +1,175 @@
+package swagger_helpers
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	openapi_spec "github.com/go-openapi/spec"
+)
+
+func newTestDefinitions(t *testing.T, schemas map[string]openapi_spec.Schema) map[string]*Definition {
+	definitions := make(map[string]*Definition)
+	for id, schema := range schemas {
+		definition, err := NewDefinition(schema, id, Naming{})
+		if err != nil {
+			t.Fatalf("cannot create definition %s: %v", id, err)
+		}
+		definitions[id] = definition
+	}
+	return definitions
+}
+
+func TestSampleBuilder(t *testing.T) {
+	refObjectMeta, err := openapi_spec.NewRef("#/definitions/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta")
+	if err != nil {
+		t.Errorf("Cannot create ref: %v", err)
+	}
+	refSelf, err := openapi_spec.NewRef("#/definitions/io.k8s.api.core.v1.Node")
+	if err != nil {
+		t.Errorf("Cannot create ref: %v", err)
+	}
+
+	schemas := make(map[string]openapi_spec.Schema)
+	schemas["io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"] = openapi_spec.Schema{
+		SchemaProps: openapi_spec.SchemaProps{
+			Properties: map[string]openapi_spec.Schema{
+				"name": {
+					SchemaProps: openapi_spec.SchemaProps{Type: []string{"string"}},
+				},
+				"creationTimestamp": {
+					SchemaProps: openapi_spec.SchemaProps{Type: []string{"string"}, Format: "date-time"},
+				},
+				"labels": {
+					SchemaProps: openapi_spec.SchemaProps{
+						Type: []string{"object"},
+						AdditionalProperties: &openapi_spec.SchemaOrBool{
+							Schema: &openapi_spec.Schema{
+								SchemaProps: openapi_spec.SchemaProps{Type: []string{"string"}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	schemas["io.k8s.api.core.v1.Node"] = openapi_spec.Schema{
+		SchemaProps: openapi_spec.SchemaProps{
+			Required: []string{"metadata"},
+			Properties: map[string]openapi_spec.Schema{
+				"metadata": {
+					SchemaProps: openapi_spec.SchemaProps{Ref: refObjectMeta},
+				},
+				"replicas": {
+					SchemaProps: openapi_spec.SchemaProps{Type: []string{"integer"}, Format: "int32"},
+				},
+				"policy": {
+					SchemaProps: openapi_spec.SchemaProps{
+						Type: []string{"string"},
+						Enum: []interface{}{"Always", "Never"},
+					},
+				},
+				"children": {
+					SchemaProps: openapi_spec.SchemaProps{
+						Type: []string{"array"},
+						Items: &openapi_spec.SchemaOrArray{
+							Schema: &openapi_spec.Schema{
+								SchemaProps: openapi_spec.SchemaProps{Ref: refSelf},
+							},
+						},
+					},
+				},
+				"parent": {
+					SchemaProps: openapi_spec.SchemaProps{Ref: refSelf},
+				},
+			},
+		},
+	}
+
+	builder := NewSampleBuilder(newTestDefinitions(t, schemas), SampleOptions{})
+
+	sample, err := builder.Build("io.k8s.api.core.v1.Node")
+	if err != nil {
+		t.Fatalf("cannot build sample: %v", err)
+	}
+
+	data, err := json.Marshal(sample)
+	if err != nil {
+		t.Fatalf("cannot encode sample: %v", err)
+	}
+
+	expected := `{"children":[],"metadata":{"creationTimestamp":"2022-01-01T00:00:00.000Z","labels":{"key":"sample"},"name":"sample"},"policy":"Always","replicas":1}`
+	if string(data) != expected {
+		t.Errorf("wrong sample, expected %s got %s instead", expected, string(data))
+	}
+
+	if _, err := builder.Build("io.k8s.api.core.v1.Unknown"); err == nil {
+		t.Errorf("was expecting an error when building an unknown definition")
+	}
+}
+
+func TestSampleBuilderOptions(t *testing.T) {
+	schemas := make(map[string]openapi_spec.Schema)
+	schemas["io.k8s.api.apps.v1.Deployment"] = openapi_spec.Schema{
+		SchemaProps: openapi_spec.SchemaProps{
+			Required: []string{"replicas"},
+			Properties: map[string]openapi_spec.Schema{
+				"apiVersion": {
+					SchemaProps: openapi_spec.SchemaProps{Type: []string{"string"}},
+				},
+				"kind": {
+					SchemaProps: openapi_spec.SchemaProps{Type: []string{"string"}},
+				},
+				"replicas": {
+					SchemaProps: openapi_spec.SchemaProps{Type: []string{"integer"}},
+				},
+				"paused": {
+					SchemaProps: openapi_spec.SchemaProps{Type: []string{"boolean"}},
+				},
+				"maxSurge": {
+					SchemaProps: openapi_spec.SchemaProps{Type: []string{"string"}, Format: "int-or-string"},
+				},
+			},
+		},
+		VendorExtensible: openapi_spec.VendorExtensible{
+			Extensions: openapi_spec.Extensions{
+				GVK_EXTENSION: []interface{}{
+					map[string]interface{}{"group": "apps", "version": "v1", "kind": "Deployment"},
+				},
+			},
+		},
+	}
+	definitions := newTestDefinitions(t, schemas)
+
+	minimalBuilder := NewSampleBuilder(definitions, SampleOptions{Minimal: true})
+	sample, err := minimalBuilder.Build("io.k8s.api.apps.v1.Deployment")
+	if err != nil {
+		t.Fatalf("cannot build sample: %v", err)
+	}
+	data, err := json.Marshal(sample)
+	if err != nil {
+		t.Fatalf("cannot encode sample: %v", err)
+	}
+	expected := `{"apiVersion":"apps/v1","kind":"Deployment","replicas":1}`
+	if string(data) != expected {
+		t.Errorf("wrong minimal sample, expected %s got %s instead", expected, string(data))
+	}
+
+	// the same seed must always produce the same document
+	documents := []string{}
+	for i := 0; i < 2; i++ {
+		builder := NewSampleBuilder(definitions, SampleOptions{Rand: rand.New(rand.NewSource(42))})
+		sample, err := builder.Build("io.k8s.api.apps.v1.Deployment")
+		if err != nil {
+			t.Fatalf("cannot build sample: %v", err)
+		}
+		data, err := json.Marshal(sample)
+		if err != nil {
+			t.Fatalf("cannot encode sample: %v", err)
+		}
+		documents = append(documents, string(data))
+	}
+	if documents[0] != documents[1] {
+		t.Errorf("seeded samples are different: %s - %s", documents[0], documents[1])
+	}
+}
@@ -0,0 +1,161 @@
+package swagger_helpers
+
+import (
+	"fmt"
+	"sort"
+
+	openapi_spec "github.com/go-openapi/spec"
+)
+
+type SchemaLocationKind int
+
+const (
+	// the schema the walk starts from
+	RootLocation SchemaLocationKind = iota
+	// a property of an object, either a definition or an inline object
+	PropertyLocation
+	// the elements of an array, including the ones of tuples and the
+	// additional items
+	ItemsLocation
+	// the values of a map
+	AdditionalPropertiesLocation
+	// the values of the properties matching a pattern
+	PatternPropertiesLocation
+	// a member of `allOf`, `oneOf` or `anyOf`, or the schema negated by `not`
+	CompositionLocation
+)
+
+// Describes where a schema has been found by WalkSchema
+type SchemaLocation struct {
+	Kind SchemaLocationKind
+
+	// Name of the property, set only for PropertyLocation
+	Name string
+
+	// Position of the schema relative to the root, used to report errors,
+	// e.g. `spec.containers[].env{}`
+	Path string
+
+	// Number of schemas between the root and this one, the root has depth 0
+	Depth int
+
+	// True when the value described by the schema is required. Properties
+	// are required when their object lists them, elements of arrays and of
+	// maps inherit the value of their container, composition members are
+	// always required since they do not describe a field
+	Required bool
+}
+
+// Invoked on every schema found by WalkSchema. Changes made to the schema
+// are preserved
+type SchemaVisitor func(schema *openapi_spec.Schema, location SchemaLocation) error
+
+// Visits the given schema and, recursively, all the schemas nested inside of
+// it: properties, array items, map values and composition members. Parents are
+// visited before their children, the order is deterministic. References are
+// not followed
+func WalkSchema(schema *openapi_spec.Schema, location SchemaLocation, visit SchemaVisitor) error {
+	if err := visit(schema, location); err != nil {
+		return err
+	}
+
+	child := func(kind SchemaLocationKind, path string, required bool) SchemaLocation {
+		return SchemaLocation{
+			Kind:     kind,
+			Path:     path,
+			Depth:    location.Depth + 1,
+			Required: required,
+		}
+	}
+
+	required := make(map[string]bool)
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+	for _, name := range sortedSchemaNames(schema.Properties) {
+		property := schema.Properties[name]
+		propertyLocation := child(PropertyLocation, joinSchemaPath(location.Path, name), required[name])
+		propertyLocation.Name = name
+		if err := WalkSchema(&property, propertyLocation, visit); err != nil {
+			return err
+		}
+		schema.Properties[name] = property
+	}
+
+	if schema.Items != nil {
+		if schema.Items.Schema != nil {
+			itemsLocation := child(ItemsLocation, location.Path+"[]", location.Required)
+			if err := WalkSchema(schema.Items.Schema, itemsLocation, visit); err != nil {
+				return err
+			}
+		}
+		for i := range schema.Items.Schemas {
+			itemsLocation := child(ItemsLocation, fmt.Sprintf("%s[%d]", location.Path, i), location.Required)
+			if err := WalkSchema(&schema.Items.Schemas[i], itemsLocation, visit); err != nil {
+				return err
+			}
+		}
+	}
+	if schema.AdditionalItems != nil && schema.AdditionalItems.Schema != nil {
+		itemsLocation := child(ItemsLocation, location.Path+"[+]", location.Required)
+		if err := WalkSchema(schema.AdditionalItems.Schema, itemsLocation, visit); err != nil {
+			return err
+		}
+	}
+
+	if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+		valuesLocation := child(AdditionalPropertiesLocation, location.Path+"{}", location.Required)
+		if err := WalkSchema(schema.AdditionalProperties.Schema, valuesLocation, visit); err != nil {
+			return err
+		}
+	}
+	for _, pattern := range sortedSchemaNames(schema.PatternProperties) {
+		property := schema.PatternProperties[pattern]
+		valuesLocation := child(PatternPropertiesLocation, fmt.Sprintf("%s{%s}", location.Path, pattern), location.Required)
+		if err := WalkSchema(&property, valuesLocation, visit); err != nil {
+			return err
+		}
+		schema.PatternProperties[pattern] = property
+	}
+
+	compositions := []struct {
+		keyword string
+		members []openapi_spec.Schema
+	}{
+		{"allOf", schema.AllOf},
+		{"anyOf", schema.AnyOf},
+		{"oneOf", schema.OneOf},
+	}
+	for _, composition := range compositions {
+		for i := range composition.members {
+			memberPath := joinSchemaPath(location.Path, fmt.Sprintf("%s[%d]", composition.keyword, i))
+			if err := WalkSchema(&composition.members[i], child(CompositionLocation, memberPath, true), visit); err != nil {
+				return err
+			}
+		}
+	}
+	if schema.Not != nil {
+		if err := WalkSchema(schema.Not, child(CompositionLocation, joinSchemaPath(location.Path, "not"), true), visit); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func joinSchemaPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func sortedSchemaNames(schemas map[string]openapi_spec.Schema) []string {
+	names := []string{}
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
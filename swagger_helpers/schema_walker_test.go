@@ -0,0 +1,235 @@
+package swagger_helpers
+
+import (
+	"reflect"
+	"testing"
+
+	openapi_spec "github.com/go-openapi/spec"
+)
+
+const walkerTestRef = "#/definitions/io.k8s.apimachinery.pkg.apis.meta.v1.LabelSelector"
+
+func arraySchema(items *openapi_spec.Schema) *openapi_spec.Schema {
+	return openapi_spec.ArrayProperty(items)
+}
+
+func mapSchema(values *openapi_spec.Schema) *openapi_spec.Schema {
+	return openapi_spec.MapProperty(values)
+}
+
+// Nesting shapes, together with the function returning the schema holding
+// the ref once it has been patched
+var walkerTestShapes = []struct {
+	name   string
+	schema *openapi_spec.Schema
+	nested func(*openapi_spec.Schema) *openapi_spec.Schema
+}{
+	{
+		name:   "direct ref",
+		schema: openapi_spec.RefProperty(walkerTestRef),
+		nested: func(s *openapi_spec.Schema) *openapi_spec.Schema { return s },
+	},
+	{
+		name:   "array of arrays",
+		schema: arraySchema(arraySchema(openapi_spec.RefProperty(walkerTestRef))),
+		nested: func(s *openapi_spec.Schema) *openapi_spec.Schema { return s.Items.Schema.Items.Schema },
+	},
+	{
+		name:   "map of arrays",
+		schema: mapSchema(arraySchema(openapi_spec.RefProperty(walkerTestRef))),
+		nested: func(s *openapi_spec.Schema) *openapi_spec.Schema {
+			return s.AdditionalProperties.Schema.Items.Schema
+		},
+	},
+	{
+		name:   "array of maps",
+		schema: arraySchema(mapSchema(openapi_spec.RefProperty(walkerTestRef))),
+		nested: func(s *openapi_spec.Schema) *openapi_spec.Schema {
+			return s.Items.Schema.AdditionalProperties.Schema
+		},
+	},
+	{
+		name: "inline object",
+		schema: &openapi_spec.Schema{SchemaProps: openapi_spec.SchemaProps{
+			Type: []string{"object"},
+			Properties: map[string]openapi_spec.Schema{
+				"inner": *arraySchema(openapi_spec.RefProperty(walkerTestRef)),
+			},
+		}},
+		nested: func(s *openapi_spec.Schema) *openapi_spec.Schema {
+			inner := s.Properties["inner"]
+			return inner.Items.Schema
+		},
+	},
+	{
+		name: "allOf",
+		schema: &openapi_spec.Schema{SchemaProps: openapi_spec.SchemaProps{
+			AllOf: []openapi_spec.Schema{*openapi_spec.RefProperty(walkerTestRef)},
+		}},
+		nested: func(s *openapi_spec.Schema) *openapi_spec.Schema { return &s.AllOf[0] },
+	},
+	{
+		name: "oneOf",
+		schema: &openapi_spec.Schema{SchemaProps: openapi_spec.SchemaProps{
+			OneOf: []openapi_spec.Schema{*openapi_spec.StringProperty(), *openapi_spec.RefProperty(walkerTestRef)},
+		}},
+		nested: func(s *openapi_spec.Schema) *openapi_spec.Schema { return &s.OneOf[1] },
+	},
+	{
+		name: "anyOf inside of a map",
+		schema: mapSchema(&openapi_spec.Schema{SchemaProps: openapi_spec.SchemaProps{
+			AnyOf: []openapi_spec.Schema{*openapi_spec.RefProperty(walkerTestRef)},
+		}}),
+		nested: func(s *openapi_spec.Schema) *openapi_spec.Schema { return &s.AdditionalProperties.Schema.AnyOf[0] },
+	},
+}
+
+func TestWalkSchemaDependencies(t *testing.T) {
+	for _, shape := range walkerTestShapes {
+		schema := openapi_spec.Schema{SchemaProps: openapi_spec.SchemaProps{
+			Type:       []string{"object"},
+			Properties: map[string]openapi_spec.Schema{"field": *shape.schema},
+		}}
+
+		definition, err := NewDefinition(schema, "io.k8s.api.apps.v1.Widget", Naming{})
+		if err != nil {
+			t.Fatalf("%s: cannot generate definition: %v", shape.name, err)
+		}
+		if !definition.dependencies.Contains("apimachinery/pkg/apis/meta/v1") || definition.dependencies.Cardinality() != 1 {
+			t.Errorf("%s: wrong dependencies: %v", shape.name, definition.dependencies)
+		}
+	}
+}
+
+func TestWalkSchemaPatchRefs(t *testing.T) {
+	gitRepo := "github.com/kubewarden/k8s-objects"
+	interfaces := NewInterfaceRegistry()
+	expectedGoType := map[string]interface{}{
+		"import": map[string]string{
+			"package": "github.com/kubewarden/k8s-objects/apimachinery/pkg/apis/meta/v1",
+			"alias":   "apimachinery_pkg_apis_meta_v1",
+		},
+		"type": "LabelSelector",
+	}
+
+	for _, shape := range walkerTestShapes {
+		schema := openapi_spec.Schema{SchemaProps: openapi_spec.SchemaProps{
+			Type:       []string{"object"},
+			Properties: map[string]openapi_spec.Schema{"field": *shape.schema},
+		}}
+
+		definition, err := NewDefinition(schema, "io.k8s.api.apps.v1.Widget", Naming{})
+		if err != nil {
+			t.Fatalf("%s: cannot generate definition: %v", shape.name, err)
+		}
+		patchedSchema, err := definition.GeneratePatchedOpenAPIDef(gitRepo, &interfaces, "")
+		if err != nil {
+			t.Fatalf("%s: cannot generate patched schema: %v", shape.name, err)
+		}
+
+		field := patchedSchema.Properties["field"]
+		nested := shape.nested(&field)
+		if nested.Ref.String() != "" {
+			t.Errorf("%s: ref not replaced: %s", shape.name, nested.Ref.String())
+		}
+		if !reflect.DeepEqual(nested.Extensions["x-go-type"], expectedGoType) {
+			t.Errorf("%s: wrong x-go-type: %v", shape.name, nested.Extensions["x-go-type"])
+		}
+
+		// the original definition is left untouched
+		original := definition.SwaggerDefinition.Properties["field"]
+		if shape.nested(&original).Ref.String() != walkerTestRef {
+			t.Errorf("%s: the original definition has been altered", shape.name)
+		}
+	}
+}
+
+func TestWalkSchemaPatchSameNamespaceRefs(t *testing.T) {
+	interfaces := NewInterfaceRegistry()
+	interfaces.RegisterInterface("api/apps/v1", "Opaque")
+
+	schema := openapi_spec.Schema{SchemaProps: openapi_spec.SchemaProps{
+		Type: []string{"object"},
+		Properties: map[string]openapi_spec.Schema{
+			"matrix": *arraySchema(arraySchema(openapi_spec.RefProperty("#/definitions/io.k8s.api.apps.v1.Gadget"))),
+			"opaque": *mapSchema(arraySchema(openapi_spec.RefProperty("#/definitions/io.k8s.api.apps.v1.Opaque"))),
+		},
+	}}
+
+	definition, err := NewDefinition(schema, "io.k8s.api.apps.v1.Widget", Naming{})
+	if err != nil {
+		t.Fatalf("cannot generate definition: %v", err)
+	}
+	if definition.dependencies.Cardinality() != 0 {
+		t.Errorf("refs to the same package are not dependencies: %v", definition.dependencies)
+	}
+
+	patchedSchema, err := definition.GeneratePatchedOpenAPIDef("github.com/kubewarden/k8s-objects", &interfaces, "")
+	if err != nil {
+		t.Fatalf("cannot generate patched schema: %v", err)
+	}
+
+	matrix := patchedSchema.Properties["matrix"]
+	gadget := matrix.Items.Schema.Items.Schema
+	if gadget.Ref.String() != "#/definitions/Gadget" {
+		t.Errorf("wrong ref: %s", gadget.Ref.String())
+	}
+	gadgetNullable, _ := gadget.Extensions.GetBool("x-nullable")
+	matrixNullable, _ := matrix.Items.Schema.Extensions.GetBool("x-nullable")
+	if !gadgetNullable || matrixNullable {
+		t.Errorf("only the structs must be nullable: %v %v", gadget.Extensions, matrix.Items.Schema.Extensions)
+	}
+
+	opaque := patchedSchema.Properties["opaque"]
+	if nullable, _ := opaque.AdditionalProperties.Schema.Items.Schema.Extensions.GetBool("x-nullable"); nullable {
+		t.Errorf("interfaces must not be nullable: %v", opaque.AdditionalProperties.Schema.Items.Schema.Extensions)
+	}
+}
+
+func TestWalkSchemaLocations(t *testing.T) {
+	schema := openapi_spec.Schema{SchemaProps: openapi_spec.SchemaProps{
+		Type:     []string{"object"},
+		Required: []string{"spec"},
+		Properties: map[string]openapi_spec.Schema{
+			"spec": {SchemaProps: openapi_spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]openapi_spec.Schema{
+					"ports":   *arraySchema(openapi_spec.Int32Property()),
+					"options": *mapSchema(openapi_spec.StringProperty()),
+				},
+			}},
+			"status": {SchemaProps: openapi_spec.SchemaProps{
+				AllOf: []openapi_spec.Schema{*openapi_spec.RefProperty(walkerTestRef)},
+			}},
+		},
+	}}
+
+	type visit struct {
+		Kind     SchemaLocationKind
+		Path     string
+		Depth    int
+		Required bool
+	}
+	visits := []visit{}
+	err := WalkSchema(&schema, SchemaLocation{Required: true}, func(_ *openapi_spec.Schema, location SchemaLocation) error {
+		visits = append(visits, visit{location.Kind, location.Path, location.Depth, location.Required})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []visit{
+		{RootLocation, "", 0, true},
+		{PropertyLocation, "spec", 1, true},
+		{PropertyLocation, "spec.options", 2, false},
+		{AdditionalPropertiesLocation, "spec.options{}", 3, false},
+		{PropertyLocation, "spec.ports", 2, false},
+		{ItemsLocation, "spec.ports[]", 3, false},
+		{PropertyLocation, "status", 1, false},
+		{CompositionLocation, "status.allOf[0]", 2, true},
+	}
+	if !reflect.DeepEqual(visits, expected) {
+		t.Errorf("wrong visits:\nexpected %+v\ngot      %+v", expected, visits)
+	}
+}
@@ -0,0 +1,209 @@
+package swagger_helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/swag"
+	"github.com/pkg/errors"
+)
+
+// Strategies used when different definitions end up with the same type name
+// inside of the same package
+const (
+	// Report the duplicated types and stop
+	FAIL_DUPLICATE_TYPE_STRATEGY = "fail"
+
+	// Prefix the type names with the chunk of the ID, closest to the type
+	// name, that tells the definition apart from the others. E.g.
+	// `com.example.v1.Widget` and `org.example.v1.Widget` become `ComWidget`
+	// and `OrgWidget`
+	PREFIX_DUPLICATE_TYPE_STRATEGY = "prefix"
+)
+
+// Ensures the custom type names are exported Go type names
+func validateTypeNames(names map[string]string) error {
+	ids := []string{}
+	for id := range names {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if name := names[id]; name == "" || swag.ToGoName(name) != name {
+			return fmt.Errorf("type name %q of %s is not an exported Go type name", name, id)
+		}
+	}
+
+	return nil
+}
+
+// Reads the custom type names from a JSON file like:
+//
+//	{
+//	  "com.example.v1.Widget": "ExampleWidget"
+//	}
+func LoadTypeNames(fileName string) (map[string]string, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read type names file %s", fileName)
+	}
+
+	names := make(map[string]string)
+	if err := json.Unmarshal(data, &names); err != nil {
+		return nil, errors.Wrapf(err, "cannot decode type names file %s", fileName)
+	}
+
+	return names, nil
+}
+
+// Returns the name of the type generated for the definition, the boolean is
+// false when the name is not changed
+func (n Naming) renamedType(id string) (string, bool) {
+	if name, found := n.customTypeNames[id]; found {
+		return name, true
+	}
+	name, found := n.disambiguatedTypeNames[id]
+	return name, found
+}
+
+// Definitions that would be generated as the same Go type
+type DuplicateType struct {
+	PackageName string
+	TypeName    string
+	// IDs of the definitions, sorted
+	IDs []string
+}
+
+// Groups the definitions by the Go type they are turned into, returns the
+// groups made by more than one definition
+func (n Naming) FindDuplicateTypes(ids []string) ([]DuplicateType, error) {
+	idsByType := make(map[string][]string)
+	for _, id := range ids {
+		packageName, typeName, err := n.SplitDefinitionID(id)
+		if err != nil {
+			return nil, err
+		}
+		key := packageName + "." + typeName
+		idsByType[key] = append(idsByType[key], id)
+	}
+
+	duplicates := []DuplicateType{}
+	for key, typeIDs := range idsByType {
+		if len(typeIDs) < 2 {
+			continue
+		}
+		sort.Strings(typeIDs)
+
+		separator := strings.LastIndex(key, ".")
+		duplicates = append(duplicates, DuplicateType{
+			PackageName: key[:separator],
+			TypeName:    key[separator+1:],
+			IDs:         typeIDs,
+		})
+	}
+	sort.Slice(duplicates, func(i, j int) bool {
+		return duplicates[i].IDs[0] < duplicates[j].IDs[0]
+	})
+
+	return duplicates, nil
+}
+
+func newDuplicateTypesError(duplicates []DuplicateType) error {
+	var report strings.Builder
+
+	report.WriteString("duplicated types found, use either the ")
+	fmt.Fprintf(&report, "%s duplicate type strategy or custom type names to solve them:", PREFIX_DUPLICATE_TYPE_STRATEGY)
+	for _, duplicate := range duplicates {
+		fmt.Fprintf(&report, "\n  %s.%s: %s", duplicate.PackageName, duplicate.TypeName, strings.Join(duplicate.IDs, ", "))
+	}
+
+	return errors.New(report.String())
+}
+
+// Ensures the given definitions are turned into distinct Go types, using the
+// given duplicate type strategy. FAIL_DUPLICATE_TYPE_STRATEGY is used when
+// the strategy is empty
+func (n *Naming) resolveDuplicateTypes(ids []string, strategy string) error {
+	switch strategy {
+	case "", FAIL_DUPLICATE_TYPE_STRATEGY, PREFIX_DUPLICATE_TYPE_STRATEGY:
+	default:
+		return fmt.Errorf("unknown duplicate type strategy %q, must be either %s or %s",
+			strategy, FAIL_DUPLICATE_TYPE_STRATEGY, PREFIX_DUPLICATE_TYPE_STRATEGY)
+	}
+
+	duplicates, err := n.FindDuplicateTypes(ids)
+	if err != nil || len(duplicates) == 0 {
+		return err
+	}
+	if strategy != PREFIX_DUPLICATE_TYPE_STRATEGY {
+		return newDuplicateTypesError(duplicates)
+	}
+
+	n.disambiguatedTypeNames = make(map[string]string)
+	for _, duplicate := range duplicates {
+		for id, name := range prefixTypeNames(duplicate) {
+			n.disambiguatedTypeNames[id] = name
+		}
+	}
+
+	duplicates, err = n.FindDuplicateTypes(ids)
+	if err != nil {
+		return err
+	}
+	if len(duplicates) > 0 {
+		return errors.Wrap(newDuplicateTypesError(duplicates), "cannot disambiguate all the types")
+	}
+
+	return nil
+}
+
+// Prefixes each type name with the chunk of its ID, starting from the right,
+// that is not shared by any of the other definitions. The type name is kept
+// when the ID has no more chunks at that position
+func prefixTypeNames(duplicate DuplicateType) map[string]string {
+	namespaces := make(map[string][]string)
+	longest := 0
+	for _, id := range duplicate.IDs {
+		chunks := strings.Split(id, ".")
+		namespaces[id] = chunks[:len(chunks)-1]
+		if len(namespaces[id]) > longest {
+			longest = len(namespaces[id])
+		}
+	}
+
+	chunkAt := func(id string, offset int) string {
+		chunks := namespaces[id]
+		if offset > len(chunks) {
+			return ""
+		}
+		return chunks[len(chunks)-offset]
+	}
+
+	names := make(map[string]string)
+	for _, id := range duplicate.IDs {
+		for offset := 1; offset <= longest; offset++ {
+			chunk := chunkAt(id, offset)
+			unique := true
+			for _, other := range duplicate.IDs {
+				if other != id && chunkAt(other, offset) == chunk {
+					unique = false
+					break
+				}
+			}
+			if !unique {
+				continue
+			}
+
+			if chunk != "" {
+				names[id] = swag.ToGoName(chunk) + duplicate.TypeName
+			}
+			break
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,99 @@
+package swagger_helpers
+
+import (
+	"strings"
+	"testing"
+)
+
+var examplePackageMappingRules = []PackageMappingRule{{Regexp: `(com|org)\.example\.(.*)`, Package: "example.$2"}}
+
+func TestResolveDuplicateTypesFail(t *testing.T) {
+	ids := []string{"com.example.v1.Widget", "org.example.v1.Widget", "com.example.v1.Gadget"}
+	for _, strategy := range []string{"", FAIL_DUPLICATE_TYPE_STRATEGY} {
+		_, err := NewNaming(NamingOptions{
+			PackageMappingRules:   examplePackageMappingRules,
+			DuplicateTypeStrategy: strategy,
+		}, ids)
+		if err == nil || !strings.Contains(err.Error(), "\n  example/v1.Widget: com.example.v1.Widget, org.example.v1.Widget") {
+			t.Errorf("expected both the IDs to be reported, got %v", err)
+		}
+		if err != nil && strings.Contains(err.Error(), "Gadget") {
+			t.Errorf("only the duplicated types must be reported: %v", err)
+		}
+	}
+}
+
+func TestResolveDuplicateTypesPrefix(t *testing.T) {
+	ids := []string{
+		"io.k8s.metrics.pkg.apis.metrics.v1.NodeMetrics",
+		"io.k8s.custom-metrics.pkg.apis.metrics.v1.NodeMetrics",
+		"apis.v1.NodeMetrics",
+		"io.k8s.api.core.v1.Pod",
+	}
+	naming, err := NewNaming(NamingOptions{
+		PackageMappingRules:   []PackageMappingRule{{Regexp: `.*\.(v1)`, Package: "merged.$1"}},
+		DuplicateTypeStrategy: PREFIX_DUPLICATE_TYPE_STRATEGY,
+	}, ids)
+	if err != nil {
+		t.Fatalf("cannot resolve duplicate types: %v", err)
+	}
+
+	expected := map[string]string{
+		// the first chunk that tells them apart is the one before `pkg`
+		"io.k8s.metrics.pkg.apis.metrics.v1.NodeMetrics":        "MetricsNodeMetrics",
+		"io.k8s.custom-metrics.pkg.apis.metrics.v1.NodeMetrics": "CustomMetricsNodeMetrics",
+		"apis.v1.NodeMetrics":                                   "ApisNodeMetrics",
+		"io.k8s.api.core.v1.Pod":                                "Pod",
+	}
+	for id, expectedType := range expected {
+		packageName, typeName, err := naming.SplitDefinitionID(id)
+		if err != nil {
+			t.Errorf("cannot split %s: %v", id, err)
+			continue
+		}
+		if packageName != "merged/v1" || typeName != expectedType {
+			t.Errorf("%s: expected merged/v1.%s, got %s.%s", id, expectedType, packageName, typeName)
+		}
+	}
+}
+
+func TestResolveDuplicateTypesPrefixNotEnough(t *testing.T) {
+	// the prefixed name of the first definition is taken by the last one,
+	// the second one keeps its name since its ID has no namespace
+	_, err := NewNaming(NamingOptions{
+		PackageMappingRules:   []PackageMappingRule{{Regexp: `.*`, Package: "all"}},
+		DuplicateTypeStrategy: PREFIX_DUPLICATE_TYPE_STRATEGY,
+	}, []string{"a.Widget", "b.x.Widget", "c.AWidget"})
+	if err == nil || !strings.Contains(err.Error(), "cannot disambiguate all the types") ||
+		!strings.Contains(err.Error(), "all.AWidget: a.Widget, c.AWidget") {
+		t.Errorf("expected the remaining duplicates to be reported, got %v", err)
+	}
+}
+
+func TestResolveDuplicateTypesCustomNames(t *testing.T) {
+	naming, err := NewNaming(NamingOptions{
+		PackageMappingRules: examplePackageMappingRules,
+		TypeNames:           map[string]string{"org.example.v1.Widget": "LegacyWidget"},
+	}, []string{"com.example.v1.Widget", "org.example.v1.Widget"})
+	if err != nil {
+		t.Fatalf("the custom name must solve the collision: %v", err)
+	}
+	if _, typeName, _ := naming.SplitDefinitionID("org.example.v1.Widget"); typeName != "LegacyWidget" {
+		t.Errorf("expected the custom type name, got %s", typeName)
+	}
+	if _, typeName, _ := naming.SplitDefinitionID("com.example.v1.Widget"); typeName != "Widget" {
+		t.Errorf("the other type must keep its name, got %s", typeName)
+	}
+}
+
+func TestTypeNamesErrors(t *testing.T) {
+	_, err := NewNaming(NamingOptions{TypeNames: map[string]string{"com.example.v1.Widget": "widget"}}, nil)
+	if err == nil || !strings.Contains(err.Error(), `type name "widget" of com.example.v1.Widget is not an exported Go type name`) {
+		t.Errorf("expected the invalid name to be rejected, got %v", err)
+	}
+
+	_, err = NewNaming(NamingOptions{DuplicateTypeStrategy: "ignore"}, nil)
+	if err == nil || !strings.Contains(err.Error(), `unknown duplicate type strategy "ignore"`) {
+		t.Errorf("expected unknown strategies to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package swagger_helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/go-openapi/swag"
+	"github.com/pkg/errors"
+)
+
+// Interfaces that can be implemented by the Go types of the overrides to
+// handle JSON. easyjson supports all of them, the first one is the fastest
+const (
+	EASYJSON_MARSHALER = "easyjson"
+	JSON_MARSHALER     = "json"
+	TEXT_MARSHALER     = "text"
+)
+
+// Shapes of the types of the overrides, they decide how the generated code
+// copies, compares and diffs the values
+const (
+	// Values compared with `==` and copied by assignment, like a struct made
+	// of scalars. The default
+	SCALAR_OVERRIDE = "scalar"
+	// Structs declaring their own DeepCopyInto and Equal methods
+	STRUCT_OVERRIDE = "struct"
+	// Any other type, e.g. one holding slices or maps: the values are copied
+	// and compared through their JSON representation
+	OPAQUE_OVERRIDE = "opaque"
+)
+
+// Replaces the Go type generated for a definition with another one
+type TypeOverride struct {
+	// Import path of the package defining the type, e.g.
+	// `github.com/example/types`. When empty, the type is written by the
+	// generator inside of the package of the definition, like IntOrString
+	Import string `json:"import,omitempty"`
+
+	// Name of the type, e.g. `Time`
+	Type string `json:"type"`
+
+	// Optional, the interface implemented by the type to handle JSON:
+	// `easyjson` (the default), `json` or `text`. This is checked at
+	// compile time
+	Marshaler string `json:"marshaler,omitempty"`
+
+	// Optional, the shape of the type: `scalar` (the default), `struct` or
+	// `opaque`
+	Kind string `json:"kind,omitempty"`
+
+	// Optional, set when the type declares a `DeepCopyInto(out *T)` method.
+	// Otherwise scalars are copied by assignment, hence they must not share
+	// memory with their copies, and opaque values are encoded and decoded.
+	// Required by structs
+	DeepCopy bool `json:"deepCopy,omitempty"`
+
+	// Optional, set when the type declares an `Equal(other *T) bool`
+	// method. Otherwise scalars are compared with `==`, hence they must be
+	// comparable, and opaque values are compared through their encoding.
+	// This is checked at compile time. Required by structs
+	Equal bool `json:"equal,omitempty"`
+}
+
+// Type overrides, indexed by the ID of the definition they replace, e.g.
+// `io.k8s.apimachinery.pkg.apis.meta.v1.Time`
+type TypeOverrides map[string]TypeOverride
+
+// Reads the type overrides from a JSON file like:
+//
+//	{
+//	  "io.k8s.apimachinery.pkg.apis.meta.v1.Time": {
+//	    "import": "github.com/example/types",
+//	    "type": "Time",
+//	    "marshaler": "json"
+//	  }
+//	}
+func LoadTypeOverrides(fileName string) (TypeOverrides, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read type overrides file %s", fileName)
+	}
+
+	overrides := TypeOverrides{}
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, errors.Wrapf(err, "cannot decode type overrides file %s", fileName)
+	}
+
+	for _, id := range overrides.IDs() {
+		if err := overrides[id].Validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid override of %s", id)
+		}
+	}
+
+	return overrides, nil
+}
+
+// Returns the IDs of the overridden definitions, sorted
+func (o TypeOverrides) IDs() []string {
+	ids := []string{}
+	for id := range o {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+func (o TypeOverride) Validate() error {
+	if o.Type == "" || swag.ToGoName(o.Type) != o.Type {
+		return fmt.Errorf("%q is not an exported Go type name", o.Type)
+	}
+
+	switch o.Marshaler {
+	case "", EASYJSON_MARSHALER, JSON_MARSHALER, TEXT_MARSHALER:
+	default:
+		return fmt.Errorf("unknown marshaler %q, must be one of %s, %s or %s",
+			o.Marshaler, EASYJSON_MARSHALER, JSON_MARSHALER, TEXT_MARSHALER)
+	}
+
+	switch o.Kind {
+	case "", SCALAR_OVERRIDE, OPAQUE_OVERRIDE:
+	case STRUCT_OVERRIDE:
+		if !o.DeepCopy || !o.Equal {
+			return fmt.Errorf("the fields of %s are not known, the struct must declare both the DeepCopyInto and the Equal methods", o.Type)
+		}
+	default:
+		return fmt.Errorf("unknown kind %q, must be one of %s, %s or %s",
+			o.Kind, SCALAR_OVERRIDE, STRUCT_OVERRIDE, OPAQUE_OVERRIDE)
+	}
+
+	return nil
+}
+
+// Returns the import path of the package defining the type
+// * `packageName`: the package of the overridden definition
+func (o TypeOverride) ImportPath(gitRepo, packageName string) string {
+	if o.Import == "" {
+		return filepath.Join(gitRepo, packageName)
+	}
+	return o.Import
+}
+
+// Returns the alias used to import the package defining the type from the
+// generated code
+func (o TypeOverride) ImportAlias(naming Naming, packageName string) string {
+	if o.Import == "" {
+		return naming.PackageAlias(packageName)
+	}
+	return naming.PackageAlias(o.Import)
+}
+
+// Convert the override into a swagger x-go-type interface
+func (o TypeOverride) ToMap(naming Naming, gitRepo, packageName string) map[string]interface{} {
+	outerObj := make(map[string]interface{})
+
+	importObj := make(map[string]string)
+	importObj["package"] = o.ImportPath(gitRepo, packageName)
+	importObj["alias"] = o.ImportAlias(naming, packageName)
+
+	outerObj["import"] = importObj
+	outerObj["type"] = o.Type
+
+	return outerObj
+}
@@ -0,0 +1,140 @@
+package swagger_helpers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	openapi_spec "github.com/go-openapi/spec"
+)
+
+func TestLoadTypeOverrides(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "overrides.json")
+	data := `{
+		"io.k8s.apimachinery.pkg.apis.meta.v1.Time": {
+			"import": "github.com/example/types",
+			"type": "Time",
+			"marshaler": "json"
+		}
+	}`
+	if err := os.WriteFile(fileName, []byte(data), 0644); err != nil {
+		t.Fatalf("cannot write overrides file: %v", err)
+	}
+
+	overrides, err := LoadTypeOverrides(fileName)
+	if err != nil {
+		t.Fatalf("cannot load overrides: %v", err)
+	}
+	expected := TypeOverride{Import: "github.com/example/types", Type: "Time", Marshaler: JSON_MARSHALER}
+	if overrides["io.k8s.apimachinery.pkg.apis.meta.v1.Time"] != expected {
+		t.Errorf("wrong overrides: %+v", overrides)
+	}
+}
+
+func TestValidateTypeOverride(t *testing.T) {
+	cases := []struct {
+		override TypeOverride
+		valid    bool
+	}{
+		{TypeOverride{Import: "github.com/example/types", Type: "Time"}, true},
+		{TypeOverride{Import: "github.com/example/types", Type: "Time", Marshaler: TEXT_MARSHALER}, true},
+		{TypeOverride{Import: "github.com/example/types", Type: ""}, false},
+		{TypeOverride{Import: "github.com/example/types", Type: "time"}, false},
+		{TypeOverride{Import: "github.com/example/types", Type: "Time", Marshaler: "yaml"}, false},
+		{TypeOverride{Import: "github.com/example/types", Type: "Time", Kind: STRUCT_OVERRIDE, DeepCopy: true, Equal: true}, true},
+		{TypeOverride{Import: "github.com/example/types", Type: "Time", Kind: STRUCT_OVERRIDE, Equal: true}, false},
+		{TypeOverride{Import: "github.com/example/types", Type: "Selector", Kind: OPAQUE_OVERRIDE}, true},
+		{TypeOverride{Import: "github.com/example/types", Type: "Selector", Kind: "map"}, false},
+	}
+
+	for _, c := range cases {
+		if err := c.override.Validate(); (err == nil) != c.valid {
+			t.Errorf("%+v: expected valid to be %v, got error %v", c.override, c.valid, err)
+		}
+	}
+}
+
+func TestPatchSchemaTypeOverride(t *testing.T) {
+	gitRepo := "github.com/kubewarden/k8s-objects"
+	timeID := "io.k8s.apimachinery.pkg.apis.meta.v1.Time"
+	definitions := newTestDefinitions(t, map[string]openapi_spec.Schema{
+		// objects without properties are interfaces, unless overridden
+		timeID: {SchemaProps: openapi_spec.SchemaProps{Type: []string{"object"}}},
+		"io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta": {SchemaProps: openapi_spec.SchemaProps{
+			Properties: map[string]openapi_spec.Schema{
+				"creationTimestamp": *openapi_spec.RefProperty("#/definitions/" + timeID),
+			},
+		}},
+		"io.k8s.api.core.v1.ContainerStateRunning": {SchemaProps: openapi_spec.SchemaProps{
+			Properties: map[string]openapi_spec.Schema{
+				"startedAt": *openapi_spec.RefProperty("#/definitions/" + timeID),
+			},
+		}},
+	})
+
+	interfaces := NewInterfaceRegistry()
+	interfaces.RegisterInterface("apimachinery/pkg/apis/meta/v1", "Time")
+	interfaces.RegisterOverride("apimachinery/pkg/apis/meta/v1", "Time",
+		TypeOverride{Import: "github.com/example/types", Type: "Time"})
+	if interfaces.IsInterface(gitRepo, "apimachinery/pkg/apis/meta/v1", "Time") {
+		t.Error("overrides must take precedence over interfaces")
+	}
+	if imports := interfaces.OverrideImports(); len(imports) != 1 || imports[0] != "github.com/example/types" {
+		t.Errorf("wrong override imports: %v", imports)
+	}
+
+	expectedGoType := map[string]interface{}{
+		"import": map[string]string{"package": "github.com/example/types", "alias": "github_com_example_types"},
+		"type":   "Time",
+	}
+
+	patchedTime, err := definitions[timeID].GeneratePatchedOpenAPIDef(gitRepo, &interfaces, "")
+	if err != nil {
+		t.Fatalf("cannot generate patched schema: %v", err)
+	}
+	if goType := patchedTime.Extensions["x-go-type"]; !equalGoTypeExtensions(goType, expectedGoType) {
+		t.Errorf("wrong x-go-type of the overridden definition: %v", goType)
+	}
+
+	for _, ref := range []struct{ id, property string }{
+		// same package
+		{"io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta", "creationTimestamp"},
+		// another package
+		{"io.k8s.api.core.v1.ContainerStateRunning", "startedAt"},
+	} {
+		patchedSchema, err := definitions[ref.id].GeneratePatchedOpenAPIDef(gitRepo, &interfaces, "")
+		if err != nil {
+			t.Fatalf("cannot generate patched schema: %v", err)
+		}
+		property := patchedSchema.Properties[ref.property]
+		if !equalGoTypeExtensions(property.Extensions["x-go-type"], expectedGoType) {
+			t.Errorf("%s: wrong x-go-type: %v", ref.id, property.Extensions)
+		}
+		if pointer := property.Ref.GetPointer(); pointer != nil && !pointer.IsEmpty() {
+			t.Errorf("%s: the ref must be removed: %s", ref.id, property.Ref.String())
+		}
+		if nullable, _ := property.Extensions.GetBool("x-nullable"); !nullable {
+			t.Errorf("%s: overridden types must be referenced by pointer", ref.id)
+		}
+	}
+
+	resolver := NewGoTypeResolver(definitions, &interfaces, gitRepo)
+	goType, err := resolver.PropertyType(definitions["io.k8s.api.core.v1.ContainerStateRunning"], "startedAt")
+	if err != nil {
+		t.Fatalf("cannot resolve type: %v", err)
+	}
+	if goType.Expr() != "*github_com_example_types.Time" || goType.Kind != ScalarGoType ||
+		goType.Import != "github.com/example/types" || goType.Zero != "*new(github_com_example_types.Time)" {
+		t.Errorf("wrong type: %+v", goType)
+	}
+}
+
+func equalGoTypeExtensions(actual interface{}, expected map[string]interface{}) bool {
+	goType, ok := actual.(map[string]interface{})
+	if !ok || goType["type"] != expected["type"] {
+		return false
+	}
+	actualImport, _ := goType["import"].(map[string]string)
+	expectedImport := expected["import"].(map[string]string)
+	return actualImport["package"] == expectedImport["package"] && actualImport["alias"] == expectedImport["alias"]
+}